@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+)
+
+// AbandonmentStat is statistics of PRs closed without merge. Times are from PR
+// creation to close. Statistics are always output so that the JSON fields are stable.
+type AbandonmentStat struct {
+	// ClosedPR is number of PRs closed with or without merge.
+	ClosedPR int `json:"closed_pr"`
+	// AbandonedPR is number of PRs closed without merge.
+	AbandonedPR int `json:"abandoned_pr"`
+	// Rate is percentage of abandoned PRs in closed PRs.
+	Rate        float64 `json:"rate"`
+	TimeMaximum int     `json:"time_maximum"`
+	TimeMinimum int     `json:"time_minimum"`
+	TimeAverage float64 `json:"time_average"`
+	TimeMedian  float64 `json:"time_median"`
+	TimeP75     float64 `json:"time_p75"`
+	TimeP90     float64 `json:"time_p90"`
+	TimeP95     float64 `json:"time_p95"`
+	TimeP99     float64 `json:"time_p99"`
+}
+
+// newAbandonmentStat calculate abandonment statistics of closed PRs.
+// Abandoned PRs without creation date are counted, but they have no time to abandon.
+func newAbandonmentStat(closed []*usecase.PullRequest) *AbandonmentStat {
+	stat := &AbandonmentStat{ClosedPR: len(closed)}
+	times := make([]int, 0)
+	for _, v := range closed {
+		if v.Status != usecase.PRStatusClosedUnmerged {
+			continue
+		}
+		stat.AbandonedPR++
+		if v.TimeToAbandonMinutes != nil {
+			times = append(times, *v.TimeToAbandonMinutes)
+		}
+	}
+	if stat.ClosedPR != 0 {
+		stat.Rate = float64(stat.AbandonedPR) / float64(stat.ClosedPR) * 100
+	}
+
+	stat.TimeMaximum = maximum(times)
+	stat.TimeMinimum = minimum(times)
+	stat.TimeAverage = average(times)
+	stat.TimeMedian = median(times)
+	stat.TimeP75 = percentile(times, 75)
+	stat.TimeP90 = percentile(times, 90)
+	stat.TimeP95 = percentile(times, 95)
+	stat.TimeP99 = percentile(times, 99)
+	return stat
+}
+
+// abandonmentItem is abandonment statistic with display name and formatted value.
+type abandonmentItem struct {
+	name  string
+	value string
+}
+
+// items return abandonment statistics in display order.
+func (a *AbandonmentStat) items() []abandonmentItem {
+	return []abandonmentItem{
+		{name: "Closed PR", value: fmt.Sprintf("%d", a.ClosedPR)},
+		{name: "Abandoned PR", value: fmt.Sprintf("%d", a.AbandonedPR)},
+		{name: "Abandonment Rate", value: fmt.Sprintf("%.2f[%%]", a.Rate)},
+		{name: "Time to Abandon(Max)", value: fmt.Sprintf("%d[min]", a.TimeMaximum)},
+		{name: "Time to Abandon(Min)", value: fmt.Sprintf("%d[min]", a.TimeMinimum)},
+		{name: "Time to Abandon(Ave)", value: fmt.Sprintf("%.2f[min]", a.TimeAverage)},
+		{name: "Time to Abandon(Median)", value: fmt.Sprintf("%.2f[min]", a.TimeMedian)},
+		{name: "Time to Abandon(P75)", value: fmt.Sprintf("%.2f[min]", a.TimeP75)},
+		{name: "Time to Abandon(P90)", value: fmt.Sprintf("%.2f[min]", a.TimeP90)},
+		{name: "Time to Abandon(P95)", value: fmt.Sprintf("%.2f[min]", a.TimeP95)},
+		{name: "Time to Abandon(P99)", value: fmt.Sprintf("%.2f[min]", a.TimeP99)},
+	}
+}
+
+// summaryColumns return abandonment statistics as summary csv columns prefixed with "abandonment_".
+func (a *AbandonmentStat) summaryColumns() []summaryColumn {
+	return []summaryColumn{
+		{name: "abandonment_closed_pr", value: float64(a.ClosedPR), integer: true, unit: unitPullRequests},
+		{name: "abandonment_abandoned_pr", value: float64(a.AbandonedPR), integer: true, unit: unitPullRequests},
+		{name: "abandonment_rate", value: a.Rate, unit: unitPercent},
+		{name: "abandonment_time_maximum", value: float64(a.TimeMaximum), integer: true, unit: unitMinutes},
+		{name: "abandonment_time_minimum", value: float64(a.TimeMinimum), integer: true, unit: unitMinutes},
+		{name: "abandonment_time_average", value: a.TimeAverage, unit: unitMinutes},
+		{name: "abandonment_time_median", value: a.TimeMedian, unit: unitMinutes},
+		{name: "abandonment_time_p75", value: a.TimeP75, unit: unitMinutes},
+		{name: "abandonment_time_p90", value: a.TimeP90, unit: unitMinutes},
+		{name: "abandonment_time_p95", value: a.TimeP95, unit: unitMinutes},
+		{name: "abandonment_time_p99", value: a.TimeP99, unit: unitMinutes},
+	}
+}
+
+// removeUnmergedPR remove PRs closed without merge.
+func (dlts *DetailLeadTimeStat) removeUnmergedPR() {
+	prs := make([]*usecase.PullRequest, 0, len(dlts.PullRequests))
+	for _, v := range dlts.PullRequests {
+		if v.Status == usecase.PRStatusClosedUnmerged {
+			continue
+		}
+		prs = append(prs, v)
+	}
+	dlts.PullRequests = prs
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/shogo82148/pointer"
+)
+
+func Test_newAbandonmentStat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Calculate rate and time to abandon of PRs closed without merge", func(t *testing.T) {
+		t.Parallel()
+
+		closed := []*usecase.PullRequest{
+			{Number: 1, Status: usecase.PRStatusMerged, TimeToMergeMinutes: pointer.Int(5)},
+			{Number: 2, Status: usecase.PRStatusClosedUnmerged, TimeToAbandonMinutes: pointer.Int(10)},
+			{Number: 3, Status: usecase.PRStatusMerged},
+			{Number: 4, Status: usecase.PRStatusClosedUnmerged, TimeToAbandonMinutes: pointer.Int(30)},
+			// Abandoned PR without creation date is counted, but it has no time to abandon.
+			{Number: 5, Status: usecase.PRStatusClosedUnmerged},
+		}
+
+		got := newAbandonmentStat(closed)
+		want := &AbandonmentStat{
+			ClosedPR:    5,
+			AbandonedPR: 3,
+			Rate:        60,
+			TimeMaximum: 30,
+			TimeMinimum: 10,
+			TimeAverage: 20,
+			TimeMedian:  20,
+			TimeP75:     percentile([]int{10, 30}, 75),
+			TimeP90:     percentile([]int{10, 30}, 90),
+			TimeP95:     percentile([]int{10, 30}, 95),
+			TimeP99:     percentile([]int{10, 30}, 99),
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("No closed PR", func(t *testing.T) {
+		t.Parallel()
+
+		if diff := cmp.Diff(&AbandonmentStat{}, newAbandonmentStat(nil)); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestDetailLeadTimeStat_removeUnmergedPR(t *testing.T) {
+	t.Parallel()
+
+	dlts := &DetailLeadTimeStat{PullRequests: []*usecase.PullRequest{
+		{Number: 1, Status: usecase.PRStatusMerged},
+		{Number: 2, Status: usecase.PRStatusClosedUnmerged},
+		{Number: 3, Status: usecase.PRStatusOpen},
+	}}
+
+	dlts.removeUnmergedPR()
+	if diff := cmp.Diff([]int{1, 3}, prNumbers(dlts.PullRequests)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nao1215/leadtime/config"
+	"github.com/nao1215/leadtime/infrastructure/cache"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd() *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect, prune and clear the on-disk cache of PRs",
+		Long: `Inspect, prune and clear the on-disk cache of PRs.
+leadtime caches PRs, first commits and reviews when stat runs with --cache.
+The cache directory is $LT_CACHE_DIR or leadtime directory under the user cache directory.`,
+	}
+	cacheCmd.AddCommand(newCacheListCmd())
+	cacheCmd.AddCommand(newCachePruneCmd())
+	cacheCmd.AddCommand(newCacheClearCmd())
+
+	return cacheCmd
+}
+
+func newCacheListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Print cached repositories",
+		RunE:  cacheList,
+	}
+}
+
+func newCachePruneCmd() *cobra.Command {
+	pruneCmd := &cobra.Command{
+		Use:     "prune",
+		Short:   "Remove repositories not synchronized for a while from the cache",
+		Example: "  leadtime cache prune --older-than=30d",
+		RunE:    cachePrune,
+	}
+	pruneCmd.Flags().String("older-than", "30d", "Remove repositories not synchronized within this duration (e.g. 30d, 2w, 12h)")
+
+	return pruneCmd
+}
+
+func newCacheClearCmd() *cobra.Command {
+	clearCmd := &cobra.Command{
+		Use:     "clear",
+		Short:   "Remove cached data. Without flags, all cached data is removed",
+		Example: "  leadtime cache clear --owner=nao1215 --repo=sqly",
+		RunE:    cacheClear,
+	}
+	clearCmd.Flags().StringP("owner", "o", "", "Specify GitHub owner name")
+	clearCmd.Flags().StringP("repo", "r", "", "Specify GitHub repository name")
+
+	return clearCmd
+}
+
+func cacheList(cmd *cobra.Command, args []string) error {
+	cacheConfig, err := config.NewCacheConfig()
+	if err != nil {
+		return err
+	}
+
+	infos, err := cache.List(cacheConfig.Dir)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	fmt.Fprintf(w, "cache directory: %s\n", cacheConfig.Dir)
+	if len(infos) == 0 {
+		fmt.Fprintln(w, "no cached repository")
+		return nil
+	}
+	fmt.Fprintf(w, "Host\tRepository\tPRs\tSize[KB]\tSynced At\n")
+	for _, v := range infos {
+		fmt.Fprintf(w, "%s\t%s/%s\t%d\t%.1f\t%s\n",
+			v.Host, v.Owner, v.Repository, v.PullRequests, float64(v.Size)/1024, v.SyncedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func cachePrune(cmd *cobra.Command, args []string) error {
+	olderThan, err := cmd.Flags().GetString("older-than")
+	if err != nil {
+		return err
+	}
+	d, err := parseDuration(olderThan)
+	if err != nil {
+		return err
+	}
+
+	cacheConfig, err := config.NewCacheConfig()
+	if err != nil {
+		return err
+	}
+
+	removed, err := cache.Prune(cacheConfig.Dir, time.Now().Add(-d))
+	if err != nil {
+		return err
+	}
+	for _, v := range removed {
+		fmt.Fprintf(cmd.OutOrStdout(), "removed %s/%s (synced at %s)\n", v.Owner, v.Repository, v.SyncedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func cacheClear(cmd *cobra.Command, args []string) error {
+	owner, err := cmd.Flags().GetString("owner")
+	if err != nil {
+		return err
+	}
+	repo, err := cmd.Flags().GetString("repo")
+	if err != nil {
+		return err
+	}
+
+	cacheConfig, err := config.NewCacheConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := cache.Clear(cacheConfig.Dir, owner, repo); err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "cleared cache")
+	return nil
+}
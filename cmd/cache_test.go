@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nao1215/leadtime/infrastructure/cache"
+)
+
+// writeCache write cache of one repository with one PR under root/github.com.
+func writeCache(t *testing.T, root, owner, repo string, syncedAt time.Time) {
+	t.Helper()
+
+	dir := filepath.Join(root, "github.com", owner, repo)
+	if err := os.MkdirAll(filepath.Join(dir, "pulls"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	sync, err := json.Marshal(map[string]interface{}{
+		"version":    3,
+		"owner":      owner,
+		"repository": repo,
+		"synced_at":  syncedAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sync.json"), sync, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pulls", "1.json"), []byte(`{"pull_request":{"number":1}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// executeCacheCmd run cache subcommand with args and return its output.
+func executeCacheCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	cmd := newCacheCmd()
+	cmd.SetOut(&buf)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func Test_cacheList(t *testing.T) {
+	t.Run("Print no cached repository", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv("LT_CACHE_DIR", root)
+
+		got, err := executeCacheCmd(t, "list")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "cache directory: " + root + "\nno cached repository\n"
+		if got != want {
+			t.Errorf("mismatch want=%v, got=%v", want, got)
+		}
+	})
+
+	t.Run("Print cached repositories", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv("LT_CACHE_DIR", root)
+		writeCache(t, root, "nao1215", "sqly", time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC))
+
+		got, err := executeCacheCmd(t, "list")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "github.com\tnao1215/sqly\t1\t"
+		if !strings.Contains(got, want) || !strings.HasSuffix(got, "\t2023-03-01T00:00:00Z\n") {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	})
+}
+
+func Test_cachePrune(t *testing.T) {
+	t.Run("Remove repositories synchronized before the duration", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv("LT_CACHE_DIR", root)
+		old := time.Now().Add(-60 * 24 * time.Hour).UTC().Truncate(time.Second)
+		writeCache(t, root, "nao1215", "sqly", old)
+		writeCache(t, root, "nao1215", "gup", time.Now())
+
+		got, err := executeCacheCmd(t, "prune", "--older-than=30d")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "removed nao1215/sqly (synced at " + old.Format(time.RFC3339) + ")\n"
+		if got != want {
+			t.Errorf("mismatch want=%v, got=%v", want, got)
+		}
+
+		infos, err := cache.List(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(infos) != 1 || infos[0].Repository != "gup" {
+			t.Errorf("unexpected cached repositories: %+v", infos)
+		}
+	})
+
+	t.Run("Accept duration in time.ParseDuration format", func(t *testing.T) {
+		t.Setenv("LT_CACHE_DIR", t.TempDir())
+
+		if _, err := executeCacheCmd(t, "prune", "--older-than=12h"); err != nil {
+			t.Errorf("mismatch want=%v, got=%v", nil, err)
+		}
+	})
+
+	for _, v := range []string{"abc", "-1d", "-12h", "1.5w"} {
+		v := v
+		t.Run("Reject invalid duration "+v, func(t *testing.T) {
+			t.Setenv("LT_CACHE_DIR", t.TempDir())
+
+			if _, err := executeCacheCmd(t, "prune", "--older-than="+v); !errors.Is(err, ErrInvalidDuration) {
+				t.Errorf("mismatch want=%v, got=%v", ErrInvalidDuration, err)
+			}
+		})
+	}
+}
+
+func Test_cacheClear(t *testing.T) {
+	t.Run("Reject repository without owner", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv("LT_CACHE_DIR", root)
+		writeCache(t, root, "nao1215", "sqly", time.Now())
+
+		if _, err := executeCacheCmd(t, "clear", "--repo=sqly"); !errors.Is(err, cache.ErrNoOwner) {
+			t.Errorf("mismatch want=%v, got=%v", cache.ErrNoOwner, err)
+		}
+		infos, err := cache.List(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(infos) != 1 {
+			t.Errorf("cache is removed: %+v", infos)
+		}
+	})
+
+	t.Run("Remove repositories of the owner", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv("LT_CACHE_DIR", root)
+		writeCache(t, root, "nao1215", "sqly", time.Now())
+		writeCache(t, root, "nao1215", "gup", time.Now())
+		writeCache(t, root, "alice", "tool", time.Now())
+
+		got, err := executeCacheCmd(t, "clear", "--owner=nao1215")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "cleared cache\n" {
+			t.Errorf("mismatch want=%v, got=%v", "cleared cache\n", got)
+		}
+
+		infos, err := cache.List(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(infos) != 1 || infos[0].Owner != "alice" {
+			t.Errorf("unexpected cached repositories: %+v", infos)
+		}
+	})
+
+	t.Run("Remove only one repository", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv("LT_CACHE_DIR", root)
+		writeCache(t, root, "nao1215", "sqly", time.Now())
+		writeCache(t, root, "nao1215", "gup", time.Now())
+
+		if _, err := executeCacheCmd(t, "clear", "--owner=nao1215", "--repo=sqly"); err != nil {
+			t.Fatal(err)
+		}
+
+		infos, err := cache.List(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(infos) != 1 || infos[0].Repository != "gup" {
+			t.Errorf("unexpected cached repositories: %+v", infos)
+		}
+	})
+}
@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+const (
+	// maxHistogramBins is maximum number of bins in lead time histogram.
+	maxHistogramBins = 30
+	// maxAuthorsInChart is maximum number of authors in per-author chart.
+	maxAuthorsInChart = 20
+	// defaultChartName is file name of chart without extension.
+	defaultChartName = "leadtime"
+	// trendChartSuffix is suffix of trend chart file name drawn with --group-by=week|month.
+	trendChartSuffix = "_trend"
+)
+
+// chartType is the kind of chart drawn by drawGraph.
+type chartType string
+
+const (
+	// chartTypeLine means line graph of lead time of each PR number
+	chartTypeLine chartType = "line"
+	// chartTypeHistogram means histogram of lead time
+	chartTypeHistogram chartType = "histogram"
+	// chartTypeBoxAuthor means box plot of lead time of each author
+	chartTypeBoxAuthor chartType = "box-author"
+	// chartTypeScatterSize means scatter plot of lead time against PR size
+	chartTypeScatterSize chartType = "scatter-size"
+	// chartTypeCumulative means cumulative number of merged PRs over time
+	chartTypeCumulative chartType = "cumulative"
+)
+
+// valid check whether chart type is supported or not.
+func (c chartType) valid() bool {
+	switch c {
+	case chartTypeLine, chartTypeHistogram, chartTypeBoxAuthor, chartTypeScatterSize, chartTypeCumulative:
+		return true
+	default:
+		return false
+	}
+}
+
+// validChartFormat check whether gonum/plot can write chart in the format or not.
+func validChartFormat(format string) bool {
+	switch format {
+	case "png", "svg", "pdf":
+		return true
+	default:
+		return false
+	}
+}
+
+// chartPath return path of chart file. If --chart-output is not specified,
+// return leadtime.<format> in the current directory.
+func (o *option) chartPath() string {
+	if o.chartOutput != "" {
+		return o.chartOutput
+	}
+	return defaultChartName + "." + o.chartFormat
+}
+
+// trendChartPath return path of trend chart file. It is the chart path with
+// "_trend" before the extension (e.g. leadtime_trend.png, doc/chart_trend.svg).
+func (o *option) trendChartPath() string {
+	path := o.chartPath()
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + trendChartSuffix + ext
+}
+
+// chartLink return link to the chart file for markdown.
+func (o *option) chartLink() string {
+	return markdownLink(o.chartPath())
+}
+
+// trendChartLink return link to the trend chart file for markdown.
+func (o *option) trendChartLink() string {
+	return markdownLink(o.trendChartPath())
+}
+
+// markdownLink return link to the file for markdown. Relative path starts with "./".
+func markdownLink(path string) string {
+	path = filepath.ToSlash(path)
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") {
+		return path
+	}
+	return "./" + path
+}
+
+// drawGraph draw the chart specified by --chart-type and write it to the chart file.
+func (dlts *DetailLeadTimeStat) drawGraph(opt *option) error {
+	p, err := dlts.chartPlot(opt.chartType)
+	if err != nil {
+		return err
+	}
+	return writeChart(p, opt, opt.chartPath())
+}
+
+// writeChart write the chart to path in the format and size specified by option.
+// The existing file is overwritten, so that the same command can be run repeatedly.
+func writeChart(p *plot.Plot, opt *option, path string) error {
+	writerTo, err := p.WriterTo(opt.chartWidth, opt.chartHeight, opt.chartFormat)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	if _, err := writerTo.WriteTo(f); err != nil {
+		f.Close() //nolint
+		return err
+	}
+	return f.Close()
+}
+
+// chartPlot return the chart of chart type.
+func (dlts *DetailLeadTimeStat) chartPlot(c chartType) (*plot.Plot, error) {
+	switch c {
+	case chartTypeHistogram:
+		return dlts.histogramPlot()
+	case chartTypeBoxAuthor:
+		return dlts.authorBoxPlot()
+	case chartTypeScatterSize:
+		return dlts.sizeScatterPlot()
+	case chartTypeCumulative:
+		return dlts.cumulativePlot()
+	case chartTypeLine:
+	}
+	return dlts.leadTimePlot()
+}
+
+// leadTimePlot return line graph of lead time of each PR.
+func (dlts *DetailLeadTimeStat) leadTimePlot() (*plot.Plot, error) {
+	p := plot.New()
+	p.X.Label.Text = "PR number"
+	p.Y.Label.Text = "Lead Time[min]"
+
+	data := make(plotter.XYs, 0, len(dlts.PullRequests))
+	for _, v := range dlts.PullRequests {
+		data = append(data, plotter.XY{
+			X: float64(v.Number),
+			Y: float64(v.MergeTimeMinutes),
+		})
+	}
+
+	line, err := plotter.NewLine(data)
+	if err != nil {
+		return nil, err
+	}
+	p.Add(plotter.NewGrid())
+	p.Y.Max = float64(dlts.max()) + 100
+	line.Color = color.RGBA{R: 226, G: 45, B: 60, A: 255}
+	line.Width = vg.Points(1.5)
+	p.Add(line)
+	return p, nil
+}
+
+// histogramPlot return histogram of lead time. Number of bins is square root of number of PRs.
+func (dlts *DetailLeadTimeStat) histogramPlot() (*plot.Plot, error) {
+	values := make(plotter.Values, 0, len(dlts.PullRequests))
+	for _, v := range dlts.leadTimes() {
+		values = append(values, float64(v))
+	}
+
+	p := plot.New()
+	p.X.Label.Text = "Lead Time[min]"
+	p.Y.Label.Text = "PRs"
+	p.Add(plotter.NewGrid())
+	if len(values) == 0 {
+		return p, nil
+	}
+
+	bins := int(math.Ceil(math.Sqrt(float64(len(values)))))
+	if bins > maxHistogramBins {
+		bins = maxHistogramBins
+	}
+
+	hist, err := plotter.NewHist(values, bins)
+	if err != nil {
+		return nil, err
+	}
+	hist.FillColor = color.RGBA{R: 226, G: 45, B: 60, A: 255}
+	hist.LineStyle.Color = color.White
+	p.Add(hist)
+	return p, nil
+}
+
+// authorPlot return bar chart of average lead time of each author.
+// Authors are sorted by number of PRs, and only top authors are drawn.
+func (dlts *DetailLeadTimeStat) authorPlot() (*plot.Plot, error) {
+	stats := newGroupStats(topAuthorGroups(dlts.PullRequests))
+
+	names := make([]string, 0, len(stats))
+	values := make(plotter.Values, 0, len(stats))
+	for _, v := range stats {
+		names = append(names, fmt.Sprintf("%s (%d)", v.Group, v.TotalPR))
+		values = append(values, v.LeadTimeAverage)
+	}
+
+	p := plot.New()
+	p.Y.Label.Text = "Average Lead Time[min]"
+	p.Add(plotter.NewGrid())
+	p.NominalX(names...)
+	p.X.Tick.Label.Rotation = 0.8
+	p.X.Tick.Label.XAlign = -0.8
+
+	bar, err := plotter.NewBarChart(values, vg.Points(12))
+	if err != nil {
+		return nil, err
+	}
+	bar.Color = color.RGBA{R: 45, G: 110, B: 226, A: 255}
+	bar.LineStyle.Width = 0
+	p.Add(bar)
+	return p, nil
+}
+
+// authorBoxPlot return box plot of lead time of each author.
+// Authors are sorted by number of PRs, and only top authors are drawn.
+func (dlts *DetailLeadTimeStat) authorBoxPlot() (*plot.Plot, error) {
+	groups := topAuthorGroups(dlts.PullRequests)
+
+	p := plot.New()
+	p.Y.Label.Text = "Lead Time[min]"
+	p.Add(plotter.NewGrid())
+
+	names := make([]string, 0, len(groups))
+	for i, g := range groups {
+		values := make(plotter.Values, 0, len(g.prs))
+		for _, v := range g.prs {
+			values = append(values, float64(v.MergeTimeMinutes))
+		}
+		box, err := plotter.NewBoxPlot(vg.Points(12), float64(i), values)
+		if err != nil {
+			return nil, err
+		}
+		box.FillColor = color.RGBA{R: 45, G: 110, B: 226, A: 128}
+		p.Add(box)
+		names = append(names, fmt.Sprintf("%s (%d)", g.name, len(g.prs)))
+	}
+	p.NominalX(names...)
+	p.X.Tick.Label.Rotation = 0.8
+	p.X.Tick.Label.XAlign = -0.8
+	return p, nil
+}
+
+// sizeScatterPlot return scatter plot of lead time against PR size (added and deleted lines).
+// PRs whose size is not provided by the source are not drawn. If no PR has size, return error.
+func (dlts *DetailLeadTimeStat) sizeScatterPlot() (*plot.Plot, error) {
+	data := make(plotter.XYs, 0, len(dlts.PullRequests))
+	for _, v := range dlts.PullRequests {
+		if v.Additions == nil || v.Deletions == nil {
+			continue
+		}
+		data = append(data, plotter.XY{
+			X: float64(*v.Additions + *v.Deletions),
+			Y: float64(v.MergeTimeMinutes),
+		})
+	}
+	if len(dlts.PullRequests) != 0 && len(data) == 0 {
+		return nil, ErrNoPRSize
+	}
+
+	p := plot.New()
+	p.X.Label.Text = "PR size[lines]"
+	p.Y.Label.Text = "Lead Time[min]"
+	p.Add(plotter.NewGrid())
+
+	scatter, err := plotter.NewScatter(data)
+	if err != nil {
+		return nil, err
+	}
+	scatter.Color = color.RGBA{R: 226, G: 45, B: 60, A: 255}
+	p.Add(scatter)
+	return p, nil
+}
+
+// cumulativePlot return step graph of cumulative number of merged PRs over merge date.
+func (dlts *DetailLeadTimeStat) cumulativePlot() (*plot.Plot, error) {
+	merged := make([]*usecase.PullRequest, 0, len(dlts.PullRequests))
+	for _, v := range dlts.PullRequests {
+		if !v.MergedAt.IsZero() {
+			merged = append(merged, v)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].MergedAt.Before(merged[j].MergedAt)
+	})
+
+	data := make(plotter.XYs, 0, len(merged))
+	for i, v := range merged {
+		data = append(data, plotter.XY{X: float64(v.MergedAt.Unix()), Y: float64(i + 1)})
+	}
+
+	p := plot.New()
+	p.X.Label.Text = "Merge date"
+	p.Y.Label.Text = "Merged PRs"
+	p.X.Tick.Marker = plot.TimeTicks{Format: "2006-01-02"}
+	p.Y.Min = 0
+	p.Add(plotter.NewGrid())
+
+	line, err := plotter.NewLine(data)
+	if err != nil {
+		return nil, err
+	}
+	line.StepStyle = plotter.PostStep
+	line.Color = color.RGBA{R: 60, G: 170, B: 80, A: 255}
+	line.Width = vg.Points(1.5)
+	p.Add(line)
+	return p, nil
+}
+
+// topAuthorGroups return PR groups of authors with the most PRs.
+func topAuthorGroups(prs []*usecase.PullRequest) []*prGroup {
+	groups := authorGroups(prs)
+	if len(groups) > maxAuthorsInChart {
+		groups = groups[:maxAuthorsInChart]
+	}
+	return groups
+}
+
+// authorGroups return PR group of each author in descending order of number of PRs.
+// Authors with the same number of PRs are sorted by name.
+func authorGroups(prs []*usecase.PullRequest) []*prGroup {
+	index := make(map[string]*prGroup)
+	groups := make([]*prGroup, 0)
+	for _, v := range prs {
+		name := authorName(v)
+		g, ok := index[name]
+		if !ok {
+			g = &prGroup{name: name}
+			index[name] = g
+			groups = append(groups, g)
+		}
+		g.prs = append(g.prs, v)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].prs) != len(groups[j].prs) {
+			return len(groups[i].prs) > len(groups[j].prs)
+		}
+		return groups[i].name < groups[j].name
+	})
+	return groups
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/shogo82148/pointer"
+	"gonum.org/v1/plot/vg"
+)
+
+func Test_option_chartPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		opt           *option
+		wantPath      string
+		wantLink      string
+		wantTrendPath string
+		wantTrendLink string
+	}{
+		{
+			name:          "Default chart name with format",
+			opt:           &option{chartFormat: "svg"},
+			wantPath:      "leadtime.svg",
+			wantLink:      "./leadtime.svg",
+			wantTrendPath: "leadtime_trend.svg",
+			wantTrendLink: "./leadtime_trend.svg",
+		},
+		{
+			name:          "Relative path",
+			opt:           &option{chartOutput: "doc/histogram.png", chartFormat: "png"},
+			wantPath:      "doc/histogram.png",
+			wantLink:      "./doc/histogram.png",
+			wantTrendPath: "doc/histogram_trend.png",
+			wantTrendLink: "./doc/histogram_trend.png",
+		},
+		{
+			name:          "Path in parent directory",
+			opt:           &option{chartOutput: "../chart.pdf", chartFormat: "pdf"},
+			wantPath:      "../chart.pdf",
+			wantLink:      "../chart.pdf",
+			wantTrendPath: "../chart_trend.pdf",
+			wantTrendLink: "../chart_trend.pdf",
+		},
+		{
+			name:          "Path without extension",
+			opt:           &option{chartOutput: "chart", chartFormat: "png"},
+			wantPath:      "chart",
+			wantLink:      "./chart",
+			wantTrendPath: "chart_trend",
+			wantTrendLink: "./chart_trend",
+		},
+		{
+			name:          "Absolute path",
+			opt:           &option{chartOutput: "/tmp/chart.png", chartFormat: "png"},
+			wantPath:      "/tmp/chart.png",
+			wantLink:      "/tmp/chart.png",
+			wantTrendPath: "/tmp/chart_trend.png",
+			wantTrendLink: "/tmp/chart_trend.png",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.opt.chartPath(); got != tt.wantPath {
+				t.Errorf("mismatch want=%v, got=%v", tt.wantPath, got)
+			}
+			if got := tt.opt.chartLink(); got != tt.wantLink {
+				t.Errorf("mismatch want=%v, got=%v", tt.wantLink, got)
+			}
+			if got := tt.opt.trendChartPath(); got != tt.wantTrendPath {
+				t.Errorf("mismatch want=%v, got=%v", tt.wantTrendPath, got)
+			}
+			if got := tt.opt.trendChartLink(); got != tt.wantTrendLink {
+				t.Errorf("mismatch want=%v, got=%v", tt.wantTrendLink, got)
+			}
+		})
+	}
+}
+
+func TestDetailLeadTimeStat_drawGraph(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	dlts := &DetailLeadTimeStat{PullRequests: []*usecase.PullRequest{
+		{Number: 1, User: &model.User{Name: pointer.String("alice")}, MergedAt: start, MergeTimeMinutes: 60},
+		{Number: 2, User: &model.User{Name: pointer.String("bob")}, MergedAt: start.Add(24 * time.Hour), MergeTimeMinutes: 120},
+		{Number: 3, User: &model.User{Name: pointer.String("alice")}, MergedAt: start.Add(48 * time.Hour), MergeTimeMinutes: 30},
+	}}
+
+	tests := []struct {
+		chartType chartType
+		format    string
+		magic     string
+	}{
+		{chartType: chartTypeLine, format: "png", magic: "\x89PNG"},
+		{chartType: chartTypeHistogram, format: "svg", magic: "<?xml"},
+		{chartType: chartTypeBoxAuthor, format: "pdf", magic: "%PDF"},
+		{chartType: chartTypeCumulative, format: "png", magic: "\x89PNG"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(string(tt.chartType), func(t *testing.T) {
+			t.Parallel()
+
+			opt := &option{
+				chartType:   tt.chartType,
+				chartOutput: filepath.Join(t.TempDir(), "chart."+tt.format),
+				chartFormat: tt.format,
+				chartWidth:  4 * 72,
+				chartHeight: 4 * 72,
+			}
+			if err := dlts.drawGraph(opt); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := os.ReadFile(opt.chartOutput)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(got, []byte(tt.magic)) {
+				t.Errorf("chart is not %s format", tt.format)
+			}
+		})
+	}
+}
+
+func Test_option_valid_chartFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		modify func(o *option)
+		want   error
+	}{
+		{
+			name:   "Extension matches format",
+			modify: func(o *option) { o.chartOutput, o.chartFormat = "chart.svg", "svg" },
+		},
+		{
+			name:   "Upper case extension matches format",
+			modify: func(o *option) { o.chartOutput = "chart.PNG" },
+		},
+		{
+			name:   "Path without extension",
+			modify: func(o *option) { o.chartOutput = "chart" },
+		},
+		{
+			name:   "Extension differs from format",
+			modify: func(o *option) { o.chartOutput, o.chartFormat = "chart.png", "svg" },
+			want:   ErrChartFormatMismatch,
+		},
+		{
+			name:   "Unsupported extension",
+			modify: func(o *option) { o.chartOutput = "chart.jpg" },
+			want:   ErrChartFormatMismatch,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := newValidOption()
+			tt.modify(o)
+			if err := o.valid(); !errors.Is(err, tt.want) {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDetailLeadTimeStat_print_markdownTwice(t *testing.T) {
+	// Not parallel because default chart files are written in the current directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	start := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	dlts := &DetailLeadTimeStat{PullRequests: []*usecase.PullRequest{
+		{Number: 1, MergedAt: start, MergeTimeMinutes: 60},
+		{Number: 2, MergedAt: start.Add(7 * 24 * time.Hour), MergeTimeMinutes: 120},
+	}}
+	dlts.stat(nil)
+	dlts.LeadTimeStatistics.GroupBy = string(groupByWeek)
+	dlts.LeadTimeStatistics.Groups = []*GroupStat{
+		{Group: "2023-01-30", TotalPR: 1, LeadTimeAverage: 60, LeadTimeMedian: 60, LeadTimeP90: 60},
+		{Group: "2023-02-06", TotalPR: 1, LeadTimeAverage: 120, LeadTimeMedian: 120, LeadTimeP90: 120},
+	}
+
+	opt := newValidOption()
+	opt.markdown = true
+	opt.groupBy = groupByWeek
+	opt.chartWidth, opt.chartHeight = 4*vg.Inch, 4*vg.Inch
+
+	for i := 0; i < 2; i++ {
+		if err := dlts.print(opt); err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+	}
+	for _, v := range []string{"leadtime.png", "leadtime_trend.png"} {
+		got, err := os.ReadFile(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(got, []byte("\x89PNG")) {
+			t.Errorf("%s is not png format", v)
+		}
+	}
+}
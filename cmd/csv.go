@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shogo82148/pointer"
+)
+
+const (
+	// commaCSV is field separator of CSV output.
+	commaCSV = ','
+	// commaTSV is field separator of TSV output.
+	commaTSV = '\t'
+)
+
+// newDelimitedWriter return writer that separates fields with comma. A field that
+// includes the separator, double quote or newline is quoted, and the double quote is escaped.
+func newDelimitedWriter(w io.Writer, comma rune) *csv.Writer {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	return writer
+}
+
+// detailCSV write every field of PRs with header row.
+// Dates are RFC 3339 format, and a date or stage time that does not exist is empty.
+func (dlts *DetailLeadTimeStat) detailCSV(w io.Writer, comma rune) error {
+	records := [][]string{{
+		"repository", "number", "state", "status", "title", "user", "bot",
+		"first_commit_at", "created_at", "first_review_at", "approved_at", "closed_at", "merged_at",
+		"merge_time_minutes", "time_to_merge_minutes", "time_to_abandon_minutes", "coding_time_minutes", "pickup_time_minutes", "review_time_minutes", "merge_stage_time_minutes",
+		"additions", "deletions", "changed_files", "comments", "labels",
+	}}
+	for _, v := range dlts.PullRequests {
+		var user string
+		var bot bool
+		if v.User != nil {
+			user = pointer.StringValue(v.User.Name)
+			bot = v.User.IsBot()
+		}
+		records = append(records, []string{
+			v.Repository,
+			strconv.Itoa(v.Number),
+			v.State,
+			string(v.Status),
+			v.Title,
+			user,
+			strconv.FormatBool(bot),
+			rfc3339(v.FirstCommitAt),
+			rfc3339(v.CreatedAt),
+			rfc3339(v.FirstReviewAt),
+			rfc3339(v.ApprovedAt),
+			rfc3339(v.ClosedAt),
+			rfc3339(v.MergedAt),
+			strconv.Itoa(v.MergeTimeMinutes),
+			optionalInt(v.TimeToMergeMinutes),
+			optionalInt(v.TimeToAbandonMinutes),
+			optionalInt(v.CodingTimeMinutes),
+			optionalInt(v.PickupTimeMinutes),
+			optionalInt(v.ReviewTimeMinutes),
+			optionalInt(v.MergeStageTimeMinutes),
+			optionalInt(v.Additions),
+			optionalInt(v.Deletions),
+			optionalInt(v.ChangedFiles),
+			optionalInt(v.Comments),
+			strings.Join(v.Labels, ";"),
+		})
+	}
+	return newDelimitedWriter(w, comma).WriteAll(records)
+}
+
+// summaryCSV write lead time statistics and stage statistics as header row and one value row.
+// Column names are the same as JSON field names. Time to merge and stage columns are
+// prefixed with the statistic name (e.g. time_to_merge_median).
+func (dlts *DetailLeadTimeStat) summaryCSV(w io.Writer) error {
+	columns := dlts.LeadTimeStatistics.summaryColumns()
+	header := make([]string, 0, len(columns))
+	values := make([]string, 0, len(columns))
+	for _, v := range columns {
+		header = append(header, v.name)
+		if v.integer {
+			values = append(values, strconv.Itoa(int(v.value)))
+		} else {
+			values = append(values, formatFloat(v.value))
+		}
+	}
+	return newDelimitedWriter(w, commaCSV).WriteAll([][]string{header, values})
+}
+
+const (
+	// unitMinutes is UCUM unit of durations in minutes.
+	unitMinutes = "min"
+	// unitPullRequests is UCUM unit of number of PRs.
+	unitPullRequests = "{pull_request}"
+	// unitPercent is UCUM unit of rates in percent.
+	unitPercent = "%"
+)
+
+// summaryColumn is one value of summary statistics.
+type summaryColumn struct {
+	name  string
+	value float64
+	// integer is whether the value is integer (e.g. number of PRs, maximum minutes) or not.
+	integer bool
+	// unit is UCUM unit of the value (unitMinutes, unitPullRequests or unitPercent).
+	unit string
+}
+
+// summaryColumns return lead time statistics, stage statistics and abandonment statistics
+// in the order of summary csv columns.
+// Additional percentiles are sorted in ascending order.
+func (lts *LeadTimeStat) summaryColumns() []summaryColumn {
+	columns := []summaryColumn{
+		{name: "total_pr", value: float64(lts.TotalPR), integer: true, unit: unitPullRequests},
+		{name: "lead_time_maximum", value: float64(lts.LeadTimeMaximum), integer: true, unit: unitMinutes},
+		{name: "lead_time_minimum", value: float64(lts.LeadTimeMinimum), integer: true, unit: unitMinutes},
+		{name: "lead_time_summation", value: float64(lts.LeadTimeSummation), integer: true, unit: unitMinutes},
+		{name: "lead_time_average", value: lts.LeadTimeAverage, unit: unitMinutes},
+		{name: "lead_time_median", value: lts.LeadTimeMedian, unit: unitMinutes},
+		{name: "lead_time_p75", value: lts.LeadTimeP75, unit: unitMinutes},
+		{name: "lead_time_p90", value: lts.LeadTimeP90, unit: unitMinutes},
+		{name: "lead_time_p95", value: lts.LeadTimeP95, unit: unitMinutes},
+		{name: "lead_time_p99", value: lts.LeadTimeP99, unit: unitMinutes},
+		{name: "lead_time_standard_deviation", value: lts.LeadTimeStandardDeviation, unit: unitMinutes},
+		{name: "lead_time_interquartile_range", value: lts.LeadTimeInterquartileRange, unit: unitMinutes},
+	}
+
+	names := make([]string, 0, len(lts.LeadTimePercentiles))
+	for k := range lts.LeadTimePercentiles {
+		names = append(names, k)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return percentileOrder(strings.ToUpper(names[i])) < percentileOrder(strings.ToUpper(names[j]))
+	})
+	for _, v := range names {
+		columns = append(columns, summaryColumn{name: "lead_time_" + v, value: lts.LeadTimePercentiles[v], unit: unitMinutes})
+	}
+
+	stages := []struct {
+		name string
+		stat *StageStat
+	}{
+		{name: "time_to_merge", stat: lts.TimeToMerge},
+		{name: "coding_time", stat: lts.CodingTime},
+		{name: "pickup_time", stat: lts.PickupTime},
+		{name: "review_time", stat: lts.ReviewTime},
+		{name: "merge_stage_time", stat: lts.MergeStageTime},
+	}
+	for _, v := range stages {
+		s := v.stat
+		if s == nil {
+			s = &StageStat{}
+		}
+		columns = append(columns,
+			summaryColumn{name: v.name + "_total_pr", value: float64(s.TotalPR), integer: true, unit: unitPullRequests},
+			summaryColumn{name: v.name + "_maximum", value: float64(s.Maximum), integer: true, unit: unitMinutes},
+			summaryColumn{name: v.name + "_minimum", value: float64(s.Minimum), integer: true, unit: unitMinutes},
+			summaryColumn{name: v.name + "_average", value: s.Average, unit: unitMinutes},
+			summaryColumn{name: v.name + "_median", value: s.Median, unit: unitMinutes},
+		)
+	}
+
+	a := lts.Abandonment
+	if a == nil {
+		a = &AbandonmentStat{}
+	}
+	return append(columns, a.summaryColumns()...)
+}
+
+// rfc3339 return t in RFC 3339 format. If t is zero, return empty string.
+func rfc3339(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// optionalInt return n for CSV output. If n is nil, return empty string.
+func optionalInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+// formatFloat return f with 2 decimal places.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
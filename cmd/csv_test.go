@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/shogo82148/pointer"
+)
+
+func newCSVTestStat() *DetailLeadTimeStat {
+	jst := time.FixedZone("JST", 9*60*60)
+	return &DetailLeadTimeStat{
+		PullRequests: []*usecase.PullRequest{
+			{
+				Repository:         "nao1215/leadtime",
+				Number:             1,
+				State:              "closed",
+				Status:             usecase.PRStatusMerged,
+				Title:              "fix \"quote\", tab\tand\nnewline",
+				User:               &model.User{Name: pointer.String("renovate[bot]"), Bot: true},
+				FirstCommitAt:      time.Date(2023, 1, 1, 9, 0, 0, 0, jst),
+				CreatedAt:          time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC),
+				ClosedAt:           time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC),
+				MergedAt:           time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC),
+				MergeTimeMinutes:   120,
+				TimeToMergeMinutes: pointer.Int(60),
+				CodingTimeMinutes:  pointer.Int(60),
+				Additions:          pointer.Int(10),
+				Deletions:          pointer.Int(0),
+				Labels:             []string{"bug", "dependencies"},
+			},
+			{
+				Number: 2,
+				State:  "open",
+				Status: usecase.PRStatusOpen,
+				Title:  "no user",
+			},
+		},
+	}
+}
+
+func TestDetailLeadTimeStat_detailCSV(t *testing.T) {
+	t.Parallel()
+
+	header := "repository,number,state,status,title,user,bot," +
+		"first_commit_at,created_at,first_review_at,approved_at,closed_at,merged_at," +
+		"merge_time_minutes,time_to_merge_minutes,time_to_abandon_minutes,coding_time_minutes,pickup_time_minutes,review_time_minutes,merge_stage_time_minutes," +
+		"additions,deletions,changed_files,comments,labels\n"
+
+	tests := []struct {
+		name  string
+		comma rune
+		want  string
+	}{
+		{
+			name:  "Quote field with comma, double quote and newline in csv",
+			comma: commaCSV,
+			want: header +
+				"nao1215/leadtime,1,closed,merged,\"fix \"\"quote\"\", tab\tand\nnewline\",renovate[bot],true," +
+				"2023-01-01T09:00:00+09:00,2023-01-01T01:00:00Z,,,2023-01-01T02:00:00Z,2023-01-01T02:00:00Z," +
+				"120,60,,60,,,,10,0,,,bug;dependencies\n" +
+				",2,open,open,no user,,false,,,,,,,0,,,,,,,,,,,\n",
+		},
+		{
+			name:  "Quote field with tab in tsv",
+			comma: commaTSV,
+			want: strings.ReplaceAll(header, ",", "\t") +
+				"nao1215/leadtime\t1\tclosed\tmerged\t\"fix \"\"quote\"\", tab\tand\nnewline\"\trenovate[bot]\ttrue\t" +
+				"2023-01-01T09:00:00+09:00\t2023-01-01T01:00:00Z\t\t\t2023-01-01T02:00:00Z\t2023-01-01T02:00:00Z\t" +
+				"120\t60\t\t60\t\t\t\t10\t0\t\t\tbug;dependencies\n" +
+				"\t2\topen\topen\tno user\t\tfalse\t\t\t\t\t\t\t0\t\t\t\t\t\t\t\t\t\t\t\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := newCSVTestStat().detailCSV(&buf, tt.comma); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDetailLeadTimeStat_summaryCSV(t *testing.T) {
+	t.Parallel()
+
+	dlts := &DetailLeadTimeStat{
+		LeadTimeStatistics: &LeadTimeStat{
+			TotalPR:             2,
+			LeadTimeMaximum:     120,
+			LeadTimeMinimum:     60,
+			LeadTimeSummation:   180,
+			LeadTimeAverage:     90,
+			LeadTimeMedian:      90,
+			LeadTimePercentiles: map[string]float64{"p99.9": 119.94, "p50": 90},
+			TimeToMerge:         &StageStat{TotalPR: 2, Maximum: 60, Minimum: 30, Average: 45, Median: 45},
+			Abandonment:         &AbandonmentStat{ClosedPR: 3, AbandonedPR: 1, Rate: 100.0 / 3},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := dlts.summaryCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("mismatch want=%v, got=%v", 2, len(lines))
+	}
+	header := strings.Split(lines[0], ",")
+	values := strings.Split(lines[1], ",")
+	if len(header) != len(values) {
+		t.Fatalf("mismatch want=%v, got=%v", len(header), len(values))
+	}
+	got := make(map[string]string, len(header))
+	for i, v := range header {
+		got[v] = values[i]
+	}
+
+	want := map[string]string{
+		"total_pr":                 "2",
+		"lead_time_maximum":        "120",
+		"lead_time_average":        "90.00",
+		"lead_time_p50":            "90.00",
+		"lead_time_p99.9":          "119.94",
+		"time_to_merge_total_pr":   "2",
+		"time_to_merge_average":    "45.00",
+		"coding_time_total_pr":     "0",
+		"coding_time_median":       "0.00",
+		"abandonment_closed_pr":    "3",
+		"abandonment_rate":         "33.33",
+		"abandonment_time_maximum": "0",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("mismatch %s want=%v, got=%v", k, v, got[k])
+		}
+	}
+	if p50, p99 := strings.Index(lines[0], "lead_time_p50"), strings.Index(lines[0], "lead_time_p99.9"); p50 > p99 {
+		t.Errorf("additional percentiles are not sorted: %s", lines[0])
+	}
+}
+
+func TestDetailLeadTimeStat_groupCSV(t *testing.T) {
+	t.Parallel()
+
+	dlts := &DetailLeadTimeStat{
+		LeadTimeStatistics: &LeadTimeStat{
+			GroupBy: "label",
+			Groups: []*GroupStat{
+				{Group: "bug, urgent", TotalPR: 2, LeadTimeAverage: 90, LeadTimeMedian: 90, LeadTimeP90: 114},
+				{Group: "no label", TotalPR: 1, LeadTimeAverage: 1.5, LeadTimeMedian: 1.5, LeadTimeP90: 1.5},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := dlts.groupCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "label,total_pr,lead_time_average,lead_time_median,lead_time_p90\n" +
+		"\"bug, urgent\",2,90.00,90.00,114.00\n" +
+		"no label,1,1.50,1.50,1.50\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseDuration parse duration string. In addition to time.ParseDuration format
+// (e.g. "36h"), days and weeks are accepted (e.g. "30d", "2w").
+func parseDuration(s string) (time.Duration, error) {
+	const (
+		day  = 24 * time.Hour
+		week = 7 * day
+	)
+
+	for suffix, unit := range map[string]time.Duration{"d": day, "w": week} {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidDuration, s)
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidDuration, s)
+	}
+	return d, nil
+}
+
+// parseDate parse absolute date (e.g. "2023-01-02", "2023-01-02T15:04:05Z")
+// or relative duration from now (e.g. "30d" means 30 days ago).
+// If endOfDay is true, date without time means the end of the day
+// (the beginning of the next day).
+func parseDate(s string, now time.Time, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		if endOfDay {
+			return t.AddDate(0, 0, 1), nil
+		}
+		return t, nil
+	}
+
+	d, err := parseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidDate, s)
+	}
+	return now.Add(-d), nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_parseDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    time.Duration
+		wantErr error
+	}{
+		{name: "Days", s: "30d", want: 30 * 24 * time.Hour},
+		{name: "Weeks", s: "2w", want: 14 * 24 * time.Hour},
+		{name: "Zero days", s: "0d", want: 0},
+		{name: "time.ParseDuration format", s: "1h30m", want: 90 * time.Minute},
+		{name: "Negative days", s: "-1d", wantErr: ErrInvalidDuration},
+		{name: "Negative weeks", s: "-2w", wantErr: ErrInvalidDuration},
+		{name: "Negative time.ParseDuration format", s: "-12h", wantErr: ErrInvalidDuration},
+		{name: "Fractional days", s: "1.5d", wantErr: ErrInvalidDuration},
+		{name: "Suffix without number", s: "d", wantErr: ErrInvalidDuration},
+		{name: "Unknown unit", s: "3y", wantErr: ErrInvalidDuration},
+		{name: "Empty", s: "", wantErr: ErrInvalidDuration},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseDuration(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("mismatch want=%v, got=%v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_parseDate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		s        string
+		endOfDay bool
+		want     time.Time
+		wantErr  error
+	}{
+		{
+			name: "RFC 3339 with UTC",
+			s:    "2023-01-02T15:04:05Z",
+			want: time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name:     "RFC 3339 is not moved to the end of day",
+			s:        "2023-01-02T15:04:05+09:00",
+			endOfDay: true,
+			want:     time.Date(2023, 1, 2, 6, 4, 5, 0, time.UTC),
+		},
+		{
+			name: "Local date is the beginning of the day",
+			s:    "2023-01-02",
+			want: time.Date(2023, 1, 2, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "Local date with endOfDay is the beginning of the next day",
+			s:        "2023-01-02",
+			endOfDay: true,
+			want:     time.Date(2023, 1, 3, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "End of month moves to the next month",
+			s:        "2023-02-28",
+			endOfDay: true,
+			want:     time.Date(2023, 3, 1, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name: "Days ago",
+			s:    "30d",
+			want: now.Add(-30 * 24 * time.Hour),
+		},
+		{
+			name:     "Relative duration is not moved to the end of day",
+			s:        "1w",
+			endOfDay: true,
+			want:     now.Add(-7 * 24 * time.Hour),
+		},
+		{
+			name: "Hours ago",
+			s:    "12h",
+			want: now.Add(-12 * time.Hour),
+		},
+		{name: "Negative duration", s: "-1d", wantErr: ErrInvalidDate},
+		{name: "Invalid date", s: "2023-13-01", wantErr: ErrInvalidDate},
+		{name: "Slash separated date", s: "2023/01/02", wantErr: ErrInvalidDate},
+		{name: "Empty", s: "", wantErr: ErrInvalidDate},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseDate(tt.s, now, tt.endOfDay)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("mismatch want=%v, got=%v", tt.wantErr, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
@@ -4,4 +4,60 @@ import "errors"
 
 var (
 	ErrMultipleOutputFlag = errors.New("multiple output flags are specified at once")
+	// ErrInvalidDuration means "duration format is invalid"
+	ErrInvalidDuration = errors.New("invalid duration (e.g. 30d, 2w, 12h)")
+	// ErrInvalidGroupBy means "unsupported group-by value"
+	ErrInvalidGroupBy = errors.New("--group-by must be week, month, author, team, label or size")
+	// ErrTeamFileRequiresGroupByTeam means "--team-file and --group-by=team are used only together"
+	ErrTeamFileRequiresGroupByTeam = errors.New("--group-by=team requires --team-file, and --team-file requires --group-by=team")
+	// ErrSizeThresholdsRequiresGroupBySize means "--size-thresholds is used only with --group-by=size"
+	ErrSizeThresholdsRequiresGroupBySize = errors.New("--size-thresholds requires --group-by=size")
+	// ErrGroupCSVRequiresGroupBy means "--group-csv is used only with --group-by"
+	ErrGroupCSVRequiresGroupBy = errors.New("--group-csv requires --group-by")
+	// ErrInvalidSizeThresholds means "size thresholds are not 4 positive numbers in ascending order"
+	ErrInvalidSizeThresholds = errors.New("--size-thresholds must be 4 positive numbers in ascending order (e.g. 10,100,500,1000)")
+	// ErrInvalidTeamFile means "team file is not JSON object of login and team name"
+	ErrInvalidTeamFile = errors.New("team file must be JSON object that maps login to team name")
+	// ErrInvalidPercentile means "percentile must be between 0 and 100"
+	ErrInvalidPercentile = errors.New("percentile must be between 0 and 100")
+	// ErrInvalidRepository means "repository must be name or owner/name"
+	ErrInvalidRepository = errors.New("repository must be name or owner/name")
+	// ErrInvalidRepositoryPattern means "invalid repository glob pattern"
+	ErrInvalidRepositoryPattern = errors.New("invalid repository glob pattern")
+	// ErrNoRepository means "no repository matches the specified conditions"
+	ErrNoRepository = errors.New("no repository matches the specified conditions")
+	// ErrInvalidProvider means "unsupported provider value"
+	ErrInvalidProvider = errors.New("--provider must be github, gitlab, gitea, forgejo or bitbucket")
+	// ErrGraphQLRequiresGitHub means "GraphQL API is only for GitHub"
+	ErrGraphQLRequiresGitHub = errors.New("--graphql is only available with --provider=github")
+	// ErrGitDirConflict means "--git-dir uses only the local repository"
+	ErrGitDirConflict = errors.New("--git-dir cannot be used with --provider, --graphql, --org or --repo")
+	// ErrFromFileConflict means "--from-file reads PRs only from the file"
+	ErrFromFileConflict = errors.New("--from-file cannot be used with --git-dir, --provider, --api-url, --graphql or --cache")
+	// ErrExportRequiresRaw means "export format is not specified"
+	ErrExportRequiresRaw = errors.New("export requires --raw")
+	// ErrInvalidChartType means "chart type is not supported"
+	ErrInvalidChartType = errors.New("invalid chart type (line, histogram, box-author, scatter-size, cumulative)")
+	// ErrInvalidChartFormat means "chart format is not supported"
+	ErrInvalidChartFormat = errors.New("invalid chart format (png, svg, pdf)")
+	// ErrChartFormatMismatch means "extension of chart file differs from chart format"
+	ErrChartFormatMismatch = errors.New("extension of --chart-output does not match --chart-format")
+	// ErrInvalidChartSize means "chart width or height is invalid"
+	ErrInvalidChartSize = errors.New("invalid chart size (e.g. 4in, 10cm, 300pt)")
+	// ErrNoPRSize means "PR size is not provided by the source"
+	ErrNoPRSize = errors.New("scatter-size chart requires PR size (additions and deletions), but no PR has it")
+	// ErrServeRequiresMetrics means "serve mode is not specified"
+	ErrServeRequiresMetrics = errors.New("serve requires --metrics")
+	// ErrInvalidInterval means "refresh interval is not positive"
+	ErrInvalidInterval = errors.New("--interval must be positive (e.g. 15m)")
+	// ErrInvalidPushURL means "URL of push destination is invalid"
+	ErrInvalidPushURL = errors.New("--push-gateway and --otlp-endpoint must be http or https URL")
+	// ErrEmptyPushJob means "job label of pushed statistics is empty"
+	ErrEmptyPushJob = errors.New("--push-job must not be empty")
+	// ErrInvalidOTLPHeader means "OTLP header is not key=value format"
+	ErrInvalidOTLPHeader = errors.New("--otlp-header must be key=value format")
+	// ErrPushFailed means "push destination returned error status"
+	ErrPushFailed = errors.New("failed to push lead time statistics")
+	// ErrInvalidDate means "date format is invalid"
+	ErrInvalidDate = errors.New("invalid date (e.g. 2023-01-02, 2023-01-02T15:04:05Z, 30d)")
 )
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/nao1215/leadtime/infrastructure/archive"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export fetched PR data to recompute statistics later without API",
+		Long: `Export fetched PR data to recompute statistics later without API.
+With --raw, PRs and their first commits and reviews are printed as versioned JSON.
+'leadtime stat --from-file' reads the JSON instead of calling API, so the same
+filters and output modes as a live run are available.`,
+		Example: `  LT_GITHUB_ACCESS_TOKEN=XXX leadtime export --raw --owner=nao1215 --repo=sqly > sqly.json
+  leadtime stat --from-file=sqly.json --since=30d --markdown`,
+		RunE: export,
+	}
+
+	addSourceFlags(exportCmd)
+	exportCmd.Flags().Bool("raw", false, "Export PRs, first commits and reviews as JSON")
+
+	return exportCmd
+}
+
+func export(cmd *cobra.Command, args []string) error {
+	raw, err := cmd.Flags().GetBool("raw")
+	if err != nil {
+		return err
+	}
+	if !raw {
+		return ErrExportRequiresRaw
+	}
+
+	opt, err := newSourceOption(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := opt.validSource(); err != nil {
+		return err
+	}
+
+	leadTime, err := newLeadTime(opt)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	repos, err := opt.repositories(ctx, leadTime.LeadTimeUsecase)
+	if err != nil {
+		return err
+	}
+
+	input := &usecase.LeadTimeUsecaseExportInput{
+		Owner:        opt.gitHubOwner,
+		Repositories: repos,
+		Concurrency:  opt.concurrency,
+	}
+	if err := input.Valid(); err != nil {
+		return err
+	}
+
+	output, err := leadTime.LeadTimeUsecase.Export(ctx, input)
+	if err != nil {
+		return err
+	}
+	return archive.Write(os.Stdout, newArchive(output, time.Now()))
+}
+
+// newArchive convert exported data to archive format.
+func newArchive(output *usecase.LeadTimeUsecaseExportOutput, exportedAt time.Time) *archive.Archive {
+	a := &archive.Archive{
+		Version:      archive.Version,
+		ExportedAt:   exportedAt.UTC(),
+		Repositories: make([]*archive.Repository, 0, len(output.Repositories)),
+	}
+	for _, repo := range output.Repositories {
+		prs := make([]*archive.PullRequest, 0, len(repo.PullRequests))
+		for _, v := range repo.PullRequests {
+			prs = append(prs, archive.NewPullRequest(v.PullRequest, v.FirstCommit, v.Reviews))
+		}
+		a.Repositories = append(a.Repositories, &archive.Repository{
+			Owner:        repo.Owner,
+			Name:         repo.Name,
+			PullRequests: prs,
+		})
+	}
+	return a
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/spf13/cobra"
+)
+
+// addFilterFlags add flags that select PRs used in statistics.
+// They are shared by the subcommands that calculate statistics.
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("exclude-bot", "B", false, "Exclude Pull Requests created by bots")
+	cmd.Flags().IntSliceP("exclude-pr", "P", []int{}, "Exclude specified Pull Requests (e.g. '-P 1,3,19')")
+	cmd.Flags().StringSliceP("exclude-user", "U", []string{}, "Exclude Pull Requests created by specified user (e.g. '-U nao,alice')")
+	cmd.Flags().StringSlice("include-label", []string{}, "Only Pull Requests that have one of specified labels (e.g. '--include-label bug,feature')")
+	cmd.Flags().StringSlice("exclude-label", []string{}, "Exclude Pull Requests that have one of specified labels (e.g. '--exclude-label dependencies')")
+	cmd.Flags().Bool("include-unmerged", false, "Include Pull Requests closed without merge in lead time statistics")
+	cmd.Flags().String("since", "", "Only PRs whose date is at or after this date or duration ago (e.g. 2023-01-02, 30d)")
+	cmd.Flags().String("until", "", "Only PRs whose date is before this date or duration ago (e.g. 2023-01-31, 7d)")
+	cmd.Flags().String("date-field", string(usecase.DateFieldMerged), "PR date compared with --since and --until (created, merged, closed)")
+}
+
+// setFilter set the values of flags added by addFilterFlags to option.
+// A duration of --since and --until (e.g. 30d) is relative to now.
+func (o *option) setFilter(cmd *cobra.Command, now time.Time) error {
+	bot, err := cmd.Flags().GetBool("exclude-bot")
+	if err != nil {
+		return err
+	}
+
+	excludePRs, err := cmd.Flags().GetIntSlice("exclude-pr")
+	if err != nil {
+		return err
+	}
+
+	excludeUsers, err := cmd.Flags().GetStringSlice("exclude-user")
+	if err != nil {
+		return err
+	}
+
+	includeLabels, err := cmd.Flags().GetStringSlice("include-label")
+	if err != nil {
+		return err
+	}
+
+	excludeLabels, err := cmd.Flags().GetStringSlice("exclude-label")
+	if err != nil {
+		return err
+	}
+
+	includeUnmerged, err := cmd.Flags().GetBool("include-unmerged")
+	if err != nil {
+		return err
+	}
+
+	since, err := dateFlag(cmd, "since", now, false)
+	if err != nil {
+		return err
+	}
+
+	until, err := dateFlag(cmd, "until", now, true)
+	if err != nil {
+		return err
+	}
+
+	dateField, err := cmd.Flags().GetString("date-field")
+	if err != nil {
+		return err
+	}
+
+	o.dateField = usecase.DateField(dateField)
+	o.excludeBot = bot
+	o.excludePRs = excludePRs
+	o.excludeUsers = excludeUsers
+	o.includeLabels = includeLabels
+	o.excludeLabels = excludeLabels
+	o.includeUnmerged = includeUnmerged
+	o.since = since
+	o.until = until
+	return nil
+}
+
+// dateFlag return date specified by flag. If the flag is empty, return zero time.
+func dateFlag(cmd *cobra.Command, name string, now time.Time, endOfDay bool) (time.Time, error) {
+	value, err := cmd.Flags().GetString(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return parseDate(value, now, endOfDay)
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// groupBy is the way to group PRs for breakdown statistics.
+type groupBy string
+
+const (
+	// groupByNone means PRs are not grouped
+	groupByNone groupBy = ""
+	// groupByWeek means merged PRs are grouped by the week of merge date
+	groupByWeek groupBy = "week"
+	// groupByMonth means merged PRs are grouped by the month of merge date
+	groupByMonth groupBy = "month"
+	// groupByAuthor means PRs are grouped by author
+	groupByAuthor groupBy = "author"
+	// groupByTeam means PRs are grouped by team of author in the team file
+	groupByTeam groupBy = "team"
+	// groupByLabel means PRs are grouped by label. A PR with several labels is in each group.
+	groupByLabel groupBy = "label"
+	// groupBySize means PRs are grouped by size class (XS to XL) of changed lines
+	groupBySize groupBy = "size"
+)
+
+// valid check whether group-by value is supported or not.
+func (g groupBy) valid() bool {
+	switch g {
+	case groupByNone, groupByWeek, groupByMonth, groupByAuthor, groupByTeam, groupByLabel, groupBySize:
+		return true
+	}
+	return false
+}
+
+// isTimeSeries check whether groups are time buckets or not.
+func (g groupBy) isTimeSeries() bool {
+	return g == groupByWeek || g == groupByMonth
+}
+
+// prGroup is PRs that belong to the same group.
+type prGroup struct {
+	name string
+	prs  []*usecase.PullRequest
+}
+
+// groups return PR groups in display order. teams is used only for groupByTeam,
+// and thresholds is used only for groupBySize.
+func (g groupBy) groups(prs []*usecase.PullRequest, teams teamMap, thresholds sizeThresholds) []*prGroup {
+	switch g {
+	case groupByWeek:
+		return timeBuckets(prs, weekStart, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }, "2006-01-02")
+	case groupByMonth:
+		return timeBuckets(prs, monthStart, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }, "2006-01")
+	case groupByAuthor:
+		return authorGroups(prs)
+	case groupByTeam:
+		return teams.groups(prs)
+	case groupByLabel:
+		return labelGroups(prs)
+	case groupBySize:
+		return thresholds.groups(prs)
+	case groupByNone:
+	}
+	return nil
+}
+
+// weekStart return the beginning of the week (Monday 00:00 UTC) that includes t.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+	return day.AddDate(0, 0, -offset)
+}
+
+// monthStart return the beginning of the month (1st 00:00 UTC) that includes t.
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// timeBuckets group merged PRs by merge date. Buckets without PRs between
+// the first and the last bucket are included so that the trend is continuous.
+func timeBuckets(prs []*usecase.PullRequest, start func(time.Time) time.Time, next func(time.Time) time.Time, layout string) []*prGroup {
+	buckets := map[time.Time][]*usecase.PullRequest{}
+	var first, last time.Time
+	for _, v := range prs {
+		if v.MergedAt.IsZero() {
+			continue
+		}
+
+		bucket := start(v.MergedAt)
+		buckets[bucket] = append(buckets[bucket], v)
+		if first.IsZero() || bucket.Before(first) {
+			first = bucket
+		}
+		if last.IsZero() || bucket.After(last) {
+			last = bucket
+		}
+	}
+	if len(buckets) == 0 {
+		return []*prGroup{}
+	}
+
+	groups := make([]*prGroup, 0, len(buckets))
+	for t := first; !t.After(last); t = next(t) {
+		groups = append(groups, &prGroup{
+			name: t.Format(layout),
+			prs:  buckets[t],
+		})
+	}
+	return groups
+}
+
+// GroupStat is lead time statistics of one PR group.
+type GroupStat struct {
+	Group           string  `json:"group"`
+	TotalPR         int     `json:"total_pr"`
+	LeadTimeAverage float64 `json:"lead_time_average"`
+	LeadTimeMedian  float64 `json:"lead_time_median"`
+	LeadTimeP90     float64 `json:"lead_time_p90"`
+}
+
+// newGroupStats return statistics of each group.
+func newGroupStats(groups []*prGroup) []*GroupStat {
+	stats := make([]*GroupStat, 0, len(groups))
+	for _, g := range groups {
+		nums := make([]int, 0, len(g.prs))
+		for _, v := range g.prs {
+			nums = append(nums, v.MergeTimeMinutes)
+		}
+		stats = append(stats, &GroupStat{
+			Group:           g.name,
+			TotalPR:         len(nums),
+			LeadTimeAverage: average(nums),
+			LeadTimeMedian:  median(nums),
+			LeadTimeP90:     percentile(nums, 90),
+		})
+	}
+	return stats
+}
+
+// summaryColumns return statistics of the group with the same names as summary csv columns.
+func (g *GroupStat) summaryColumns() []summaryColumn {
+	return []summaryColumn{
+		{name: "total_pr", value: float64(g.TotalPR), integer: true, unit: unitPullRequests},
+		{name: "lead_time_average", value: g.LeadTimeAverage, unit: unitMinutes},
+		{name: "lead_time_median", value: g.LeadTimeMedian, unit: unitMinutes},
+		{name: "lead_time_p90", value: g.LeadTimeP90, unit: unitMinutes},
+	}
+}
+
+// title return title of group column.
+func (g groupBy) title() string {
+	switch g {
+	case groupByWeek:
+		return "Week"
+	case groupByMonth:
+		return "Month"
+	case groupByAuthor:
+		return "Author"
+	case groupByTeam:
+		return "Team"
+	case groupByLabel:
+		return "Label"
+	case groupBySize:
+		return "Size"
+	case groupByNone:
+	}
+	return "Group"
+}
+
+func (dlts *DetailLeadTimeStat) groupStdout() {
+	lts := dlts.LeadTimeStatistics
+	printGroupStatsStdout(lts.GroupBy, groupBy(lts.GroupBy).title(), lts.Groups)
+}
+
+func (dlts *DetailLeadTimeStat) repositoryStdout() {
+	printGroupStatsStdout("repository", "Repository", dlts.LeadTimeStatistics.Repositories)
+}
+
+func printGroupStatsStdout(name, title string, stats []*GroupStat) {
+	fmt.Println("")
+	fmt.Printf("[lead time by %s]\n", name)
+	fmt.Printf("%s\tPRs\tAve[min]\tMedian[min]\tP90[min]\n", title)
+	for _, v := range stats {
+		fmt.Printf("%s\t%d\t%.2f\t%.2f\t%.2f\n", v.Group, v.TotalPR, v.LeadTimeAverage, v.LeadTimeMedian, v.LeadTimeP90)
+	}
+}
+
+func (dlts *DetailLeadTimeStat) repositoryMarkdown() {
+	printGroupStatsMarkdown("Repository", dlts.LeadTimeStatistics.Repositories)
+}
+
+func printGroupStatsMarkdown(title string, stats []*GroupStat) {
+	fmt.Printf("## Lead Time by %s\n", title)
+	fmt.Printf("| %s | PRs | Ave | MN | P90 |\n", title)
+	fmt.Println("|:-----|:----|:----|:---|:----|")
+	for _, v := range stats {
+		fmt.Printf("|%s|%d|%.2f[min]|%.2f[min]|%.2f[min]|\n", v.Group, v.TotalPR, v.LeadTimeAverage, v.LeadTimeMedian, v.LeadTimeP90)
+	}
+	fmt.Println()
+}
+
+// groupMarkdown print group statistics. trendChart is link to the trend chart
+// of time series groups.
+func (dlts *DetailLeadTimeStat) groupMarkdown(trendChart string) {
+	g := groupBy(dlts.LeadTimeStatistics.GroupBy)
+	printGroupStatsMarkdown(g.title(), dlts.LeadTimeStatistics.Groups)
+	if g.isTimeSeries() && len(dlts.LeadTimeStatistics.Groups) != 0 {
+		fmt.Printf("![PR Lead Time Trend](%s)\n", trendChart)
+		fmt.Println()
+	}
+}
+
+// groupCSV write group statistics with header row. Fields are separated by comma.
+func (dlts *DetailLeadTimeStat) groupCSV(w io.Writer) error {
+	lts := dlts.LeadTimeStatistics
+	writer := newDelimitedWriter(w, commaCSV)
+
+	records := [][]string{{lts.GroupBy, "total_pr", "lead_time_average", "lead_time_median", "lead_time_p90"}}
+	for _, v := range lts.Groups {
+		records = append(records, []string{
+			v.Group,
+			strconv.Itoa(v.TotalPR),
+			strconv.FormatFloat(v.LeadTimeAverage, 'f', 2, 64),
+			strconv.FormatFloat(v.LeadTimeMedian, 'f', 2, 64),
+			strconv.FormatFloat(v.LeadTimeP90, 'f', 2, 64),
+		})
+	}
+	return writer.WriteAll(records)
+}
+
+// drawTrendGraph draw average, median and p90 lead time of each time bucket
+// and write it to the trend chart file next to the chart file.
+func (dlts *DetailLeadTimeStat) drawTrendGraph(opt *option) error {
+	groups := dlts.LeadTimeStatistics.Groups
+	if len(groups) == 0 {
+		return nil
+	}
+
+	p, err := trendPlot(groups, groupBy(dlts.LeadTimeStatistics.GroupBy).title())
+	if err != nil {
+		return err
+	}
+	return writeChart(p, opt, opt.trendChartPath())
+}
+
+// trendPlot return line graph of average, median and p90 lead time of each group.
+// xLabel is label of X axis (e.g. Week).
+func trendPlot(groups []*GroupStat, xLabel string) (*plot.Plot, error) {
+	p := plot.New()
+	p.X.Label.Text = xLabel
+	p.Y.Label.Text = "Lead Time[min]"
+	p.Y.Min = 0
+	p.Legend.Top = true
+	p.Legend.Left = true
+	p.Add(plotter.NewGrid())
+
+	names := make([]string, 0, len(groups))
+	for _, v := range groups {
+		names = append(names, v.Group)
+	}
+	p.NominalX(names...)
+	p.X.Tick.Label.Rotation = 0.8
+	p.X.Tick.Label.XAlign = -0.8
+
+	lines := []struct {
+		name  string
+		color color.RGBA
+		value func(g *GroupStat) float64
+	}{
+		{name: "Average", color: color.RGBA{R: 226, G: 45, B: 60, A: 255}, value: func(g *GroupStat) float64 { return g.LeadTimeAverage }},
+		{name: "Median", color: color.RGBA{R: 45, G: 110, B: 226, A: 255}, value: func(g *GroupStat) float64 { return g.LeadTimeMedian }},
+		{name: "P90", color: color.RGBA{R: 60, G: 170, B: 80, A: 255}, value: func(g *GroupStat) float64 { return g.LeadTimeP90 }},
+	}
+	for _, l := range lines {
+		data := make(plotter.XYs, 0, len(groups))
+		for i, v := range groups {
+			data = append(data, plotter.XY{X: float64(i), Y: l.value(v)})
+		}
+
+		line, err := plotter.NewLine(data)
+		if err != nil {
+			return nil, err
+		}
+		line.Color = l.color
+		line.Width = vg.Points(1.5)
+		p.Add(line)
+		p.Legend.Add(l.name, line)
+	}
+	return p, nil
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/usecase"
+	"gonum.org/v1/plot/vg"
+)
+
+// groupNumbers is group name and PR numbers for comparison of PR groups.
+type groupNumbers struct {
+	Name    string
+	Numbers []int
+}
+
+func toGroupNumbers(groups []*prGroup) []groupNumbers {
+	got := make([]groupNumbers, 0, len(groups))
+	for _, g := range groups {
+		numbers := make([]int, 0, len(g.prs))
+		for _, v := range g.prs {
+			numbers = append(numbers, v.Number)
+		}
+		got = append(got, groupNumbers{Name: g.name, Numbers: numbers})
+	}
+	return got
+}
+
+func Test_weekStart(t *testing.T) {
+	t.Parallel()
+
+	jst := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{
+			name: "Monday 00:00 UTC is the beginning of the week",
+			t:    time.Date(2023, 2, 20, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2023, 2, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Sunday 23:59 UTC belongs to the week that started on Monday",
+			t:    time.Date(2023, 2, 26, 23, 59, 59, 0, time.UTC),
+			want: time.Date(2023, 2, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Monday morning in JST is Sunday in UTC",
+			t:    time.Date(2023, 2, 27, 8, 0, 0, 0, jst),
+			want: time.Date(2023, 2, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Week across the end of year",
+			t:    time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := weekStart(tt.t); !got.Equal(tt.want) {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_monthStart(t *testing.T) {
+	t.Parallel()
+
+	got := monthStart(time.Date(2023, 3, 1, 1, 0, 0, 0, time.FixedZone("JST", 9*60*60)))
+	if want := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("mismatch want=%v, got=%v", want, got)
+	}
+}
+
+func Test_groupBy_groups_timeSeries(t *testing.T) {
+	t.Parallel()
+
+	merged := func(number int, mergedAt time.Time) *usecase.PullRequest {
+		return &usecase.PullRequest{Number: number, MergedAt: mergedAt}
+	}
+	prs := []*usecase.PullRequest{
+		merged(1, time.Date(2023, 2, 21, 0, 0, 0, 0, time.UTC)),
+		merged(2, time.Date(2023, 3, 7, 0, 0, 0, 0, time.UTC)),
+		merged(3, time.Date(2023, 2, 26, 23, 0, 0, 0, time.UTC)),
+		{Number: 4}, // not merged
+	}
+
+	tests := []struct {
+		name string
+		g    groupBy
+		prs  []*usecase.PullRequest
+		want []groupNumbers
+	}{
+		{
+			name: "Weeks without PR between the first and the last week are included",
+			g:    groupByWeek,
+			prs:  prs,
+			want: []groupNumbers{
+				{Name: "2023-02-20", Numbers: []int{1, 3}},
+				{Name: "2023-02-27", Numbers: []int{}},
+				{Name: "2023-03-06", Numbers: []int{2}},
+			},
+		},
+		{
+			name: "Group by month",
+			g:    groupByMonth,
+			prs:  prs,
+			want: []groupNumbers{
+				{Name: "2023-02", Numbers: []int{1, 3}},
+				{Name: "2023-03", Numbers: []int{2}},
+			},
+		},
+		{
+			name: "No merged PR",
+			g:    groupByWeek,
+			prs:  []*usecase.PullRequest{{Number: 4}},
+			want: []groupNumbers{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := toGroupNumbers(tt.g.groups(tt.prs, nil, nil))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_newGroupStats(t *testing.T) {
+	t.Parallel()
+
+	got := newGroupStats([]*prGroup{
+		{name: "a", prs: []*usecase.PullRequest{{MergeTimeMinutes: 10}, {MergeTimeMinutes: 30}}},
+		{name: "b"},
+	})
+	want := []*GroupStat{
+		{Group: "a", TotalPR: 2, LeadTimeAverage: 20, LeadTimeMedian: 20, LeadTimeP90: 28},
+		{Group: "b"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDetailLeadTimeStat_drawTrendGraph(t *testing.T) {
+	t.Parallel()
+
+	dlts := &DetailLeadTimeStat{
+		LeadTimeStatistics: &LeadTimeStat{
+			GroupBy: string(groupByWeek),
+			Groups: []*GroupStat{
+				{Group: "2023-01-02", TotalPR: 2, LeadTimeAverage: 90, LeadTimeMedian: 90, LeadTimeP90: 114},
+				{Group: "2023-01-09", TotalPR: 1, LeadTimeAverage: 30, LeadTimeMedian: 30, LeadTimeP90: 30},
+			},
+		},
+	}
+	opt := &option{
+		chartOutput: filepath.Join(t.TempDir(), "chart.svg"),
+		chartFormat: "svg",
+		chartWidth:  8 * vg.Inch,
+		chartHeight: 3 * vg.Inch,
+	}
+	if err := dlts.drawTrendGraph(opt); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(filepath.Dir(opt.chartOutput), "chart_trend.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `width="576pt" height="216pt"`; !strings.Contains(string(got), want) {
+		t.Errorf("trend chart does not contain %q", want)
+	}
+}
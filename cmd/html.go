@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shogo82148/pointer"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+)
+
+// htmlReport is data of HTML report.
+type htmlReport struct {
+	GeneratedAt  string
+	TotalPR      int
+	Summary      []*htmlValue
+	TimeToMerge  *htmlStage
+	Stages       []*htmlStage
+	Abandonment  []*htmlValue
+	Repositories []*GroupStat
+	GroupTitle   string
+	Groups       []*GroupStat
+	Charts       []*htmlChart
+	PullRequests []*htmlPullRequest
+}
+
+// htmlValue is statistic value with display name.
+type htmlValue struct {
+	Name  string
+	Value string
+}
+
+// htmlStage is stage statistics with display information.
+type htmlStage struct {
+	Name        string
+	Description string
+	Stat        *StageStat
+}
+
+// htmlChart is chart embedded in HTML report as SVG.
+type htmlChart struct {
+	Title string
+	SVG   template.HTML
+}
+
+// htmlPullRequest is one row of PR detail table.
+type htmlPullRequest struct {
+	Name     string
+	Author   string
+	Bot      bool
+	LeadTime int
+	// TimeToMerge is nil if the PR is not merged.
+	TimeToMerge *int
+	Coding      *int
+	Pickup      *int
+	Review      *int
+	Merge       *int
+	MergedAt    string
+	Title       string
+}
+
+// html write self-contained HTML report that has statistics tables, charts as
+// inline SVG and PR detail table that can be sorted and filtered without network.
+func (dlts *DetailLeadTimeStat) html(w io.Writer, now time.Time) error {
+	lts := dlts.LeadTimeStatistics
+	report := &htmlReport{
+		GeneratedAt:  now.Format(time.RFC3339),
+		TotalPR:      len(dlts.PullRequests),
+		Repositories: lts.Repositories,
+		GroupTitle:   groupBy(lts.GroupBy).title(),
+		Groups:       lts.Groups,
+	}
+
+	report.Summary = []*htmlValue{
+		{Name: "Lead Time(Max)", Value: fmt.Sprintf("%d[min]", dlts.max())},
+		{Name: "Lead Time(Min)", Value: fmt.Sprintf("%d[min]", dlts.min())},
+		{Name: "Lead Time(Sum)", Value: fmt.Sprintf("%d[min]", dlts.sum())},
+		{Name: "Lead Time(Ave)", Value: fmt.Sprintf("%.2f[min]", dlts.average())},
+		{Name: "Lead Time(Median)", Value: fmt.Sprintf("%.2f[min]", dlts.median())},
+	}
+	for _, v := range lts.distribution() {
+		report.Summary = append(report.Summary, &htmlValue{Name: "Lead Time(" + v.name + ")", Value: fmt.Sprintf("%.2f[min]", v.value)})
+	}
+	ttm := lts.timeToMergeSummary()
+	report.TimeToMerge = &htmlStage{Name: ttm.name, Description: ttm.description, Stat: ttm.stat}
+	for _, v := range lts.stageSummaries() {
+		report.Stages = append(report.Stages, &htmlStage{Name: v.name, Description: v.description, Stat: v.stat})
+	}
+
+	for _, v := range lts.Abandonment.items() {
+		report.Abandonment = append(report.Abandonment, &htmlValue{Name: v.name, Value: v.value})
+	}
+
+	charts, err := dlts.htmlCharts()
+	if err != nil {
+		return err
+	}
+	report.Charts = charts
+
+	for _, v := range dlts.PullRequests {
+		pr := &htmlPullRequest{
+			Name:        dlts.prName(v),
+			LeadTime:    v.MergeTimeMinutes,
+			TimeToMerge: v.TimeToMergeMinutes,
+			Coding:      v.CodingTimeMinutes,
+			Pickup:      v.PickupTimeMinutes,
+			Review:      v.ReviewTimeMinutes,
+			Merge:       v.MergeStageTimeMinutes,
+			MergedAt:    rfc3339(v.MergedAt),
+			Title:       v.Title,
+		}
+		if v.User != nil {
+			pr.Author = pointer.StringValue(v.User.Name)
+			pr.Bot = v.User.IsBot()
+		}
+		report.PullRequests = append(report.PullRequests, pr)
+	}
+
+	return htmlTemplate.Execute(w, report)
+}
+
+// htmlCharts return charts of lead time, trend, histogram and per-author lead time.
+// If there is no PR, return no chart. The trend is weekly unless PRs are grouped by time.
+func (dlts *DetailLeadTimeStat) htmlCharts() ([]*htmlChart, error) {
+	if len(dlts.PullRequests) == 0 {
+		return nil, nil
+	}
+
+	groups, title := dlts.LeadTimeStatistics.Groups, groupBy(dlts.LeadTimeStatistics.GroupBy).title()
+	if !groupBy(dlts.LeadTimeStatistics.GroupBy).isTimeSeries() {
+		groups, title = newGroupStats(groupByWeek.groups(dlts.PullRequests, nil, nil)), groupByWeek.title()
+	}
+
+	charts := []struct {
+		title   string
+		newPlot func() (*plot.Plot, error)
+	}{
+		{title: "Lead Time of each PR", newPlot: dlts.leadTimePlot},
+		{title: "Lead Time Trend", newPlot: func() (*plot.Plot, error) { return trendPlot(groups, title) }},
+		{title: "Lead Time Histogram", newPlot: dlts.histogramPlot},
+		{title: "Lead Time by Author", newPlot: dlts.authorPlot},
+	}
+
+	result := make([]*htmlChart, 0, len(charts))
+	for _, v := range charts {
+		p, err := v.newPlot()
+		if err != nil {
+			return nil, err
+		}
+		svg, err := svgString(p, 6*vg.Inch, 4*vg.Inch)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &htmlChart{Title: v.title, SVG: svg})
+	}
+	return result, nil
+}
+
+// svgString return the plot as SVG element that can be embedded in HTML.
+func svgString(p *plot.Plot, width, height vg.Length) (template.HTML, error) {
+	writerTo, err := p.WriterTo(width, height, "svg")
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if _, err := writerTo.WriteTo(&buf); err != nil {
+		return "", err
+	}
+
+	svg := buf.String()
+	// XML declaration is not allowed in the middle of HTML document.
+	if i := strings.Index(svg, "<svg"); i > 0 {
+		svg = svg[i:]
+	}
+	return template.HTML(svg), nil //nolint:gosec // SVG is generated by gonum/plot, not by user input
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"minutes": minutesString,
+	"sortKey": func(minutes *int) string {
+		if minutes == nil {
+			return "-1"
+		}
+		return fmt.Sprint(*minutes)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Pull Request Lead Time</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; }
+th { background: #f6f8fa; }
+#prs th { cursor: pointer; user-select: none; }
+#prs th[data-order="asc"]::after { content: " \25B2"; }
+#prs th[data-order="desc"]::after { content: " \25BC"; }
+.charts { display: flex; flex-wrap: wrap; gap: 2em; }
+.charts figure { margin: 0; }
+#filter { margin-bottom: 1em; padding: 4px; width: 20em; }
+</style>
+</head>
+<body>
+<h1>Pull Request Lead Time</h1>
+<p>Statistics were calculated for {{.TotalPR}} closed PRs. Generated at {{.GeneratedAt}}.</p>
+
+<h2>Statistics</h2>
+<table>
+<tr><th>Item</th><th>Result</th></tr>
+{{- range .Summary}}
+<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Time to Merge Statistics</h2>
+<table>
+<tr><th>Item</th><th>Span</th><th>PRs</th><th>Max</th><th>Min</th><th>Ave</th><th>MN</th></tr>
+{{- with .TimeToMerge}}
+<tr><td>{{.Name}}</td><td>{{.Description}}</td><td>{{.Stat.TotalPR}}</td><td>{{.Stat.Maximum}}[min]</td><td>{{.Stat.Minimum}}[min]</td><td>{{printf "%.2f" .Stat.Average}}[min]</td><td>{{printf "%.2f" .Stat.Median}}[min]</td></tr>
+{{- end}}
+</table>
+
+<h2>Stage Statistics</h2>
+<table>
+<tr><th>Stage</th><th>Span</th><th>PRs</th><th>Max</th><th>Min</th><th>Ave</th><th>MN</th></tr>
+{{- range .Stages}}
+<tr><td>{{.Name}}</td><td>{{.Description}}</td><td>{{.Stat.TotalPR}}</td><td>{{.Stat.Maximum}}[min]</td><td>{{.Stat.Minimum}}[min]</td><td>{{printf "%.2f" .Stat.Average}}[min]</td><td>{{printf "%.2f" .Stat.Median}}[min]</td></tr>
+{{- end}}
+</table>
+
+<h2>Abandonment Statistics</h2>
+<p>PRs closed without merge. Time to abandon is from creating PR to closing PR.</p>
+<table>
+<tr><th>Item</th><th>Result</th></tr>
+{{- range .Abandonment}}
+<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- if .Repositories}}
+
+<h2>Lead Time by Repository</h2>
+<table>
+<tr><th>Repository</th><th>PRs</th><th>Ave</th><th>MN</th><th>P90</th></tr>
+{{- range .Repositories}}
+<tr><td>{{.Group}}</td><td>{{.TotalPR}}</td><td>{{printf "%.2f" .LeadTimeAverage}}[min]</td><td>{{printf "%.2f" .LeadTimeMedian}}[min]</td><td>{{printf "%.2f" .LeadTimeP90}}[min]</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Groups}}
+
+<h2>Lead Time by {{.GroupTitle}}</h2>
+<table>
+<tr><th>{{.GroupTitle}}</th><th>PRs</th><th>Ave</th><th>MN</th><th>P90</th></tr>
+{{- range .Groups}}
+<tr><td>{{.Group}}</td><td>{{.TotalPR}}</td><td>{{printf "%.2f" .LeadTimeAverage}}[min]</td><td>{{printf "%.2f" .LeadTimeMedian}}[min]</td><td>{{printf "%.2f" .LeadTimeP90}}[min]</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Charts}}
+
+<h2>Charts</h2>
+<div class="charts">
+{{- range .Charts}}
+<figure>
+<figcaption>{{.Title}}</figcaption>
+{{.SVG}}
+</figure>
+{{- end}}
+</div>
+{{- end}}
+
+<h2>Pull Request Detail</h2>
+<input id="filter" type="search" placeholder="Filter PRs (e.g. author, title)">
+<table id="prs">
+<thead>
+<tr><th>PR</th><th>Author</th><th>Bot</th><th>LeadTime[min]</th><th>TimeToMerge[min]</th><th>Coding[min]</th><th>Pickup[min]</th><th>Review[min]</th><th>Merge[min]</th><th>Merged At</th><th>Title</th></tr>
+</thead>
+<tbody>
+{{- range .PullRequests}}
+<tr><td>{{.Name}}</td><td>{{.Author}}</td><td>{{if .Bot}}yes{{else}}no{{end}}</td><td data-value="{{.LeadTime}}">{{.LeadTime}}</td><td data-value="{{sortKey .TimeToMerge}}">{{minutes .TimeToMerge}}</td><td data-value="{{sortKey .Coding}}">{{minutes .Coding}}</td><td data-value="{{sortKey .Pickup}}">{{minutes .Pickup}}</td><td data-value="{{sortKey .Review}}">{{minutes .Review}}</td><td data-value="{{sortKey .Merge}}">{{minutes .Merge}}</td><td>{{.MergedAt}}</td><td>{{.Title}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<script>
+(function () {
+  var table = document.getElementById("prs");
+  var headers = table.tHead.rows[0].cells;
+  Array.prototype.forEach.call(headers, function (th, col) {
+    th.addEventListener("click", function () {
+      var asc = th.dataset.order !== "asc";
+      Array.prototype.forEach.call(headers, function (h) { delete h.dataset.order; });
+      th.dataset.order = asc ? "asc" : "desc";
+
+      var tbody = table.tBodies[0];
+      var rows = Array.prototype.slice.call(tbody.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[col].dataset.value, y = b.cells[col].dataset.value;
+        var c = (x !== undefined && y !== undefined) ? parseFloat(x) - parseFloat(y)
+          : a.cells[col].textContent.localeCompare(b.cells[col].textContent);
+        return asc ? c : -c;
+      });
+      rows.forEach(function (r) { tbody.appendChild(r); });
+    });
+  });
+
+  document.getElementById("filter").addEventListener("input", function (e) {
+    var query = e.target.value.toLowerCase();
+    Array.prototype.forEach.call(table.tBodies[0].rows, function (r) {
+      r.style.display = r.textContent.toLowerCase().indexOf(query) >= 0 ? "" : "none";
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/shogo82148/pointer"
+)
+
+func TestDetailLeadTimeStat_html(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Write statistics, inline charts and escaped PR detail", func(t *testing.T) {
+		t.Parallel()
+
+		dlts := &DetailLeadTimeStat{
+			PullRequests: []*usecase.PullRequest{
+				{
+					Number:             1,
+					Status:             usecase.PRStatusMerged,
+					Title:              "<script>alert(1)</script>",
+					User:               &model.User{Name: pointer.String("renovate[bot]"), Bot: true},
+					MergedAt:           time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+					MergeTimeMinutes:   120,
+					TimeToMergeMinutes: pointer.Int(60),
+				},
+				{
+					Number:           2,
+					Status:           usecase.PRStatusMerged,
+					Title:            "fix & refactor",
+					MergedAt:         time.Date(2023, 2, 8, 0, 0, 0, 0, time.UTC),
+					MergeTimeMinutes: 60,
+				},
+			},
+		}
+		dlts.stat(nil)
+
+		var buf bytes.Buffer
+		if err := dlts.html(&buf, now); err != nil {
+			t.Fatal(err)
+		}
+		got := buf.String()
+
+		for _, want := range []string{
+			"Statistics were calculated for 2 closed PRs. Generated at 2023-03-01T00:00:00Z.",
+			"<tr><td>Lead Time(Max)</td><td>120[min]</td></tr>",
+			"<tr><td>Time to Merge</td><td>create PR to merge PR</td><td>1</td>",
+			"<h2>Abandonment Statistics</h2>",
+			"<tr><td>#1</td><td>renovate[bot]</td><td>yes</td><td data-value=\"120\">120</td><td data-value=\"60\">60</td>",
+			"<tr><td>#2</td><td></td><td>no</td><td data-value=\"60\">60</td><td data-value=\"-1\">-</td>",
+			"&lt;script&gt;alert(1)&lt;/script&gt;",
+			"fix &amp; refactor",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("report does not contain %q", want)
+			}
+		}
+		if strings.Contains(got, "<script>alert(1)</script>") {
+			t.Error("PR title is not escaped")
+		}
+		if n := strings.Count(got, "<svg"); n != 4 {
+			t.Errorf("mismatch want=%v, got=%v", 4, n)
+		}
+		if strings.Contains(got, "<?xml") || strings.Contains(got, `src="http`) || strings.Contains(got, `href="http`) {
+			t.Error("report is not self-contained")
+		}
+		if strings.Contains(got, "Lead Time by Repository") {
+			t.Error("repository table is written for single repository")
+		}
+	})
+
+	t.Run("No chart without PR", func(t *testing.T) {
+		t.Parallel()
+
+		dlts := &DetailLeadTimeStat{PullRequests: []*usecase.PullRequest{}}
+		dlts.stat(nil)
+
+		var buf bytes.Buffer
+		if err := dlts.html(&buf, now); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(buf.String(), "<h2>Charts</h2>") {
+			t.Error("charts are written without PR")
+		}
+		if !strings.Contains(buf.String(), "Statistics were calculated for 0 closed PRs.") {
+			t.Errorf("report does not contain number of PRs:\n%s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+)
+
+// unlabeled is group name of PRs that have no label.
+const unlabeled = "unlabeled"
+
+// hasAnyLabel check whether PR has one of labels. Labels are compared case insensitively.
+func hasAnyLabel(pr *usecase.PullRequest, labels []string) bool {
+	for _, v := range pr.Labels {
+		for _, l := range labels {
+			if strings.EqualFold(v, l) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (dlts *DetailLeadTimeStat) keepPRsWithLabel(labels []string) {
+	prs := make([]*usecase.PullRequest, 0, len(dlts.PullRequests))
+	for _, v := range dlts.PullRequests {
+		if !hasAnyLabel(v, labels) {
+			continue
+		}
+		prs = append(prs, v)
+	}
+	dlts.PullRequests = prs
+}
+
+func (dlts *DetailLeadTimeStat) removePRsWithLabel(labels []string) {
+	prs := make([]*usecase.PullRequest, 0, len(dlts.PullRequests))
+	for _, v := range dlts.PullRequests {
+		if hasAnyLabel(v, labels) {
+			continue
+		}
+		prs = append(prs, v)
+	}
+	dlts.PullRequests = prs
+}
+
+// labelGroups return PR group of each label in ascending order of label name.
+// A PR with several labels belongs to each of the groups, so the sum of PRs in
+// groups may be larger than the number of PRs. The group of PRs without label is the last.
+func labelGroups(prs []*usecase.PullRequest) []*prGroup {
+	index := make(map[string]*prGroup)
+	groups := make([]*prGroup, 0)
+	add := func(name string, pr *usecase.PullRequest) {
+		g, ok := index[name]
+		if !ok {
+			g = &prGroup{name: name}
+			index[name] = g
+			groups = append(groups, g)
+		}
+		g.prs = append(g.prs, pr)
+	}
+
+	for _, v := range prs {
+		if len(v.Labels) == 0 {
+			add(unlabeled, v)
+			continue
+		}
+		seen := make(map[string]bool, len(v.Labels))
+		for _, l := range v.Labels {
+			if seen[l] {
+				continue
+			}
+			seen[l] = true
+			add(l, v)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].name == unlabeled) != (groups[j].name == unlabeled) {
+			return groups[j].name == unlabeled
+		}
+		return groups[i].name < groups[j].name
+	})
+	return groups
+}
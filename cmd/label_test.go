@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/usecase"
+)
+
+func Test_labelGroups(t *testing.T) {
+	t.Parallel()
+
+	prs := []*usecase.PullRequest{
+		{Number: 1, Labels: []string{"feature", "bug"}},
+		{Number: 2},
+		{Number: 3, Labels: []string{"bug", "bug"}},
+		{Number: 4, Labels: []string{"docs"}},
+	}
+
+	want := []groupNumbers{
+		{Name: "bug", Numbers: []int{1, 3}},
+		{Name: "docs", Numbers: []int{4}},
+		{Name: "feature", Numbers: []int{1}},
+		{Name: unlabeled, Numbers: []int{2}},
+	}
+	if diff := cmp.Diff(want, toGroupNumbers(labelGroups(prs))); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDetailLeadTimeStat_label(t *testing.T) {
+	t.Parallel()
+
+	newStat := func() *DetailLeadTimeStat {
+		return &DetailLeadTimeStat{PullRequests: []*usecase.PullRequest{
+			{Number: 1, Labels: []string{"Bug"}},
+			{Number: 2},
+			{Number: 3, Labels: []string{"docs", "dependencies"}},
+		}}
+	}
+
+	t.Run("Keep PRs with any label case insensitively", func(t *testing.T) {
+		t.Parallel()
+
+		dlts := newStat()
+		dlts.keepPRsWithLabel([]string{"bug", "dependencies"})
+		if diff := cmp.Diff([]int{1, 3}, prNumbers(dlts.PullRequests)); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Remove PRs with any label case insensitively", func(t *testing.T) {
+		t.Parallel()
+
+		dlts := newStat()
+		dlts.removePRsWithLabel([]string{"BUG"})
+		if diff := cmp.Diff([]int{2, 3}, prNumbers(dlts.PullRequests)); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/shogo82148/pointer"
+)
+
+const (
+	// contentTypeOpenMetrics is content type of OpenMetrics text format.
+	contentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	// contentTypePrometheus is content type of Prometheus text format.
+	contentTypePrometheus = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// durationBuckets is upper bounds of histogram buckets in seconds
+// (1h, 4h, 8h, 1d, 2d, 3d, 1w, 2w, 4w).
+var durationBuckets = []float64{3600, 14400, 28800, 86400, 172800, 259200, 604800, 1209600, 2419200}
+
+// metrics is lead time metrics exposed in OpenMetrics text format.
+type metrics struct {
+	// leadTime is lead time histogram of each repository and author.
+	leadTime map[metricLabels]*histogram
+	// timeToMerge is time to merge histogram of each repository and author.
+	timeToMerge map[metricLabels]*histogram
+	// stageTime is stage time histogram of each repository, author and stage.
+	stageTime map[metricLabels]*histogram
+	// openPRs is open PRs of each repository and author.
+	openPRs map[metricLabels]int
+	// openPRAges is age of each open PR in seconds.
+	openPRAges map[metricLabels]float64
+	// refreshedAt is the time when metrics were calculated.
+	refreshedAt time.Time
+}
+
+// metricLabels is label values of one time series. Empty value is not output.
+type metricLabels struct {
+	repository string
+	author     string
+	stage      string
+	number     string
+}
+
+// histogram is cumulative histogram of durations in seconds.
+type histogram struct {
+	// buckets is number of observations less than or equal to each durationBuckets.
+	buckets []int
+	count   int
+	sum     float64
+}
+
+// observe add duration in seconds to the histogram.
+func (h *histogram) observe(seconds float64) {
+	if h.buckets == nil {
+		h.buckets = make([]int, len(durationBuckets))
+	}
+	for i, v := range durationBuckets {
+		if seconds <= v {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// newMetrics calculate metrics of closed PRs and open PRs.
+// The age of open PRs is the time from PR creation to now.
+func newMetrics(closed, open []*usecase.PullRequest, now time.Time) *metrics {
+	m := &metrics{
+		leadTime:    make(map[metricLabels]*histogram),
+		timeToMerge: make(map[metricLabels]*histogram),
+		stageTime:   make(map[metricLabels]*histogram),
+		openPRs:     make(map[metricLabels]int),
+		openPRAges:  make(map[metricLabels]float64),
+		refreshedAt: now,
+	}
+
+	stages := []struct {
+		name  string
+		stage func(pr *usecase.PullRequest) *int
+	}{
+		{name: "coding", stage: codingTime},
+		{name: "pickup", stage: pickupTime},
+		{name: "review", stage: reviewTime},
+		{name: "merge", stage: mergeStageTime},
+	}
+	for _, v := range closed {
+		labels := metricLabels{repository: v.Repository, author: authorName(v)}
+		observe(m.leadTime, labels, v.MergeTimeMinutes)
+		if v.TimeToMergeMinutes != nil {
+			observe(m.timeToMerge, labels, *v.TimeToMergeMinutes)
+		}
+		for _, s := range stages {
+			if minutes := s.stage(v); minutes != nil {
+				observe(m.stageTime, metricLabels{repository: labels.repository, author: labels.author, stage: s.name}, *minutes)
+			}
+		}
+	}
+
+	for _, v := range open {
+		labels := metricLabels{repository: v.Repository, author: authorName(v)}
+		m.openPRs[labels]++
+		if v.CreatedAt.IsZero() {
+			continue
+		}
+		labels.number = strconv.Itoa(v.Number)
+		m.openPRAges[labels] = now.Sub(v.CreatedAt).Truncate(time.Second).Seconds()
+	}
+	return m
+}
+
+// observe add minutes to the histogram of labels.
+func observe(histograms map[metricLabels]*histogram, labels metricLabels, minutes int) {
+	h, ok := histograms[labels]
+	if !ok {
+		h = &histogram{}
+		histograms[labels] = h
+	}
+	h.observe(float64(minutes * 60))
+}
+
+// authorName return login name of PR author. If it is unknown, return "unknown".
+func authorName(pr *usecase.PullRequest) string {
+	if pr.User == nil || pointer.StringValue(pr.User.Name) == "" {
+		return "unknown"
+	}
+	return pointer.StringValue(pr.User.Name)
+}
+
+// write write metrics in Prometheus text format. If openMetrics is true, write
+// OpenMetrics text format that ends with "# EOF". Time series are sorted by labels.
+//
+// Histograms are calculated from closed PRs in the date range at each refresh, so
+// their count and sum decrease when PRs leave a rolling --since window. Therefore
+// they are gauge histograms, not counters. Prometheus text format has no gauge
+// histogram type, so the same samples are untyped in it.
+func (m *metrics) write(w io.Writer, openMetrics bool, refreshSucceeded bool) error {
+	var b strings.Builder
+
+	histogramType := "gaugehistogram"
+	if !openMetrics {
+		histogramType = "untyped"
+	}
+
+	writeHeader(&b, "leadtime_lead_time_seconds", histogramType, "Lead time from first commit to merge of closed PRs.")
+	for _, labels := range sortedLabels(histogramLabels(m.leadTime)) {
+		m.leadTime[labels].write(&b, "leadtime_lead_time_seconds", labels)
+	}
+
+	writeHeader(&b, "leadtime_time_to_merge_seconds", histogramType, "Time from creation to merge of merged PRs.")
+	for _, labels := range sortedLabels(histogramLabels(m.timeToMerge)) {
+		m.timeToMerge[labels].write(&b, "leadtime_time_to_merge_seconds", labels)
+	}
+
+	writeHeader(&b, "leadtime_stage_time_seconds", histogramType, "Time of each stage (coding, pickup, review, merge) of closed PRs.")
+	for _, labels := range sortedLabels(histogramLabels(m.stageTime)) {
+		m.stageTime[labels].write(&b, "leadtime_stage_time_seconds", labels)
+	}
+
+	writeHeader(&b, "leadtime_open_pull_requests", "gauge", "Number of open PRs.")
+	for _, labels := range sortedLabels(countLabels(m.openPRs)) {
+		writeSample(&b, "leadtime_open_pull_requests", labels, "", float64(m.openPRs[labels]))
+	}
+
+	writeHeader(&b, "leadtime_open_pull_request_age_seconds", "gauge", "Time from creation of each open PR to the last refresh.")
+	for _, labels := range sortedLabels(ageLabels(m.openPRAges)) {
+		writeSample(&b, "leadtime_open_pull_request_age_seconds", labels, "", m.openPRAges[labels])
+	}
+
+	writeHeader(&b, "leadtime_last_refresh_timestamp_seconds", "gauge", "Unix time when the metrics were calculated.")
+	writeSample(&b, "leadtime_last_refresh_timestamp_seconds", metricLabels{}, "", float64(m.refreshedAt.Unix()))
+
+	success := 0.0
+	if refreshSucceeded {
+		success = 1
+	}
+	writeHeader(&b, "leadtime_last_refresh_success", "gauge", "Whether the last refresh succeeded (1) or not (0).")
+	writeSample(&b, "leadtime_last_refresh_success", metricLabels{}, "", success)
+
+	if openMetrics {
+		b.WriteString("# EOF\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// write write buckets, sum and count of the gauge histogram. Sum and count have
+// "_gsum" and "_gcount" suffixes as OpenMetrics gauge histogram requires.
+func (h *histogram) write(b *strings.Builder, name string, labels metricLabels) {
+	for i, v := range durationBuckets {
+		writeSample(b, name+"_bucket", labels, strconv.FormatFloat(v, 'f', 1, 64), float64(h.buckets[i]))
+	}
+	writeSample(b, name+"_bucket", labels, "+Inf", float64(h.count))
+	writeSample(b, name+"_gsum", labels, "", h.sum)
+	writeSample(b, name+"_gcount", labels, "", float64(h.count))
+}
+
+// writeHeader write TYPE and HELP of metric family.
+func writeHeader(b *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, metricType)
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+}
+
+// writeSample write one sample. If le is not empty, it is added as the last label.
+func writeSample(b *strings.Builder, name string, labels metricLabels, le string, value float64) {
+	pairs := make([]string, 0, 5)
+	for _, v := range []struct{ name, value string }{
+		{name: "repository", value: labels.repository},
+		{name: "author", value: labels.author},
+		{name: "stage", value: labels.stage},
+		{name: "number", value: labels.number},
+		{name: "le", value: le},
+	} {
+		if v.value != "" {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", v.name, escapeLabelValue(v.value)))
+		}
+	}
+
+	b.WriteString(name)
+	if len(pairs) != 0 {
+		b.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	b.WriteString(" " + strconv.FormatFloat(value, 'f', -1, 64) + "\n")
+}
+
+// escapeLabelValue escape backslash, double quote and newline in label value.
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// histogramLabels return labels of histograms.
+func histogramLabels(m map[metricLabels]*histogram) []metricLabels {
+	labels := make([]metricLabels, 0, len(m))
+	for k := range m {
+		labels = append(labels, k)
+	}
+	return labels
+}
+
+// countLabels return labels of counts.
+func countLabels(m map[metricLabels]int) []metricLabels {
+	labels := make([]metricLabels, 0, len(m))
+	for k := range m {
+		labels = append(labels, k)
+	}
+	return labels
+}
+
+// ageLabels return labels of ages.
+func ageLabels(m map[metricLabels]float64) []metricLabels {
+	labels := make([]metricLabels, 0, len(m))
+	for k := range m {
+		labels = append(labels, k)
+	}
+	return labels
+}
+
+// sortedLabels sort labels by repository, author, stage and PR number.
+func sortedLabels(labels []metricLabels) []metricLabels {
+	sort.Slice(labels, func(i, j int) bool {
+		a, b := labels[i], labels[j]
+		if a.repository != b.repository {
+			return a.repository < b.repository
+		}
+		if a.author != b.author {
+			return a.author < b.author
+		}
+		if a.stage != b.stage {
+			return a.stage < b.stage
+		}
+		x, _ := strconv.Atoi(a.number) //nolint:errcheck // number is formatted by strconv.Itoa
+		y, _ := strconv.Atoi(b.number) //nolint:errcheck // number is formatted by strconv.Itoa
+		return x < y
+	})
+	return labels
+}
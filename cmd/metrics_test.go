@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/shogo82148/pointer"
+)
+
+func Test_newMetrics(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	alice := &model.User{Name: pointer.String("alice")}
+	closed := []*usecase.PullRequest{
+		{
+			Repository:         "o/r",
+			Number:             1,
+			User:               alice,
+			MergeTimeMinutes:   90,
+			TimeToMergeMinutes: pointer.Int(60),
+			CodingTimeMinutes:  pointer.Int(30),
+		},
+		{
+			Repository:       "o/r",
+			Number:           2,
+			User:             alice,
+			MergeTimeMinutes: 10 * 24 * 60,
+		},
+		{
+			Repository:       "o/r",
+			Number:           3,
+			MergeTimeMinutes: 1,
+		},
+	}
+	open := []*usecase.PullRequest{
+		{Repository: "o/r", Number: 4, User: alice, CreatedAt: now.Add(-2*time.Hour - 500*time.Millisecond)},
+		{Repository: "o/r", Number: 5, User: alice},
+	}
+
+	got := newMetrics(closed, open, now)
+
+	aliceLabels := metricLabels{repository: "o/r", author: "alice"}
+	unknownLabels := metricLabels{repository: "o/r", author: "unknown"}
+	wantLeadTime := map[metricLabels]*histogram{
+		aliceLabels: {
+			// 5400 seconds is in 4h or more buckets, and 10 days is in 2w or more buckets.
+			buckets: []int{0, 1, 1, 1, 1, 1, 1, 2, 2},
+			count:   2,
+			sum:     5400 + 864000,
+		},
+		unknownLabels: {
+			buckets: []int{1, 1, 1, 1, 1, 1, 1, 1, 1},
+			count:   1,
+			sum:     60,
+		},
+	}
+	if diff := cmp.Diff(wantLeadTime, got.leadTime, cmp.AllowUnexported(histogram{})); diff != "" {
+		t.Errorf("lead time mismatch (-want +got):\n%s", diff)
+	}
+
+	wantTimeToMerge := map[metricLabels]*histogram{
+		aliceLabels: {buckets: []int{1, 1, 1, 1, 1, 1, 1, 1, 1}, count: 1, sum: 3600},
+	}
+	if diff := cmp.Diff(wantTimeToMerge, got.timeToMerge, cmp.AllowUnexported(histogram{})); diff != "" {
+		t.Errorf("time to merge mismatch (-want +got):\n%s", diff)
+	}
+
+	codingLabels := metricLabels{repository: "o/r", author: "alice", stage: "coding"}
+	if h, ok := got.stageTime[codingLabels]; !ok || h.count != 1 || h.sum != 1800 {
+		t.Errorf("mismatch want=%v, got=%v", &histogram{count: 1, sum: 1800}, h)
+	}
+
+	if diff := cmp.Diff(map[metricLabels]int{aliceLabels: 2}, got.openPRs); diff != "" {
+		t.Errorf("open PRs mismatch (-want +got):\n%s", diff)
+	}
+	wantAges := map[metricLabels]float64{
+		{repository: "o/r", author: "alice", number: "4"}: 7200,
+	}
+	if diff := cmp.Diff(wantAges, got.openPRAges); diff != "" {
+		t.Errorf("open PR ages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_metrics_write(t *testing.T) {
+	t.Parallel()
+
+	labels := metricLabels{repository: "o/r", author: "a\"b\\c\nd"}
+	h := &histogram{}
+	h.observe(5400)
+	h.observe(864000)
+	m := &metrics{
+		leadTime:    map[metricLabels]*histogram{labels: h},
+		timeToMerge: map[metricLabels]*histogram{},
+		stageTime:   map[metricLabels]*histogram{},
+		openPRs:     map[metricLabels]int{labels: 1},
+		openPRAges:  map[metricLabels]float64{{repository: "o/r", author: "alice", number: "4"}: 7200},
+		refreshedAt: time.Unix(1677672000, 0),
+	}
+
+	t.Run("Write gauge histogram with escaped labels in OpenMetrics format", func(t *testing.T) {
+		t.Parallel()
+
+		var b bytes.Buffer
+		if err := m.write(&b, true, true); err != nil {
+			t.Fatal(err)
+		}
+		got := b.String()
+
+		for _, want := range []string{
+			"# TYPE leadtime_lead_time_seconds gaugehistogram\n",
+			`leadtime_lead_time_seconds_bucket{repository="o/r",author="a\"b\\c\nd",le="3600.0"} 0` + "\n",
+			`leadtime_lead_time_seconds_bucket{repository="o/r",author="a\"b\\c\nd",le="14400.0"} 1` + "\n",
+			`leadtime_lead_time_seconds_bucket{repository="o/r",author="a\"b\\c\nd",le="604800.0"} 1` + "\n",
+			`leadtime_lead_time_seconds_bucket{repository="o/r",author="a\"b\\c\nd",le="1209600.0"} 2` + "\n",
+			`leadtime_lead_time_seconds_bucket{repository="o/r",author="a\"b\\c\nd",le="+Inf"} 2` + "\n",
+			`leadtime_lead_time_seconds_gsum{repository="o/r",author="a\"b\\c\nd"} 869400` + "\n",
+			`leadtime_lead_time_seconds_gcount{repository="o/r",author="a\"b\\c\nd"} 2` + "\n",
+			`leadtime_open_pull_requests{repository="o/r",author="a\"b\\c\nd"} 1` + "\n",
+			`leadtime_open_pull_request_age_seconds{repository="o/r",author="alice",number="4"} 7200` + "\n",
+			"leadtime_last_refresh_timestamp_seconds 1677672000\n",
+			"leadtime_last_refresh_success 1\n",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("output does not contain %q:\n%s", want, got)
+			}
+		}
+		if !strings.HasSuffix(got, "\n# EOF\n") {
+			t.Errorf("OpenMetrics output does not end with # EOF:\n%s", got)
+		}
+	})
+
+	t.Run("Write Prometheus format without EOF after failed refresh", func(t *testing.T) {
+		t.Parallel()
+
+		var b bytes.Buffer
+		if err := m.write(&b, false, false); err != nil {
+			t.Fatal(err)
+		}
+		got := b.String()
+
+		if strings.Contains(got, "# EOF") {
+			t.Errorf("Prometheus output contains # EOF:\n%s", got)
+		}
+		if want := "# TYPE leadtime_lead_time_seconds untyped\n"; !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+		if !strings.HasSuffix(got, "leadtime_last_refresh_success 0\n") {
+			t.Errorf("output does not end with failed refresh:\n%s", got)
+		}
+	})
+}
+
+func Test_metrics_write_shrinkingWindow(t *testing.T) {
+	t.Parallel()
+
+	alice := &model.User{Name: pointer.String("alice")}
+	first := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	// PR #1 leaves the rolling window at the second refresh.
+	refreshes := []struct {
+		now    time.Time
+		closed []*usecase.PullRequest
+	}{
+		{
+			now: first,
+			closed: []*usecase.PullRequest{
+				{Repository: "o/r", Number: 1, User: alice, MergeTimeMinutes: 60},
+				{Repository: "o/r", Number: 2, User: alice, MergeTimeMinutes: 120},
+			},
+		},
+		{
+			now: first.Add(24 * time.Hour),
+			closed: []*usecase.PullRequest{
+				{Repository: "o/r", Number: 2, User: alice, MergeTimeMinutes: 120},
+			},
+		},
+	}
+
+	got := make([]string, 0, len(refreshes))
+	for _, v := range refreshes {
+		var b bytes.Buffer
+		if err := newMetrics(v.closed, nil, v.now).write(&b, true, true); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, b.String())
+	}
+
+	for i, want := range [][]string{
+		{
+			`leadtime_lead_time_seconds_bucket{repository="o/r",author="alice",le="3600.0"} 1` + "\n",
+			`leadtime_lead_time_seconds_gsum{repository="o/r",author="alice"} 10800` + "\n",
+			`leadtime_lead_time_seconds_gcount{repository="o/r",author="alice"} 2` + "\n",
+		},
+		{
+			`leadtime_lead_time_seconds_bucket{repository="o/r",author="alice",le="3600.0"} 0` + "\n",
+			`leadtime_lead_time_seconds_gsum{repository="o/r",author="alice"} 7200` + "\n",
+			`leadtime_lead_time_seconds_gcount{repository="o/r",author="alice"} 1` + "\n",
+		},
+	} {
+		// Decreasing count and sum are valid only for gauge histogram.
+		for _, w := range append(want, "# TYPE leadtime_lead_time_seconds gaugehistogram\n") {
+			if !strings.Contains(got[i], w) {
+				t.Errorf("refresh %d output does not contain %q:\n%s", i+1, w, got[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"github.com/nao1215/leadtime/config"
+	"github.com/nao1215/leadtime/di"
+)
+
+// provider is the service that hosts repositories.
+type provider string
+
+const (
+	// providerGitHub means GitHub or GitHub Enterprise Server
+	providerGitHub provider = "github"
+	// providerGitLab means GitLab.com or self-managed GitLab
+	providerGitLab provider = "gitlab"
+	// providerGitea means Gitea
+	providerGitea provider = "gitea"
+	// providerForgejo means Forgejo. Forgejo has the same API as Gitea.
+	providerForgejo provider = "forgejo"
+	// providerBitbucket means Bitbucket Cloud or Bitbucket Server/Data Center
+	providerBitbucket provider = "bitbucket"
+)
+
+// valid check whether provider is supported or not.
+func (p provider) valid() bool {
+	switch p {
+	case providerGitHub, providerGitLab, providerGitea, providerForgejo, providerBitbucket:
+		return true
+	default:
+		return false
+	}
+}
+
+// newLeadTime initialize usecase set for the provider specified by option.
+// If --from-file is specified, the exported file is used instead of the provider.
+// If --git-dir is specified, the local git repository is used instead of the provider.
+func newLeadTime(opt *option) (*di.LeadTime, error) {
+	if opt.fromFile != "" {
+		return di.NewFileLeadTime(&config.FileConfig{Path: opt.fromFile})
+	}
+	if opt.gitDir != "" {
+		return di.NewGitLeadTime(&config.GitConfig{Dir: opt.gitDir})
+	}
+
+	cacheConfig, err := config.NewCacheConfig()
+	if err != nil {
+		return nil, err
+	}
+	cacheConfig.Enabled = opt.cache
+
+	switch opt.provider {
+	case providerGitLab:
+		return newGitLabLeadTime(opt, cacheConfig)
+	case providerGitea, providerForgejo:
+		return newGiteaLeadTime(opt, cacheConfig)
+	case providerBitbucket:
+		return newBitbucketLeadTime(opt, cacheConfig)
+	default:
+		return newGitHubLeadTime(opt, cacheConfig)
+	}
+}
+
+func newGitHubLeadTime(opt *option, cacheConfig *config.CacheConfig) (*di.LeadTime, error) {
+	githubConfig, err := config.NewGitHubConfig()
+	if err != nil {
+		return nil, err
+	}
+	githubConfig.GraphQL = opt.graphQL
+	if opt.apiURL != "" {
+		githubConfig.APIURL = opt.apiURL
+		if err := githubConfig.Valid(); err != nil {
+			return nil, err
+		}
+	}
+	return di.NewLeadTime(githubConfig, cacheConfig)
+}
+
+func newGitLabLeadTime(opt *option, cacheConfig *config.CacheConfig) (*di.LeadTime, error) {
+	gitlabConfig, err := config.NewGitLabConfig()
+	if err != nil {
+		return nil, err
+	}
+	if opt.apiURL != "" {
+		gitlabConfig.URL = opt.apiURL
+		if err := gitlabConfig.Valid(); err != nil {
+			return nil, err
+		}
+	}
+	return di.NewGitLabLeadTime(gitlabConfig, cacheConfig)
+}
+
+func newGiteaLeadTime(opt *option, cacheConfig *config.CacheConfig) (*di.LeadTime, error) {
+	giteaConfig, err := config.NewGiteaConfig()
+	if err != nil {
+		return nil, err
+	}
+	if opt.apiURL != "" {
+		giteaConfig.URL = opt.apiURL
+		if err := giteaConfig.Valid(); err != nil {
+			return nil, err
+		}
+	}
+	return di.NewGiteaLeadTime(giteaConfig, cacheConfig)
+}
+
+func newBitbucketLeadTime(opt *option, cacheConfig *config.CacheConfig) (*di.LeadTime, error) {
+	bitbucketConfig, err := config.NewBitbucketConfig()
+	if err != nil {
+		return nil, err
+	}
+	if opt.apiURL != "" {
+		bitbucketConfig.URL = opt.apiURL
+		if err := bitbucketConfig.Valid(); err != nil {
+			return nil, err
+		}
+	}
+	return di.NewBitbucketLeadTime(bitbucketConfig, cacheConfig)
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+)
+
+const (
+	// pushTimeout is timeout of each push request.
+	pushTimeout = 30 * time.Second
+	// otlpMetricsPath is default path of OTLP/HTTP metrics endpoint.
+	otlpMetricsPath = "/v1/metrics"
+)
+
+// validPushURL check whether u is absolute http or https URL.
+func validPushURL(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// parseHeaders parse headers in "key=value" format.
+func parseHeaders(headers []string) (http.Header, error) {
+	result := make(http.Header, len(headers))
+	for _, v := range headers {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidOTLPHeader, v)
+		}
+		result.Add(strings.TrimSpace(key), value)
+	}
+	return result, nil
+}
+
+// aggregateRepositoryLabel is repository label of statistics of all target repositories.
+// It never conflicts with repository names because they are owner/name format.
+const aggregateRepositoryLabel = "all"
+
+// pushGroup is statistics pushed with one repository label.
+type pushGroup struct {
+	repository string
+	columns    []summaryColumn
+}
+
+// pushGroups return statistics pushed for target repositories. For one repository, all
+// statistics are pushed with its name. For multiple repositories, statistics of each
+// repository are pushed with its name, and all statistics of all repositories are pushed
+// with aggregateRepositoryLabel.
+func (dlts *DetailLeadTimeStat) pushGroups(owner string, repos []*usecase.Repository) []*pushGroup {
+	columns := dlts.LeadTimeStatistics.summaryColumns()
+	switch len(repos) {
+	case 0:
+		return []*pushGroup{{repository: owner, columns: columns}}
+	case 1:
+		return []*pushGroup{{repository: repos[0].FullName(), columns: columns}}
+	}
+
+	groups := make([]*pushGroup, 0, len(dlts.LeadTimeStatistics.Repositories)+1)
+	for _, v := range dlts.LeadTimeStatistics.Repositories {
+		groups = append(groups, &pushGroup{repository: v.Group, columns: v.summaryColumns()})
+	}
+	return append(groups, &pushGroup{repository: aggregateRepositoryLabel, columns: columns})
+}
+
+// push push lead time statistics to Prometheus Pushgateway and OTLP/HTTP endpoint
+// specified by option. Each value has job and repository labels.
+func (dlts *DetailLeadTimeStat) push(ctx context.Context, opt *option, repos []*usecase.Repository, now time.Time) error {
+	groups := dlts.pushGroups(opt.gitHubOwner, repos)
+	client := &http.Client{Timeout: pushTimeout}
+
+	if opt.pushGateway != "" {
+		for _, v := range groups {
+			if err := pushGateway(ctx, client, opt.pushGateway, opt.pushJob, v.repository, v.columns); err != nil {
+				return err
+			}
+		}
+	}
+	if opt.otlpEndpoint != "" {
+		if err := pushOTLP(ctx, client, opt.otlpEndpoint, opt.otlpHeaders, opt.pushJob, groups, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pushGateway replace the metrics group of job and repository in Pushgateway with statistics.
+// Values are pushed as gauges whose name ends with the unit (e.g. "_minutes").
+func pushGateway(ctx context.Context, client *http.Client, gateway, job, repository string, columns []summaryColumn) error {
+	var body bytes.Buffer
+	for _, v := range columns {
+		name := prometheusName(v)
+		fmt.Fprintf(&body, "# TYPE %s gauge\n", name)
+		fmt.Fprintf(&body, "%s %s\n", name, strconv.FormatFloat(v.value, 'f', -1, 64))
+	}
+
+	endpoint := strings.TrimSuffix(gateway, "/") + "/metrics/" +
+		groupingKey("job", job) + "/" + groupingKey("repository", repository)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentTypePrometheus)
+	return send(client, req)
+}
+
+// prometheusName return Prometheus metric name of summary column with the unit suffix.
+// Number of PRs has no suffix. Characters that are not allowed in metric name
+// (e.g. "." of p99.9) are replaced with "_".
+func prometheusName(column summaryColumn) string {
+	name := "leadtime_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == ':' {
+			return r
+		}
+		return '_'
+	}, column.name)
+
+	switch column.unit {
+	case unitMinutes:
+		return name + "_minutes"
+	case unitPercent:
+		return name + "_percent"
+	default:
+		return name
+	}
+}
+
+// groupingKey return path element of Pushgateway grouping key. A value that includes
+// "/" or is empty is encoded in base64 as Pushgateway requires.
+func groupingKey(name, value string) string {
+	switch {
+	case value == "":
+		return name + "@base64/="
+	case strings.Contains(value, "/"):
+		return name + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	default:
+		return name + "/" + url.PathEscape(value)
+	}
+}
+
+// pushOTLP send statistics to OTLP/HTTP endpoint in JSON encoding. If endpoint has no path,
+// "/v1/metrics" is used. job is the service.name resource attribute, so that it becomes
+// job label in Prometheus, and repository of each group is the attribute of each data point.
+func pushOTLP(ctx context.Context, client *http.Client, endpoint string, headers http.Header, job string, groups []*pushGroup, now time.Time) error { //nolint
+	timestamp := strconv.FormatInt(now.UnixNano(), 10)
+
+	metrics := make([]otlpMetric, 0)
+	index := make(map[string]int)
+	for _, g := range groups {
+		attributes := []otlpAttribute{{Key: "repository", Value: otlpAnyValue{StringValue: g.repository}}}
+		for _, v := range g.columns {
+			i, ok := index[v.name]
+			if !ok {
+				i = len(metrics)
+				index[v.name] = i
+				metrics = append(metrics, otlpMetric{Name: "leadtime." + v.name, Unit: v.unit})
+			}
+			metrics[i].Gauge.DataPoints = append(metrics[i].Gauge.DataPoints, otlpDataPoint{
+				Attributes:   attributes,
+				TimeUnixNano: timestamp,
+				AsDouble:     v.value,
+			})
+		}
+	}
+
+	body, err := json.Marshal(&otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			{Key: "service.name", Value: otlpAnyValue{StringValue: job}},
+		}},
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope:   otlpScope{Name: "github.com/nao1215/leadtime", Version: Version},
+			Metrics: metrics,
+		}},
+	}}})
+	if err != nil {
+		return err
+	}
+
+	if u, err := url.Parse(endpoint); err == nil && (u.Path == "" || u.Path == "/") {
+		endpoint = strings.TrimSuffix(endpoint, "/") + otlpMetricsPath
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return send(client, req)
+}
+
+// send send the request. If response status is not 2xx, return error with the response body.
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512)) //nolint:errcheck // body is only for error message
+		return fmt.Errorf("%w: %s %s: %s: %s", ErrPushFailed, req.Method, req.URL.Redacted(), resp.Status, strings.TrimSpace(string(body)))
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
+
+// otlpRequest is ExportMetricsServiceRequest of OTLP in JSON encoding.
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpMetric struct {
+	Name  string    `json:"name"`
+	Unit  string    `json:"unit"`
+	Gauge otlpGauge `json:"gauge"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpDataPoint struct {
+	Attributes []otlpAttribute `json:"attributes"`
+	// TimeUnixNano is string because OTLP JSON encodes 64 bit integer as string.
+	TimeUnixNano string  `json:"timeUnixNano"`
+	AsDouble     float64 `json:"asDouble"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/usecase"
+)
+
+// recordedRequest is request received by the push test server.
+type recordedRequest struct {
+	method      string
+	path        string
+	contentType string
+	auth        string
+	body        string
+}
+
+// newPushTestServer return server that records requests.
+func newPushTestServer(t *testing.T) (*httptest.Server, func() []recordedRequest) {
+	t.Helper()
+
+	var mu sync.Mutex
+	requests := make([]recordedRequest, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, recordedRequest{
+			method:      r.Method,
+			path:        r.URL.EscapedPath(),
+			contentType: r.Header.Get("Content-Type"),
+			auth:        r.Header.Get("Authorization"),
+			body:        string(body),
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []recordedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func Test_prometheusName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		column summaryColumn
+		want   string
+	}{
+		{column: summaryColumn{name: "total_pr", unit: unitPullRequests}, want: "leadtime_total_pr"},
+		{column: summaryColumn{name: "lead_time_p90", unit: unitMinutes}, want: "leadtime_lead_time_p90_minutes"},
+		{column: summaryColumn{name: "lead_time_p99.9", unit: unitMinutes}, want: "leadtime_lead_time_p99_9_minutes"},
+		{column: summaryColumn{name: "abandonment_closed_pr", unit: unitPullRequests}, want: "leadtime_abandonment_closed_pr"},
+		{column: summaryColumn{name: "abandonment_abandoned_pr", unit: unitPullRequests}, want: "leadtime_abandonment_abandoned_pr"},
+		{column: summaryColumn{name: "abandonment_rate", unit: unitPercent}, want: "leadtime_abandonment_rate_percent"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.column.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := prometheusName(tt.column); got != tt.want {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_pushGateway(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPushTestServer(t)
+	columns := []summaryColumn{
+		{name: "total_pr", value: 3, integer: true, unit: unitPullRequests},
+		{name: "lead_time_p99.9", value: 1.5, unit: unitMinutes},
+	}
+	if err := pushGateway(context.Background(), server.Client(), server.URL+"/", "nightly", "nao1215/leadtime", columns); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []recordedRequest{{
+		method:      http.MethodPut,
+		path:        "/metrics/job/nightly/repository@base64/bmFvMTIxNS9sZWFkdGltZQ",
+		contentType: contentTypePrometheus,
+		body: "# TYPE leadtime_total_pr gauge\n" +
+			"leadtime_total_pr 3\n" +
+			"# TYPE leadtime_lead_time_p99_9_minutes gauge\n" +
+			"leadtime_lead_time_p99_9_minutes 1.5\n",
+	}}
+	if diff := cmp.Diff(want, requests(), cmp.AllowUnexported(recordedRequest{})); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_pushGateway_abandonment(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPushTestServer(t)
+	lts := &LeadTimeStat{
+		Abandonment: &AbandonmentStat{ClosedPR: 4, AbandonedPR: 1, Rate: 25, TimeMaximum: 30, TimeP90: 27},
+	}
+	if err := pushGateway(context.Background(), server.Client(), server.URL, "nightly", "o/r", lts.summaryColumns()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := requests()
+	if len(got) != 1 {
+		t.Fatalf("mismatch want=%v, got=%v", 1, len(got))
+	}
+	for _, want := range []string{
+		"leadtime_abandonment_closed_pr 4\n",
+		"leadtime_abandonment_abandoned_pr 1\n",
+		"leadtime_abandonment_rate_percent 25\n",
+		"leadtime_abandonment_time_maximum_minutes 30\n",
+		"leadtime_abandonment_time_p90_minutes 27\n",
+	} {
+		if !strings.Contains(got[0].body, want) {
+			t.Errorf("body does not contain %q:\n%s", want, got[0].body)
+		}
+	}
+	for _, wrong := range []string{"_pr_minutes", "_rate_minutes"} {
+		if strings.Contains(got[0].body, wrong) {
+			t.Errorf("body contains %q:\n%s", wrong, got[0].body)
+		}
+	}
+}
+
+func Test_pushOTLP(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1677672000, 0)
+	groups := []*pushGroup{
+		{repository: "o/a", columns: []summaryColumn{{name: "total_pr", value: 1, integer: true, unit: unitPullRequests}}},
+		{repository: "all", columns: []summaryColumn{
+			{name: "total_pr", value: 3, integer: true, unit: unitPullRequests},
+			{name: "lead_time_average", value: 60, unit: unitMinutes},
+			{name: "abandonment_rate", value: 25, unit: unitPercent},
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantPath string
+	}{
+		{name: "Use default path if endpoint has no path", path: "", wantPath: otlpMetricsPath},
+		{name: "Use default path if endpoint is root", path: "/", wantPath: otlpMetricsPath},
+		{name: "Use path of endpoint", path: "/custom/metrics", wantPath: "/custom/metrics"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, requests := newPushTestServer(t)
+			headers := http.Header{"Authorization": []string{"Bearer XXX"}}
+			if err := pushOTLP(context.Background(), server.Client(), server.URL+tt.path, headers, "nightly", groups, now); err != nil {
+				t.Fatal(err)
+			}
+
+			got := requests()
+			if len(got) != 1 {
+				t.Fatalf("mismatch want=%v, got=%v", 1, len(got))
+			}
+			if got[0].method != http.MethodPost || got[0].path != tt.wantPath {
+				t.Errorf("mismatch want=%v, got=%v", http.MethodPost+" "+tt.wantPath, got[0].method+" "+got[0].path)
+			}
+			if got[0].contentType != "application/json" || got[0].auth != "Bearer XXX" {
+				t.Errorf("headers are not sent: content-type=%s, authorization=%s", got[0].contentType, got[0].auth)
+			}
+
+			var body otlpRequest
+			if err := json.Unmarshal([]byte(got[0].body), &body); err != nil {
+				t.Fatal(err)
+			}
+			point := func(repository string, value float64) otlpDataPoint {
+				return otlpDataPoint{
+					Attributes:   []otlpAttribute{{Key: "repository", Value: otlpAnyValue{StringValue: repository}}},
+					TimeUnixNano: "1677672000000000000",
+					AsDouble:     value,
+				}
+			}
+			want := otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+				Resource: otlpResource{Attributes: []otlpAttribute{
+					{Key: "service.name", Value: otlpAnyValue{StringValue: "nightly"}},
+				}},
+				ScopeMetrics: []otlpScopeMetrics{{
+					Scope: otlpScope{Name: "github.com/nao1215/leadtime", Version: Version},
+					Metrics: []otlpMetric{
+						{Name: "leadtime.total_pr", Unit: "{pull_request}", Gauge: otlpGauge{DataPoints: []otlpDataPoint{point("o/a", 1), point("all", 3)}}},
+						{Name: "leadtime.lead_time_average", Unit: "min", Gauge: otlpGauge{DataPoints: []otlpDataPoint{point("all", 60)}}},
+						{Name: "leadtime.abandonment_rate", Unit: "%", Gauge: otlpGauge{DataPoints: []otlpDataPoint{point("all", 25)}}},
+					},
+				}},
+			}}}
+			if diff := cmp.Diff(want, body); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDetailLeadTimeStat_pushGroups(t *testing.T) {
+	t.Parallel()
+
+	dlts := &DetailLeadTimeStat{
+		LeadTimeStatistics: &LeadTimeStat{
+			TotalPR: 3,
+			Repositories: []*GroupStat{
+				{Group: "o/a", TotalPR: 1, LeadTimeAverage: 10, LeadTimeMedian: 10, LeadTimeP90: 10},
+				{Group: "o/b", TotalPR: 2, LeadTimeAverage: 20, LeadTimeMedian: 20, LeadTimeP90: 30},
+			},
+		},
+	}
+
+	t.Run("Push each repository and all repositories", func(t *testing.T) {
+		t.Parallel()
+
+		repos := []*usecase.Repository{{Owner: "o", Name: "a"}, {Owner: "o", Name: "b"}}
+		got := make([]string, 0)
+		for _, v := range dlts.pushGroups("o", repos) {
+			got = append(got, v.repository)
+		}
+		if diff := cmp.Diff([]string{"o/a", "o/b", aggregateRepositoryLabel}, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Push one repository with its name", func(t *testing.T) {
+		t.Parallel()
+
+		got := dlts.pushGroups("o", []*usecase.Repository{{Owner: "o", Name: "a"}})
+		if len(got) != 1 || got[0].repository != "o/a" {
+			t.Errorf("mismatch want=%v, got=%v", "o/a", got)
+		}
+	})
+}
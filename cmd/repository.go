@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+)
+
+// parseRepository parse repository flag value. The value is "owner/name" or
+// "name". If owner is omitted, defaultOwner is used. owner may include "/"
+// because GitLab namespace has subgroups (e.g. group/subgroup/name).
+// Empty name or owner part (e.g. "owner/", "/name", "group//name") is invalid.
+func parseRepository(value, defaultOwner string) (*usecase.Repository, error) {
+	parts := strings.Split(value, "/")
+	for _, v := range parts {
+		if v == "" {
+			return nil, ErrInvalidRepository
+		}
+	}
+	if len(parts) == 1 {
+		return &usecase.Repository{Owner: defaultOwner, Name: value}, nil
+	}
+	return &usecase.Repository{
+		Owner: strings.Join(parts[:len(parts)-1], "/"),
+		Name:  parts[len(parts)-1],
+	}, nil
+}
+
+// validRepositoryPattern check whether pattern is valid glob pattern or not.
+func validRepositoryPattern(pattern string) bool {
+	_, err := path.Match(pattern, "")
+	return err == nil
+}
+
+// matchRepository check whether repository matches glob pattern or not.
+// If pattern includes "/", it is compared with "owner/name". Otherwise, it is
+// compared with repository name.
+func matchRepository(pattern string, repo *usecase.Repository) bool {
+	target := repo.Name
+	if strings.Contains(pattern, "/") {
+		target = repo.FullName()
+	}
+	matched, err := path.Match(pattern, target)
+	return err == nil && matched
+}
+
+// filterRepositories return repositories that match one of include patterns and
+// do not match any exclude patterns. If include is empty, all repositories are included.
+func filterRepositories(repos []*usecase.Repository, include, exclude []string) []*usecase.Repository {
+	filtered := make([]*usecase.Repository, 0, len(repos))
+	for _, v := range repos {
+		if len(include) != 0 && !matchAnyRepository(include, v) {
+			continue
+		}
+		if matchAnyRepository(exclude, v) {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+	return filtered
+}
+
+func matchAnyRepository(patterns []string, repo *usecase.Repository) bool {
+	for _, p := range patterns {
+		if matchRepository(p, repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// repositories return target repositories specified by --repo and --org.
+// If neither is specified, return nil so that --owner and --repo are validated by usecase.
+// In --git-dir mode, return the local repository named after the directory.
+// In --from-file mode, if neither is specified, return all repositories in the file.
+func (o *option) repositories(ctx context.Context, lt usecase.LeadTimeUsecase) ([]*usecase.Repository, error) {
+	if o.gitDir != "" {
+		abs, err := filepath.Abs(o.gitDir)
+		if err != nil {
+			return nil, err
+		}
+		return []*usecase.Repository{{Owner: "local", Name: filepath.Base(abs)}}, nil
+	}
+	if len(o.gitHubRepos) == 0 && o.org == "" && o.fromFile == "" {
+		return nil, nil
+	}
+
+	repos := make([]*usecase.Repository, 0, len(o.gitHubRepos))
+	for _, v := range o.gitHubRepos {
+		repo, err := parseRepository(v, o.gitHubOwner)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo)
+	}
+
+	if o.org != "" || (o.fromFile != "" && len(o.gitHubRepos) == 0) {
+		output, err := lt.ListRepositories(ctx, &usecase.LeadTimeUsecaseListRepositoriesInput{
+			Organization: o.org,
+		})
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, output.Repositories...)
+	}
+
+	repos = filterRepositories(uniqueRepositories(repos), o.includeRepos, o.excludeRepos)
+	if len(repos) == 0 {
+		return nil, ErrNoRepository
+	}
+	return repos, nil
+}
+
+// uniqueRepositories remove duplicated repositories with keeping order.
+func uniqueRepositories(repos []*usecase.Repository) []*usecase.Repository {
+	seen := make(map[string]bool, len(repos))
+	unique := make([]*usecase.Repository, 0, len(repos))
+	for _, v := range repos {
+		if seen[v.FullName()] {
+			continue
+		}
+		seen[v.FullName()] = true
+		unique = append(unique, v)
+	}
+	return unique
+}
+
+// repositoryGroups return PR group of each repository in the order of repos.
+func repositoryGroups(repos []*usecase.Repository, prs []*usecase.PullRequest) []*prGroup {
+	groups := make([]*prGroup, 0, len(repos))
+	index := make(map[string]*prGroup, len(repos))
+	for _, v := range repos {
+		g := &prGroup{name: v.FullName()}
+		groups = append(groups, g)
+		index[g.name] = g
+	}
+	for _, v := range prs {
+		if g, ok := index[v.Repository]; ok {
+			g.prs = append(g.prs, v)
+		}
+	}
+	return groups
+}
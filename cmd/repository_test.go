@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/usecase"
+)
+
+// repositoryNames return full names of repositories for comparison.
+func repositoryNames(repos []*usecase.Repository) []string {
+	names := make([]string, 0, len(repos))
+	for _, v := range repos {
+		names = append(names, v.FullName())
+	}
+	return names
+}
+
+func Test_parseRepository(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    *usecase.Repository
+		wantErr error
+	}{
+		{
+			name:  "Name with default owner",
+			value: "sqly",
+			want:  &usecase.Repository{Owner: "nao1215", Name: "sqly"},
+		},
+		{
+			name:  "Owner and name",
+			value: "alice/tool",
+			want:  &usecase.Repository{Owner: "alice", Name: "tool"},
+		},
+		{
+			name:  "Owner with subgroups",
+			value: "group/sub/tool",
+			want:  &usecase.Repository{Owner: "group/sub", Name: "tool"},
+		},
+		{name: "Empty value", value: "", wantErr: ErrInvalidRepository},
+		{name: "Empty name", value: "alice/", wantErr: ErrInvalidRepository},
+		{name: "Empty owner", value: "/tool", wantErr: ErrInvalidRepository},
+		{name: "Double slash", value: "alice//tool", wantErr: ErrInvalidRepository},
+		{name: "Double slash in subgroups", value: "group//sub/tool", wantErr: ErrInvalidRepository},
+		{name: "Only slash", value: "/", wantErr: ErrInvalidRepository},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseRepository(tt.value, "nao1215")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("mismatch want=%v, got=%v", tt.wantErr, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_matchRepository(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pattern string
+		repo    *usecase.Repository
+		want    bool
+	}{
+		{name: "Pattern without slash matches name", pattern: "go-*", repo: &usecase.Repository{Owner: "alice", Name: "go-cli"}, want: true},
+		{name: "Pattern without slash does not match owner", pattern: "alice*", repo: &usecase.Repository{Owner: "alice", Name: "tool"}, want: false},
+		{name: "Pattern with slash matches full name", pattern: "alice/*", repo: &usecase.Repository{Owner: "alice", Name: "tool"}, want: true},
+		{name: "Pattern with slash does not match other owner", pattern: "alice/*", repo: &usecase.Repository{Owner: "bob", Name: "tool"}, want: false},
+		{name: "Star does not match slash of subgroups", pattern: "group/*", repo: &usecase.Repository{Owner: "group/sub", Name: "tool"}, want: false},
+		{name: "Pattern matches subgroups", pattern: "group/*/tool", repo: &usecase.Repository{Owner: "group/sub", Name: "tool"}, want: true},
+		{name: "Invalid pattern does not match", pattern: "[", repo: &usecase.Repository{Owner: "alice", Name: "["}, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := matchRepository(tt.pattern, tt.repo); got != tt.want {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_filterRepositories(t *testing.T) {
+	t.Parallel()
+
+	repos := []*usecase.Repository{
+		{Owner: "alice", Name: "go-cli"},
+		{Owner: "alice", Name: "go-lib"},
+		{Owner: "alice", Name: "docs"},
+		{Owner: "group/sub", Name: "go-api"},
+	}
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{
+			name: "Include all repositories without patterns",
+			want: []string{"alice/go-cli", "alice/go-lib", "alice/docs", "group/sub/go-api"},
+		},
+		{
+			name:    "Include repositories that match one of patterns",
+			include: []string{"go-c*", "docs"},
+			want:    []string{"alice/go-cli", "alice/docs"},
+		},
+		{
+			name:    "Exclude repositories that match patterns",
+			exclude: []string{"go-*"},
+			want:    []string{"alice/docs"},
+		},
+		{
+			name:    "Exclude has priority over include",
+			include: []string{"go-*"},
+			exclude: []string{"alice/go-lib"},
+			want:    []string{"alice/go-cli", "group/sub/go-api"},
+		},
+		{
+			name:    "Include repositories in subgroup",
+			include: []string{"group/sub/*"},
+			want:    []string{"group/sub/go-api"},
+		},
+		{
+			name:    "No repository matches",
+			include: []string{"rust-*"},
+			want:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := repositoryNames(filterRepositories(repos, tt.include, tt.exclude))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_uniqueRepositories(t *testing.T) {
+	t.Parallel()
+
+	repos := []*usecase.Repository{
+		{Owner: "alice", Name: "tool"},
+		{Owner: "bob", Name: "tool"},
+		{Owner: "alice", Name: "tool"},
+		{Owner: "group/sub", Name: "tool"},
+	}
+	want := []string{"alice/tool", "bob/tool", "group/sub/tool"}
+	if diff := cmp.Diff(want, repositoryNames(uniqueRepositories(repos))); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
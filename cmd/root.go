@@ -27,6 +27,9 @@ func Execute() int {
 	rootCmd.SilenceErrors = true
 
 	rootCmd.AddCommand(newStatCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newCacheCmd())
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newCompletionCmd())
 
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/leadtime/di"
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd() *cobra.Command {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve pull request lead time metrics for Prometheus",
+		Long: `Serve pull request lead time metrics for Prometheus.
+With --metrics, leadtime refreshes the statistics on an interval and exposes
+/metrics in Prometheus/OpenMetrics text format. Lead time, time to merge and
+stage times of closed PRs are gauge histograms labeled by repository and author, and the age of
+open PRs is a gauge. The same source and filter flags as 'leadtime stat' are
+available, and a duration of --since (e.g. 30d) is relative to each refresh.
+--since and --until filter only closed PRs, so every open PR is in the gauges.`,
+		Example: `  LT_GITHUB_ACCESS_TOKEN=XXX leadtime serve --metrics --org=myorg --since=90d --cache
+  leadtime serve --metrics --git-dir=. --listen=:9101 --interval=5m`,
+		RunE: serve,
+	}
+
+	addSourceFlags(serveCmd)
+	addFilterFlags(serveCmd)
+	serveCmd.Flags().Bool("metrics", false, "Expose lead time metrics at /metrics in Prometheus/OpenMetrics format")
+	serveCmd.Flags().String("listen", ":8080", "Address that the metrics server listens on")
+	serveCmd.Flags().Duration("interval", 15*time.Minute, "Interval to refresh metrics (e.g. 5m, 1h)")
+	serveCmd.Flags().String("from-file", "", "Compute metrics from the file exported by 'leadtime export --raw' instead of API")
+
+	return serveCmd
+}
+
+func serve(cmd *cobra.Command, args []string) error {
+	enabled, err := cmd.Flags().GetBool("metrics")
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return ErrServeRequiresMetrics
+	}
+
+	listen, err := cmd.Flags().GetString("listen")
+	if err != nil {
+		return err
+	}
+
+	interval, err := cmd.Flags().GetDuration("interval")
+	if err != nil {
+		return err
+	}
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	opt, err := newSourceOption(cmd)
+	if err != nil {
+		return err
+	}
+
+	opt.fromFile, err = cmd.Flags().GetString("from-file")
+	if err != nil {
+		return err
+	}
+
+	if err := opt.validSource(); err != nil {
+		return err
+	}
+	if err := opt.validFromFile(); err != nil {
+		return err
+	}
+
+	leadTime, err := newLeadTime(opt)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &metricsServer{cmd: cmd, opt: opt, leadTime: leadTime}
+	// The first refresh fails fast, so that a wrong option is found at startup.
+	if err := server.refresh(ctx); err != nil {
+		return err
+	}
+	go server.refreshEvery(ctx, interval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", server)
+	httpServer := &http.Server{
+		Addr:              listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx) //nolint
+	}()
+
+	log.Info("serve metrics", "addr", listen, "path", "/metrics", "interval", interval)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// metricsServer refresh lead time metrics and serve them over HTTP.
+type metricsServer struct {
+	cmd      *cobra.Command
+	opt      *option
+	leadTime *di.LeadTime
+
+	mu sync.RWMutex
+	// metrics is the last metrics that were calculated successfully.
+	metrics *metrics
+	// succeeded is whether the last refresh succeeded or not.
+	succeeded bool
+}
+
+// refreshEvery refresh metrics on the interval until ctx is done.
+// If refresh fails, the previous metrics are served.
+func (s *metricsServer) refreshEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.refresh(ctx); err != nil {
+				log.Error("failed to refresh metrics", "err", err)
+			}
+		}
+	}
+}
+
+// refresh calculate metrics of PRs selected by the source and filter flags.
+// Repositories of --org and a duration of --since and --until are evaluated at each refresh.
+func (s *metricsServer) refresh(ctx context.Context) error {
+	m, err := s.calculate(ctx, time.Now())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.succeeded = err == nil
+	if err != nil {
+		return err
+	}
+	s.metrics = m
+	return nil
+}
+
+func (s *metricsServer) calculate(ctx context.Context, now time.Time) (*metrics, error) {
+	if err := s.opt.setFilter(s.cmd, now); err != nil {
+		return nil, err
+	}
+
+	repos, err := s.opt.repositories(ctx, s.leadTime.LeadTimeUsecase)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &usecase.LeadTimeUsecaseStatInput{
+		Owner:        s.opt.gitHubOwner,
+		Repositories: repos,
+		Concurrency:  s.opt.concurrency,
+		Since:        s.opt.since,
+		Until:        s.opt.until,
+		DateField:    s.opt.dateField,
+		IncludeOpen:  true,
+	}
+	if err := input.Valid(); err != nil {
+		return nil, err
+	}
+
+	output, err := s.leadTime.LeadTimeUsecase.Stat(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	dlts := newDetailLeadTimeStat(output.LeadTime)
+	dlts.removeExcludedPRs(s.opt)
+	open := dlts.openPRs()
+	dlts.removeOpenPR()
+	if !s.opt.includeUnmerged {
+		dlts.removeUnmergedPR()
+	}
+
+	return newMetrics(dlts.PullRequests, open, now), nil
+}
+
+// ServeHTTP write the last metrics. If the client accepts OpenMetrics, the response
+// is OpenMetrics text format. Otherwise, it is Prometheus text format.
+func (s *metricsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	m, succeeded := s.metrics, s.succeeded
+	s.mu.RUnlock()
+
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", contentTypeOpenMetrics)
+	} else {
+		w.Header().Set("Content-Type", contentTypePrometheus)
+	}
+	if err := m.write(w, openMetrics, succeeded); err != nil {
+		log.Error("failed to write metrics", "err", err)
+	}
+}
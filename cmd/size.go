@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+)
+
+// unknownSize is group name of PRs whose size the source does not provide.
+const unknownSize = "unknown"
+
+// sizeClasses is names of PR size classes in ascending order of size.
+var sizeClasses = []string{"XS", "S", "M", "L", "XL"}
+
+// defaultSizeThresholds is default upper bounds of changed lines of XS, S, M and L.
+var defaultSizeThresholds = []int{10, 100, 500, 1000}
+
+// sizeThresholds is upper bounds (inclusive) of changed lines (additions + deletions)
+// of XS, S, M and L. PRs larger than the last threshold are XL.
+type sizeThresholds []int
+
+// valid check whether there is a threshold for each class except XL and
+// thresholds are positive and in ascending order.
+func (s sizeThresholds) valid() bool {
+	if len(s) != len(sizeClasses)-1 {
+		return false
+	}
+	for i, v := range s {
+		if v <= 0 || (i > 0 && v <= s[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// names return group name of each class with its range of changed lines (e.g. "S(11-100)").
+func (s sizeThresholds) names() []string {
+	names := make([]string, 0, len(sizeClasses))
+	lower := 0
+	for i, v := range s {
+		names = append(names, fmt.Sprintf("%s(%d-%d)", sizeClasses[i], lower, v))
+		lower = v + 1
+	}
+	return append(names, fmt.Sprintf("%s(%d-)", sizeClasses[len(s)], lower))
+}
+
+// class return index of size class of the PR. If the size is unknown, return -1.
+func (s sizeThresholds) class(pr *usecase.PullRequest) int {
+	if pr.Additions == nil || pr.Deletions == nil {
+		return -1
+	}
+	lines := *pr.Additions + *pr.Deletions
+	for i, v := range s {
+		if lines <= v {
+			return i
+		}
+	}
+	return len(s)
+}
+
+// groups return PR group of each size class from XS to XL. Classes without PR are
+// included so that classes are always the same. The group of PRs whose size is unknown
+// is the last, and it is included only if there is such a PR.
+func (s sizeThresholds) groups(prs []*usecase.PullRequest) []*prGroup {
+	groups := make([]*prGroup, 0, len(sizeClasses)+1)
+	for _, v := range s.names() {
+		groups = append(groups, &prGroup{name: v})
+	}
+	unknown := &prGroup{name: unknownSize}
+
+	for _, v := range prs {
+		if i := s.class(v); i >= 0 {
+			groups[i].prs = append(groups[i].prs, v)
+			continue
+		}
+		unknown.prs = append(unknown.prs, v)
+	}
+
+	if len(unknown.prs) != 0 {
+		groups = append(groups, unknown)
+	}
+	return groups
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/shogo82148/pointer"
+)
+
+func Test_sizeThresholds_valid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		thresholds sizeThresholds
+		want       bool
+	}{
+		{name: "Default thresholds", thresholds: defaultSizeThresholds, want: true},
+		{name: "Too few thresholds", thresholds: sizeThresholds{10, 100, 500}, want: false},
+		{name: "Too many thresholds", thresholds: sizeThresholds{10, 100, 500, 1000, 2000}, want: false},
+		{name: "Not positive", thresholds: sizeThresholds{0, 100, 500, 1000}, want: false},
+		{name: "Not ascending order", thresholds: sizeThresholds{10, 500, 100, 1000}, want: false},
+		{name: "Same thresholds", thresholds: sizeThresholds{10, 10, 500, 1000}, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.thresholds.valid(); got != tt.want {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_sizeThresholds_groups(t *testing.T) {
+	t.Parallel()
+
+	pr := func(number, additions, deletions int) *usecase.PullRequest {
+		return &usecase.PullRequest{Number: number, Additions: pointer.Int(additions), Deletions: pointer.Int(deletions)}
+	}
+	thresholds := sizeThresholds(defaultSizeThresholds)
+
+	t.Run("Classify PRs by changed lines with inclusive upper bounds", func(t *testing.T) {
+		t.Parallel()
+
+		prs := []*usecase.PullRequest{
+			pr(1, 10, 0),
+			pr(2, 6, 5),
+			pr(3, 1000, 0),
+			pr(4, 1000, 1),
+			{Number: 5, Additions: pointer.Int(1)},
+			pr(6, 0, 0),
+		}
+
+		want := []groupNumbers{
+			{Name: "XS(0-10)", Numbers: []int{1, 6}},
+			{Name: "S(11-100)", Numbers: []int{2}},
+			{Name: "M(101-500)", Numbers: []int{}},
+			{Name: "L(501-1000)", Numbers: []int{3}},
+			{Name: "XL(1001-)", Numbers: []int{4}},
+			{Name: unknownSize, Numbers: []int{5}},
+		}
+		if diff := cmp.Diff(want, toGroupNumbers(thresholds.groups(prs))); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("No unknown group if every PR has size", func(t *testing.T) {
+		t.Parallel()
+
+		groups := thresholds.groups([]*usecase.PullRequest{pr(1, 1, 1)})
+		if len(groups) != len(sizeClasses) {
+			t.Errorf("mismatch want=%v, got=%v", len(sizeClasses), len(groups))
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// addSourceFlags add flags that specify where PRs are fetched from.
+// They are shared by the subcommands that fetch PRs.
+func addSourceFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("owner", "o", "", "Specify owner name (GitHub user/organization or GitLab group)")
+	cmd.Flags().StringSliceP("repo", "r", []string{}, "Specify repository name or owner/name. Repeatable (e.g. '-r nao1215/sqly -r nao1215/gup')")
+	cmd.Flags().String("org", "", "Use all repositories of the specified GitHub organization or GitLab group")
+	cmd.Flags().StringSlice("include-repo", []string{}, "Only repositories whose name matches the glob pattern (e.g. 'api-*', 'myorg/*')")
+	cmd.Flags().StringSlice("exclude-repo", []string{}, "Exclude repositories whose name matches the glob pattern (e.g. '*-archive')")
+	cmd.Flags().IntP("concurrency", "c", 4, "Number of workers that fetch PR commits and reviews at the same time")
+	cmd.Flags().String("git-dir", "", "Derive PRs from merge commits of the local git repository instead of API (no token needed)")
+	cmd.Flags().String("provider", string(providerGitHub), "Service that hosts repositories (github, gitlab, gitea, forgejo, bitbucket)")
+	cmd.Flags().String("api-url", "", "URL of GitHub Enterprise Server API, GitLab, Gitea or Bitbucket. Overrides LT_GITHUB_API_URL, LT_GITLAB_URL, LT_GITEA_URL or LT_BITBUCKET_URL")
+	cmd.Flags().Bool("graphql", false, "Fetch PRs with their first commit and reviews in bulk by GitHub GraphQL API")
+	cmd.Flags().Bool("cache", false, "Cache fetched PRs on disk and fetch only PRs updated since the last run")
+}
+
+// newSourceOption return option that has only the values of flags added by addSourceFlags.
+func newSourceOption(cmd *cobra.Command) (*option, error) {
+	apiURL, err := cmd.Flags().GetString("api-url")
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := cmd.Flags().GetBool("cache")
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency, err := cmd.Flags().GetInt("concurrency")
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := cmd.Flags().GetString("owner")
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := cmd.Flags().GetStringSlice("repo")
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := cmd.Flags().GetString("org")
+	if err != nil {
+		return nil, err
+	}
+
+	includeRepos, err := cmd.Flags().GetStringSlice("include-repo")
+	if err != nil {
+		return nil, err
+	}
+
+	excludeRepos, err := cmd.Flags().GetStringSlice("exclude-repo")
+	if err != nil {
+		return nil, err
+	}
+
+	gitDir, err := cmd.Flags().GetString("git-dir")
+	if err != nil {
+		return nil, err
+	}
+
+	graphQL, err := cmd.Flags().GetBool("graphql")
+	if err != nil {
+		return nil, err
+	}
+
+	providerName, err := cmd.Flags().GetString("provider")
+	if err != nil {
+		return nil, err
+	}
+
+	return &option{
+		apiURL:       apiURL,
+		cache:        cache,
+		concurrency:  concurrency,
+		excludeRepos: excludeRepos,
+		includeRepos: includeRepos,
+		gitDir:       gitDir,
+		graphQL:      graphQL,
+		gitHubOwner:  owner,
+		gitHubRepos:  repos,
+		org:          org,
+		provider:     provider(providerName),
+	}, nil
+}
+
+// validSource validate the values of flags added by addSourceFlags.
+func (o *option) validSource() error {
+	if !o.provider.valid() {
+		return ErrInvalidProvider
+	}
+	if o.graphQL && o.provider != providerGitHub {
+		return ErrGraphQLRequiresGitHub
+	}
+	if o.gitDir != "" && (o.provider != providerGitHub || o.graphQL || o.org != "" || len(o.gitHubRepos) != 0) {
+		return ErrGitDirConflict
+	}
+	for _, v := range append(append([]string{}, o.includeRepos...), o.excludeRepos...) {
+		if !validRepositoryPattern(v) {
+			return ErrInvalidRepositoryPattern
+		}
+	}
+	for _, v := range o.gitHubRepos {
+		if _, err := parseRepository(v, o.gitHubOwner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
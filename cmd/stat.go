@@ -4,18 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"image/color"
 	"io"
+	"math"
+	"net/http"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/nao1215/leadtime/di"
 	"github.com/nao1215/leadtime/domain/usecase"
 	"github.com/shogo82148/pointer"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
-	"gonum.org/v1/plot"
-	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
 )
 
@@ -30,19 +32,47 @@ leadtime calculates statistics for PRs already in Closed/Merged status.
 ---------------------------------------
 ^               ^                     ^
 first commit    create PR          merge PR
+
+Lead time is also broken into stages using PR reviews.
+ coding time: first commit to create PR
+ pickup time: create PR to first review
+ review time: first review to approval
+ merge time : approval to merge PR
 `,
-		Example: "  LT_GITHUB_ACCESS_TOKEN=XXX leadtime stat --owner=nao1215 --repo=sqly",
-		RunE:    stat,
+		Example: `  LT_GITHUB_ACCESS_TOKEN=XXX leadtime stat --owner=nao1215 --repo=sqly
+  LT_GITHUB_ACCESS_TOKEN=XXX leadtime stat --repo=nao1215/sqly --repo=nao1215/gup
+  LT_GITHUB_ACCESS_TOKEN=XXX leadtime stat --org=myorg --exclude-repo='*-archive'
+  LT_GITLAB_TOKEN=XXX leadtime stat --provider=gitlab --repo=mygroup/subgroup/project
+  LT_GITEA_TOKEN=XXX LT_GITEA_URL=https://gitea.example.com leadtime stat --provider=gitea --repo=myorg/repo
+  leadtime stat --git-dir=.`,
+		RunE: stat,
 	}
 
-	statCmd.Flags().StringP("owner", "o", "", "Specify GitHub owner name")
-	statCmd.Flags().StringP("repo", "r", "", "Specify GitHub repository name")
+	addSourceFlags(statCmd)
+	addFilterFlags(statCmd)
+
 	statCmd.Flags().BoolP("markdown", "m", false, "Output markdown")
-	statCmd.Flags().BoolP("exclude-bot", "B", false, "Exclude Pull Requests created by bots")
-	statCmd.Flags().IntSliceP("exclude-pr", "P", []int{}, "Exclude specified Pull Requests (e.g. '-P 1,3,19')")
-	statCmd.Flags().StringSliceP("exclude-user", "U", []string{}, "Exclude Pull Requests created by specified user (e.g. '-U nao,alice')")
 	statCmd.Flags().BoolP("all", "a", false, "Print all data used for statistics")
 	statCmd.Flags().BoolP("json", "j", false, "Output json")
+	statCmd.Flags().Bool("html", false, "Output self-contained html report with charts and sortable PR table")
+	statCmd.Flags().Bool("csv", false, "Output csv of every PR")
+	statCmd.Flags().Bool("tsv", false, "Output tsv of every PR")
+	statCmd.Flags().Bool("summary-csv", false, "Output csv of lead time statistics only")
+	statCmd.Flags().Bool("group-csv", false, "Output csv of grouped statistics only (requires --group-by)")
+	statCmd.Flags().StringP("group-by", "g", "", "Print lead time grouped by merge date (week, month), author, team (with --team-file), label or size")
+	statCmd.Flags().IntSlice("size-thresholds", defaultSizeThresholds, "Upper bounds of changed lines of XS, S, M and L PRs for --group-by=size")
+	statCmd.Flags().String("team-file", "", "JSON file that maps GitHub logins to team names for --group-by=team (e.g. {\"nao1215\": \"backend\"})")
+	statCmd.Flags().Float64Slice("percentiles", []float64{}, "Additional lead time percentiles to print (e.g. '--percentiles=50,85,95')")
+	statCmd.Flags().String("from-file", "", "Compute statistics from the file exported by 'leadtime export --raw' instead of API")
+	statCmd.Flags().String("chart-type", string(chartTypeLine), "Chart drawn with --markdown or --chart-output (line, histogram, box-author, scatter-size, cumulative)")
+	statCmd.Flags().String("chart-output", "", "Write chart to the path. Default is leadtime.<format> with --markdown")
+	statCmd.Flags().String("chart-format", "png", "Chart format (png, svg, pdf). Default is the extension of --chart-output or png")
+	statCmd.Flags().String("chart-width", "4in", "Chart width (e.g. 6in, 15cm, 400pt)")
+	statCmd.Flags().String("chart-height", "4in", "Chart height (e.g. 4in, 10cm, 300pt)")
+	statCmd.Flags().String("push-gateway", "", "Push lead time statistics to the Prometheus Pushgateway URL (e.g. http://localhost:9091)")
+	statCmd.Flags().String("otlp-endpoint", "", "Push lead time statistics to the OTLP/HTTP metrics endpoint (e.g. http://localhost:4318)")
+	statCmd.Flags().StringSlice("otlp-header", []string{}, "Header of OTLP/HTTP request in key=value format (e.g. 'Authorization=Bearer XXX')")
+	statCmd.Flags().String("push-job", Name, "Job label of pushed statistics")
 
 	return statCmd
 }
@@ -50,61 +80,223 @@ first commit    create PR          merge PR
 type option struct {
 	// all is flag whether output statistical data instead of statistical information or not
 	all bool
+	// apiURL is URL of GitHub Enterprise Server API, GitLab, Gitea or Bitbucket. Empty means the environment variable setting.
+	apiURL string
+	// cache is whether fetched data is cached on disk or not
+	cache bool
+	// chartType is the kind of chart
+	chartType chartType
+	// chartOutput is path of chart file. Empty means leadtime.<format> with markdown, or no chart.
+	chartOutput string
+	// chartFormat is format of chart file (png, svg, pdf)
+	chartFormat string
+	// chartWidth is width of chart
+	chartWidth vg.Length
+	// chartHeight is height of chart
+	chartHeight vg.Length
+	// concurrency is number of workers that fetch PR details
+	concurrency int
+	// dateField is PR date compared with since and until
+	dateField usecase.DateField
 	// excludeBot is whether PRs created by bots exclude or not
 	excludeBot bool
 	// excludePRs is PR number list for exclusion
 	excludePRs []int
 	// excludeUsers is user list for exclusion
 	excludeUsers []string
+	// includeLabels is label list. If it is not empty, only PRs that have one of them are used.
+	includeLabels []string
+	// excludeLabels is label list for exclusion
+	excludeLabels []string
+	// includeUnmerged is whether PRs closed without merge are used for lead time statistics or not
+	includeUnmerged bool
+	// excludeRepos is glob patterns of repositories for exclusion
+	excludeRepos []string
+	// includeRepos is glob patterns of target repositories
+	includeRepos []string
+	// fromFile is path of the file exported by "leadtime export --raw". If it is set, PRs are read from the file.
+	fromFile string
+	// gitDir is path of local git repository. If it is set, PRs are derived from git history.
+	gitDir string
+	// graphQL is whether GitHub GraphQL API is used or not
+	graphQL bool
+	// groupBy is the way to group PRs for breakdown statistics
+	groupBy groupBy
+	// teamFile is path of the file that maps logins to team names
+	teamFile string
+	// teams is team of each login. It is set only with --group-by=team.
+	teams teamMap
+	// sizeThresholds is upper bounds of changed lines of size classes for --group-by=size
+	sizeThresholds sizeThresholds
+	// sizeThresholdsChanged is whether --size-thresholds is specified or not
+	sizeThresholdsChanged bool
 	// gitHubOwner is owner name
 	gitHubOwner string
-	// gitHubRepo is github repository
-	gitHubRepo string
+	// gitHubRepos is github repositories (name or owner/name)
+	gitHubRepos []string
+	// org is github organization whose all repositories are used
+	org string
+	// csv is csv output mode flag
+	csv bool
+	// tsv is tsv output mode flag
+	tsv bool
+	// summaryCSV is csv output mode flag for statistics only
+	summaryCSV bool
+	// groupCSV is csv output mode flag for grouped statistics only
+	groupCSV bool
 	// json is json output mode flag
 	json bool
+	// html is html output mode flag
+	html bool
+	// otlpEndpoint is URL of OTLP/HTTP metrics endpoint. Empty means no push.
+	otlpEndpoint string
+	// otlpHeaders is headers of OTLP/HTTP request
+	otlpHeaders http.Header
+	// percentiles is additional lead time percentiles
+	percentiles []float64
+	// provider is the service that hosts repositories
+	provider provider
+	// pushGateway is URL of Prometheus Pushgateway. Empty means no push.
+	pushGateway string
+	// pushJob is job label of pushed statistics
+	pushJob string
 	// markdown is markdown output mode flag
 	markdown bool
+	// since is start of date range. Zero means no limit.
+	since time.Time
+	// until is end of date range. Zero means no limit.
+	until time.Time
 }
 
 func (o *option) valid() error {
-	if o.json && o.markdown {
+	outputs := 0
+	for _, v := range []bool{o.json, o.html, o.markdown, o.csv, o.tsv, o.summaryCSV, o.groupCSV} {
+		if v {
+			outputs++
+		}
+	}
+	if outputs > 1 {
 		return ErrMultipleOutputFlag
 	}
+	if err := o.validSource(); err != nil {
+		return err
+	}
+	if err := o.validFromFile(); err != nil {
+		return err
+	}
+	if !o.groupBy.valid() {
+		return ErrInvalidGroupBy
+	}
+	if (o.groupBy == groupByTeam) != (o.teamFile != "") {
+		return ErrTeamFileRequiresGroupByTeam
+	}
+	if o.sizeThresholdsChanged && o.groupBy != groupBySize {
+		return ErrSizeThresholdsRequiresGroupBySize
+	}
+	if o.groupCSV && o.groupBy == groupByNone {
+		return ErrGroupCSVRequiresGroupBy
+	}
+	if !o.sizeThresholds.valid() {
+		return ErrInvalidSizeThresholds
+	}
+	for _, v := range o.percentiles {
+		if v < 0 || v > 100 || math.IsNaN(v) {
+			return ErrInvalidPercentile
+		}
+	}
+	if !o.chartType.valid() {
+		return ErrInvalidChartType
+	}
+	if !validChartFormat(o.chartFormat) {
+		return ErrInvalidChartFormat
+	}
+	if ext := strings.TrimPrefix(filepath.Ext(o.chartOutput), "."); ext != "" && !strings.EqualFold(ext, o.chartFormat) {
+		return ErrChartFormatMismatch
+	}
+	if o.chartWidth <= 0 || o.chartHeight <= 0 {
+		return ErrInvalidChartSize
+	}
+	for _, v := range []string{o.pushGateway, o.otlpEndpoint} {
+		if v != "" && !validPushURL(v) {
+			return ErrInvalidPushURL
+		}
+	}
+	if o.pushJob == "" {
+		return ErrEmptyPushJob
+	}
+	return nil
+}
+
+// validFromFile check that --from-file is not used with flags for the other sources.
+func (o *option) validFromFile() error {
+	if o.fromFile != "" && (o.gitDir != "" || o.provider != providerGitHub || o.apiURL != "" || o.graphQL || o.cache) {
+		return ErrFromFileConflict
+	}
 	return nil
 }
 
 func newOption(cmd *cobra.Command) (*option, error) {
+	opt, err := newSourceOption(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	all, err := cmd.Flags().GetBool("all")
 	if err != nil {
 		return nil, err
 	}
 
-	bot, err := cmd.Flags().GetBool("exclude-bot")
+	fromFile, err := cmd.Flags().GetString("from-file")
 	if err != nil {
 		return nil, err
 	}
 
-	excludePRs, err := cmd.Flags().GetIntSlice("exclude-pr")
+	json, err := cmd.Flags().GetBool("json")
 	if err != nil {
 		return nil, err
 	}
 
-	excludeUsers, err := cmd.Flags().GetStringSlice("exclude-user")
+	html, err := cmd.Flags().GetBool("html")
 	if err != nil {
 		return nil, err
 	}
 
-	owner, err := cmd.Flags().GetString("owner")
+	csv, err := cmd.Flags().GetBool("csv")
 	if err != nil {
 		return nil, err
 	}
 
-	repo, err := cmd.Flags().GetString("repo")
+	tsv, err := cmd.Flags().GetBool("tsv")
 	if err != nil {
 		return nil, err
 	}
 
-	json, err := cmd.Flags().GetBool("json")
+	summaryCSV, err := cmd.Flags().GetBool("summary-csv")
+	if err != nil {
+		return nil, err
+	}
+
+	groupCSV, err := cmd.Flags().GetBool("group-csv")
+	if err != nil {
+		return nil, err
+	}
+
+	percentiles, err := cmd.Flags().GetFloat64Slice("percentiles")
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := cmd.Flags().GetString("group-by")
+	if err != nil {
+		return nil, err
+	}
+
+	teamFile, err := cmd.Flags().GetString("team-file")
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds, err := cmd.Flags().GetIntSlice("size-thresholds")
 	if err != nil {
 		return nil, err
 	}
@@ -114,24 +306,102 @@ func newOption(cmd *cobra.Command) (*option, error) {
 		return nil, err
 	}
 
-	return &option{
-		all:          all,
-		excludeBot:   bot,
-		excludePRs:   excludePRs,
-		excludeUsers: excludeUsers,
-		gitHubOwner:  owner,
-		gitHubRepo:   repo,
-		markdown:     markdown,
-		json:         json,
-	}, nil
+	chart, err := cmd.Flags().GetString("chart-type")
+	if err != nil {
+		return nil, err
+	}
+
+	chartOutput, err := cmd.Flags().GetString("chart-output")
+	if err != nil {
+		return nil, err
+	}
+
+	chartFormat, err := cmd.Flags().GetString("chart-format")
+	if err != nil {
+		return nil, err
+	}
+	if ext := strings.TrimPrefix(filepath.Ext(chartOutput), "."); !cmd.Flags().Changed("chart-format") && validChartFormat(ext) {
+		chartFormat = ext
+	}
+
+	chartWidth, err := chartLengthFlag(cmd, "chart-width")
+	if err != nil {
+		return nil, err
+	}
+
+	chartHeight, err := chartLengthFlag(cmd, "chart-height")
+	if err != nil {
+		return nil, err
+	}
+
+	pushGateway, err := cmd.Flags().GetString("push-gateway")
+	if err != nil {
+		return nil, err
+	}
+
+	otlpEndpoint, err := cmd.Flags().GetString("otlp-endpoint")
+	if err != nil {
+		return nil, err
+	}
+
+	otlpHeaders, err := cmd.Flags().GetStringSlice("otlp-header")
+	if err != nil {
+		return nil, err
+	}
+	headers, err := parseHeaders(otlpHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	pushJob, err := cmd.Flags().GetString("push-job")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := opt.setFilter(cmd, time.Now()); err != nil {
+		return nil, err
+	}
+
+	opt.all = all
+	opt.chartType = chartType(chart)
+	opt.fromFile = fromFile
+	opt.groupBy = groupBy(group)
+	opt.teamFile = teamFile
+	opt.sizeThresholds = thresholds
+	opt.sizeThresholdsChanged = cmd.Flags().Changed("size-thresholds")
+	opt.markdown = markdown
+	opt.json = json
+	opt.html = html
+	opt.csv = csv
+	opt.tsv = tsv
+	opt.summaryCSV = summaryCSV
+	opt.groupCSV = groupCSV
+	opt.percentiles = percentiles
+	opt.chartOutput = chartOutput
+	opt.chartFormat = chartFormat
+	opt.chartWidth = chartWidth
+	opt.chartHeight = chartHeight
+	opt.pushGateway = pushGateway
+	opt.otlpEndpoint = otlpEndpoint
+	opt.otlpHeaders = headers
+	opt.pushJob = pushJob
+	return opt, nil
 }
 
-func stat(cmd *cobra.Command, args []string) error { //nolint
-	leadTime, err := di.NewLeadTime()
+// chartLengthFlag return chart length specified by flag (e.g. 4in, 10cm).
+func chartLengthFlag(cmd *cobra.Command, name string) (vg.Length, error) {
+	value, err := cmd.Flags().GetString(name)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	length, err := vg.ParseLength(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s=%s", ErrInvalidChartSize, name, value)
 	}
+	return length, nil
+}
 
+func stat(cmd *cobra.Command, args []string) error { //nolint
 	opt, err := newOption(cmd)
 	if err != nil {
 		return err
@@ -141,77 +411,107 @@ func stat(cmd *cobra.Command, args []string) error { //nolint
 		return err
 	}
 
+	if opt.teamFile != "" {
+		if opt.teams, err = loadTeamMap(opt.teamFile); err != nil {
+			return err
+		}
+	}
+
+	leadTime, err := newLeadTime(opt)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	repos, err := opt.repositories(ctx, leadTime.LeadTimeUsecase)
+	if err != nil {
+		return err
+	}
+
 	input := &usecase.LeadTimeUsecaseStatInput{
-		Owner:      opt.gitHubOwner,
-		Repository: opt.gitHubRepo,
+		Owner:        opt.gitHubOwner,
+		Repositories: repos,
+		Concurrency:  opt.concurrency,
+		Since:        opt.since,
+		Until:        opt.until,
+		DateField:    opt.dateField,
+		FetchSize:    opt.groupBy == groupBySize || opt.chartType == chartTypeScatterSize,
 	}
 	if err := input.Valid(); err != nil {
 		return err
 	}
 
-	output, err := leadTime.LeadTimeUsecase.Stat(context.Background(), input)
+	output, err := leadTime.LeadTimeUsecase.Stat(ctx, input)
 	if err != nil {
 		return err
 	}
 
 	dlts := newDetailLeadTimeStat(output.LeadTime)
 	dlts.removePRs(opt)
-	dlts.stat()
+	dlts.stat(opt.percentiles)
+	dlts.group(opt.groupBy, opt.teams, opt.sizeThresholds)
+	dlts.groupByRepository(repos)
+
+	if err := dlts.print(opt); err != nil {
+		return err
+	}
 
-	return dlts.print(opt)
+	if opt.pushGateway != "" || opt.otlpEndpoint != "" {
+		return dlts.push(ctx, opt, repos, time.Now())
+	}
+	return nil
 }
 
 func (dlts *DetailLeadTimeStat) print(opt *option) error {
-	if opt.markdown {
-		if err := dlts.drawGraph(); err != nil {
+	if opt.markdown || opt.chartOutput != "" {
+		if err := dlts.drawGraph(opt); err != nil {
 			return err
 		}
-		dlts.markdown(opt.all)
-		return nil
+		if opt.groupBy.isTimeSeries() {
+			if err := dlts.drawTrendGraph(opt); err != nil {
+				return err
+			}
+		}
 	}
 
-	if opt.json {
-		if err := dlts.json(os.Stdout, opt.all); err != nil {
-			return err
-		}
+	if opt.markdown {
+		dlts.markdown(opt.all, opt.chartLink(), opt.trendChartLink())
 		return nil
 	}
 
-	dlts.stdout(opt.all)
-
-	return nil
-}
+	if opt.html {
+		return dlts.html(os.Stdout, time.Now())
+	}
 
-func (dlts *DetailLeadTimeStat) drawGraph() error {
-	p := plot.New()
-	p.X.Label.Text = "PR number"
-	p.Y.Label.Text = "Lead Time[min]"
+	if opt.summaryCSV {
+		return dlts.summaryCSV(os.Stdout)
+	}
 
-	data := make(plotter.XYs, 0, len(dlts.PullRequests))
-	for _, v := range dlts.PullRequests {
-		data = append(data, plotter.XY{
-			X: float64(v.Number),
-			Y: float64(v.MergeTimeMinutes),
-		})
+	if opt.groupCSV {
+		return dlts.groupCSV(os.Stdout)
 	}
 
-	line, err := plotter.NewLine(data)
-	if err != nil {
-		return err
+	if opt.csv || opt.tsv {
+		comma := commaCSV
+		if opt.tsv {
+			comma = commaTSV
+		}
+		return dlts.detailCSV(os.Stdout, comma)
 	}
-	p.Add(plotter.NewGrid())
-	p.Y.Max = float64(dlts.max()) + 100
-	line.Color = color.RGBA{R: 226, G: 45, B: 60, A: 255}
-	line.Width = vg.Points(1.5)
-	p.Add(line)
 
-	if err := p.Save(4*vg.Inch, 4*vg.Inch, "leadtime.png"); err != nil {
-		return err
+	if opt.json {
+		if err := dlts.json(os.Stdout, opt.all); err != nil {
+			return err
+		}
+		return nil
 	}
+
+	dlts.stdout(opt.all)
+
 	return nil
 }
 
-func (dlts *DetailLeadTimeStat) markdown(all bool) {
+func (dlts *DetailLeadTimeStat) markdown(all bool, chart, trendChart string) {
 	fmt.Println("# Pull Request Lead Time")
 	fmt.Println("## Statistics")
 	fmt.Printf("Statistics were calculated for %d closed PRs.  \n", len(dlts.PullRequests))
@@ -222,33 +522,69 @@ func (dlts *DetailLeadTimeStat) markdown(all bool) {
 	fmt.Printf("| Lead Time(Sum)|%d[min]|\n", dlts.sum())
 	fmt.Printf("| Lead Time(Ave)|%.2f[min]|\n", dlts.average())
 	fmt.Printf("| Lead Time(MN )|%.2f[min]|\n", dlts.median())
+	for _, v := range dlts.LeadTimeStatistics.distribution() {
+		fmt.Printf("| Lead Time(%s)|%.2f[min]|\n", v.name, v.value)
+	}
+	fmt.Println()
+	fmt.Println("## Time to Merge Statistics")
+	fmt.Println("| Item | Span | PRs | Max | Min | Ave | MN |")
+	fmt.Println("|:-----|:-----|:----|:----|:----|:----|:---|")
+	ttm := dlts.LeadTimeStatistics.timeToMergeSummary()
+	fmt.Printf("|%s|%s|%d|%d[min]|%d[min]|%.2f[min]|%.2f[min]|\n",
+		ttm.name, ttm.description, ttm.stat.TotalPR, ttm.stat.Maximum, ttm.stat.Minimum, ttm.stat.Average, ttm.stat.Median)
+	fmt.Println()
+	fmt.Println("## Stage Statistics")
+	fmt.Println("| Stage | Span | PRs | Max | Min | Ave | MN |")
+	fmt.Println("|:------|:-----|:----|:----|:----|:----|:---|")
+	for _, v := range dlts.LeadTimeStatistics.stageSummaries() {
+		fmt.Printf("|%s|%s|%d|%d[min]|%d[min]|%.2f[min]|%.2f[min]|\n",
+			v.name, v.description, v.stat.TotalPR, v.stat.Maximum, v.stat.Minimum, v.stat.Average, v.stat.Median)
+	}
+	fmt.Println()
+	fmt.Println("## Abandonment Statistics")
+	fmt.Println("PRs closed without merge. Time to abandon is from creating PR to closing PR.  ")
+	fmt.Println("| Item | Result |")
+	fmt.Println("|:-----|:-------|")
+	for _, v := range dlts.LeadTimeStatistics.Abandonment.items() {
+		fmt.Printf("| %s|%s|\n", v.name, v.value)
+	}
 	fmt.Println()
-	fmt.Println("![PR Lead Time](./leadtime.png)")
+	fmt.Printf("![PR Lead Time](%s)\n", chart)
 	fmt.Println()
+	if len(dlts.LeadTimeStatistics.Repositories) != 0 {
+		dlts.repositoryMarkdown()
+	}
+	if dlts.LeadTimeStatistics.GroupBy != "" {
+		dlts.groupMarkdown(trendChart)
+	}
 
 	if all {
 		fmt.Println("## Pull Request Detail")
-		fmt.Println("| Number | Author | Bot | LeadTime[min] | Title |")
-		fmt.Println("|:-------|:-------|:----|:--------------|:------|")
+		fmt.Println("| Number | Author | Bot | LeadTime[min] | TimeToMerge[min] | Coding[min] | Pickup[min] | Review[min] | Merge[min] | Title |")
+		fmt.Println("|:-------|:-------|:----|:--------------|:-----------------|:------------|:------------|:------------|:-----------|:------|")
 		for _, v := range dlts.PullRequests {
-			if v.User.Bot {
-				fmt.Printf("|#%d|%s|%s|%d|%s|\n", v.Number, pointer.StringValue(v.User.Name), "yes", v.MergeTimeMinutes, v.Title)
-				continue
+			bot := "no"
+			if v.User.IsBot() {
+				bot = "yes"
 			}
-			fmt.Printf("|#%d|%s|%s|%d|%s|\n", v.Number, pointer.StringValue(v.User.Name), "no", v.MergeTimeMinutes, v.Title)
+			fmt.Printf("|%s|%s|%s|%d|%s|%s|%s|%s|%s|%s|\n", dlts.prName(v), authorName(v), bot, v.MergeTimeMinutes,
+				minutesString(v.TimeToMergeMinutes), minutesString(v.CodingTimeMinutes), minutesString(v.PickupTimeMinutes),
+				minutesString(v.ReviewTimeMinutes), minutesString(v.MergeStageTimeMinutes), v.Title)
 		}
 	}
 }
 
 func (dlts *DetailLeadTimeStat) stdout(all bool) {
 	if all {
-		fmt.Printf("PR\tAuthor\tBot\tLeadTime[min]\tTitle\n")
+		fmt.Printf("PR\tAuthor\tBot\tLeadTime[min]\tTimeToMerge[min]\tCoding[min]\tPickup[min]\tReview[min]\tMerge[min]\tTitle\n")
 		for _, v := range dlts.PullRequests {
-			if v.User.Bot {
-				fmt.Printf("#%d\t%s\t%s\t%d\t%s\n", v.Number, pointer.StringValue(v.User.Name), "yes", v.MergeTimeMinutes, v.Title)
-				continue
+			bot := "no"
+			if v.User.IsBot() {
+				bot = "yes"
 			}
-			fmt.Printf("#%d\t%s\t%s\t%d\t%s\n", v.Number, pointer.StringValue(v.User.Name), "no", v.MergeTimeMinutes, v.Title)
+			fmt.Printf("%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", dlts.prName(v), authorName(v), bot, v.MergeTimeMinutes,
+				minutesString(v.TimeToMergeMinutes), minutesString(v.CodingTimeMinutes), minutesString(v.PickupTimeMinutes),
+				minutesString(v.ReviewTimeMinutes), minutesString(v.MergeStageTimeMinutes), v.Title)
 		}
 		fmt.Println("")
 	}
@@ -259,6 +595,33 @@ func (dlts *DetailLeadTimeStat) stdout(all bool) {
 	fmt.Printf(" Lead Time(Sum) = %d[min]\n", dlts.sum())
 	fmt.Printf(" Lead Time(Ave) = %.2f[min]\n", dlts.average())
 	fmt.Printf(" Lead Time(Median) = %.2f[min]\n", dlts.median())
+	for _, v := range dlts.LeadTimeStatistics.distribution() {
+		fmt.Printf(" Lead Time(%s) = %.2f[min]\n", v.name, v.value)
+	}
+
+	summaries := append([]stageSummary{dlts.LeadTimeStatistics.timeToMergeSummary()}, dlts.LeadTimeStatistics.stageSummaries()...)
+	for _, v := range summaries {
+		fmt.Println("")
+		fmt.Printf("[%s: %s]\n", strings.ToLower(v.name), v.description)
+		fmt.Printf(" Total PR = %d\n", v.stat.TotalPR)
+		fmt.Printf(" %s(Max) = %d[min]\n", v.name, v.stat.Maximum)
+		fmt.Printf(" %s(Min) = %d[min]\n", v.name, v.stat.Minimum)
+		fmt.Printf(" %s(Ave) = %.2f[min]\n", v.name, v.stat.Average)
+		fmt.Printf(" %s(Median) = %.2f[min]\n", v.name, v.stat.Median)
+	}
+
+	fmt.Println("")
+	fmt.Println("[abandonment: create PR to close PR without merge]")
+	for _, v := range dlts.LeadTimeStatistics.Abandonment.items() {
+		fmt.Printf(" %s = %s\n", v.name, v.value)
+	}
+
+	if len(dlts.LeadTimeStatistics.Repositories) != 0 {
+		dlts.repositoryStdout()
+	}
+	if dlts.LeadTimeStatistics.GroupBy != "" {
+		dlts.groupStdout()
+	}
 }
 
 // LeadTimeStat is Lead time statistics.
@@ -269,11 +632,78 @@ type LeadTimeStat struct {
 	LeadTimeSummation int     `json:"lead_time_summation,omitempty"`
 	LeadTimeAverage   float64 `json:"lead_time_average,omitempty"`
 	LeadTimeMedian    float64 `json:"lead_time_median,omitempty"`
+	// Distribution statistics are always output so that the JSON fields are stable.
+	LeadTimeP75                float64            `json:"lead_time_p75"`
+	LeadTimeP90                float64            `json:"lead_time_p90"`
+	LeadTimeP95                float64            `json:"lead_time_p95"`
+	LeadTimeP99                float64            `json:"lead_time_p99"`
+	LeadTimeStandardDeviation  float64            `json:"lead_time_standard_deviation"`
+	LeadTimeInterquartileRange float64            `json:"lead_time_interquartile_range"`
+	LeadTimePercentiles        map[string]float64 `json:"lead_time_percentiles,omitempty"`
+	TimeToMerge                *StageStat         `json:"time_to_merge,omitempty"`
+	CodingTime                 *StageStat         `json:"coding_time,omitempty"`
+	PickupTime                 *StageStat         `json:"pickup_time,omitempty"`
+	ReviewTime                 *StageStat         `json:"review_time,omitempty"`
+	MergeStageTime             *StageStat         `json:"merge_stage_time,omitempty"`
+	Abandonment                *AbandonmentStat   `json:"abandonment,omitempty"`
+	// Repositories is statistics of each repository. It is set only for multiple repositories.
+	Repositories []*GroupStat `json:"repositories,omitempty"`
+	GroupBy      string       `json:"group_by,omitempty"`
+	Groups       []*GroupStat `json:"groups,omitempty"`
+}
+
+// namedValue is statistic value with display name.
+type namedValue struct {
+	name  string
+	value float64
+}
+
+// distribution return distribution statistics of lead time in display order.
+// Additional percentiles are sorted in ascending order.
+func (lts *LeadTimeStat) distribution() []namedValue {
+	values := []namedValue{
+		{name: "P75", value: lts.LeadTimeP75},
+		{name: "P90", value: lts.LeadTimeP90},
+		{name: "P95", value: lts.LeadTimeP95},
+		{name: "P99", value: lts.LeadTimeP99},
+		{name: "StdDev", value: lts.LeadTimeStandardDeviation},
+		{name: "IQR", value: lts.LeadTimeInterquartileRange},
+	}
+
+	additional := make([]namedValue, 0, len(lts.LeadTimePercentiles))
+	for k, v := range lts.LeadTimePercentiles {
+		additional = append(additional, namedValue{name: strings.ToUpper(k), value: v})
+	}
+	sort.Slice(additional, func(i, j int) bool {
+		return percentileOrder(additional[i].name) < percentileOrder(additional[j].name)
+	})
+	return append(values, additional...)
+}
+
+// percentileOrder return percentile number of name (e.g. "P90" -> 90).
+func percentileOrder(name string) float64 {
+	p, err := strconv.ParseFloat(strings.TrimPrefix(name, "P"), 64)
+	if err != nil {
+		return 0
+	}
+	return p
+}
+
+// StageStat is statistics of one stage in the PR life cycle.
+type StageStat struct {
+	TotalPR int     `json:"total_pr,omitempty"`
+	Maximum int     `json:"maximum,omitempty"`
+	Minimum int     `json:"minimum,omitempty"`
+	Average float64 `json:"average,omitempty"`
+	Median  float64 `json:"median,omitempty"`
 }
 
 type DetailLeadTimeStat struct {
 	LeadTimeStatistics *LeadTimeStat          `json:"lead_time_statistics,omitempty"`
 	PullRequests       []*usecase.PullRequest `json:"pull_requests,omitempty"`
+	// closedPRs is PRs closed with or without merge. It is used for abandonment statistics
+	// because PRs closed without merge are removed from PullRequests by default.
+	closedPRs []*usecase.PullRequest
 }
 
 func newDetailLeadTimeStat(lt *usecase.LeadTime) *DetailLeadTimeStat {
@@ -300,19 +730,82 @@ func (dlts *DetailLeadTimeStat) json(w io.Writer, all bool) error {
 	return nil
 }
 
-func (dlts *DetailLeadTimeStat) stat() {
+// stat calculate lead time statistics. percentiles are additional percentiles
+// stored in LeadTimeStat.LeadTimePercentiles.
+func (dlts *DetailLeadTimeStat) stat(percentiles []float64) {
+	leadTimes := dlts.leadTimes()
+
+	var additional map[string]float64
+	if len(percentiles) != 0 {
+		additional = make(map[string]float64, len(percentiles))
+		for _, v := range percentiles {
+			additional[percentileName(v)] = percentile(leadTimes, v)
+		}
+	}
+
 	dlts.LeadTimeStatistics = &LeadTimeStat{
-		TotalPR:           len(dlts.PullRequests),
-		LeadTimeMaximum:   dlts.max(),
-		LeadTimeMinimum:   dlts.min(),
-		LeadTimeSummation: dlts.sum(),
-		LeadTimeAverage:   dlts.average(),
-		LeadTimeMedian:    dlts.median(),
+		TotalPR:                    len(dlts.PullRequests),
+		LeadTimeMaximum:            dlts.max(),
+		LeadTimeMinimum:            dlts.min(),
+		LeadTimeSummation:          dlts.sum(),
+		LeadTimeAverage:            dlts.average(),
+		LeadTimeMedian:             dlts.median(),
+		LeadTimeP75:                percentile(leadTimes, 75),
+		LeadTimeP90:                percentile(leadTimes, 90),
+		LeadTimeP95:                percentile(leadTimes, 95),
+		LeadTimeP99:                percentile(leadTimes, 99),
+		LeadTimeStandardDeviation:  standardDeviation(leadTimes),
+		LeadTimeInterquartileRange: interquartileRange(leadTimes),
+		LeadTimePercentiles:        additional,
+		TimeToMerge:                dlts.stageStat(timeToMerge),
+		CodingTime:                 dlts.stageStat(codingTime),
+		PickupTime:                 dlts.stageStat(pickupTime),
+		ReviewTime:                 dlts.stageStat(reviewTime),
+		MergeStageTime:             dlts.stageStat(mergeStageTime),
+		Abandonment:                newAbandonmentStat(dlts.closedPRs),
 	}
 }
 
+// group calculate statistics of each PR group.
+func (dlts *DetailLeadTimeStat) group(g groupBy, teams teamMap, thresholds sizeThresholds) {
+	if g == groupByNone {
+		return
+	}
+	dlts.LeadTimeStatistics.GroupBy = string(g)
+	dlts.LeadTimeStatistics.Groups = newGroupStats(g.groups(dlts.PullRequests, teams, thresholds))
+}
+
+// groupByRepository calculate statistics of each repository if there are multiple repositories.
+func (dlts *DetailLeadTimeStat) groupByRepository(repos []*usecase.Repository) {
+	if len(repos) < 2 {
+		return
+	}
+	dlts.LeadTimeStatistics.Repositories = newGroupStats(repositoryGroups(repos, dlts.PullRequests))
+}
+
+// prName return PR name for detail output. If there are multiple repositories,
+// the name includes repository name (e.g. nao1215/sqly#1).
+func (dlts *DetailLeadTimeStat) prName(pr *usecase.PullRequest) string {
+	if len(dlts.LeadTimeStatistics.Repositories) != 0 {
+		return fmt.Sprintf("%s#%d", pr.Repository, pr.Number)
+	}
+	return fmt.Sprintf("#%d", pr.Number)
+}
+
+// removePRs remove open PRs and excluded PRs. PRs closed without merge are also
+// removed unless --include-unmerged is specified.
 func (dlts *DetailLeadTimeStat) removePRs(opt *option) {
 	dlts.removeOpenPR()
+	dlts.removeExcludedPRs(opt)
+	dlts.closedPRs = dlts.PullRequests
+	if !opt.includeUnmerged {
+		dlts.removeUnmergedPR()
+	}
+}
+
+// removeExcludedPRs remove PRs excluded by --exclude-bot, --exclude-pr, --exclude-user,
+// --include-label and --exclude-label.
+func (dlts *DetailLeadTimeStat) removeExcludedPRs(opt *option) {
 	if opt.excludeBot {
 		dlts.removePRCreatedByBot()
 	}
@@ -322,6 +815,12 @@ func (dlts *DetailLeadTimeStat) removePRs(opt *option) {
 	if len(opt.excludeUsers) != 0 {
 		dlts.removePRsCreatedByTargetUser(opt.excludeUsers)
 	}
+	if len(opt.includeLabels) != 0 {
+		dlts.keepPRsWithLabel(opt.includeLabels)
+	}
+	if len(opt.excludeLabels) != 0 {
+		dlts.removePRsWithLabel(opt.excludeLabels)
+	}
 }
 
 func (dlts *DetailLeadTimeStat) removeOpenPR() {
@@ -335,6 +834,17 @@ func (dlts *DetailLeadTimeStat) removeOpenPR() {
 	dlts.PullRequests = prs
 }
 
+// openPRs return PRs that are not closed yet.
+func (dlts *DetailLeadTimeStat) openPRs() []*usecase.PullRequest {
+	prs := make([]*usecase.PullRequest, 0)
+	for _, v := range dlts.PullRequests {
+		if v.State == "open" {
+			prs = append(prs, v)
+		}
+	}
+	return prs
+}
+
 func (dlts *DetailLeadTimeStat) removePRCreatedByBot() {
 	prs := make([]*usecase.PullRequest, 0, len(dlts.PullRequests))
 	for _, v := range dlts.PullRequests {
@@ -360,7 +870,7 @@ func (dlts *DetailLeadTimeStat) removeSpecifiedPRs(removeTargetPRs []int) {
 func (dlts *DetailLeadTimeStat) removePRsCreatedByTargetUser(target []string) {
 	prs := make([]*usecase.PullRequest, 0, len(dlts.PullRequests))
 	for _, v := range dlts.PullRequests {
-		if slices.Contains(target, pointer.StringValue(v.User.Name)) {
+		if v.User != nil && slices.Contains(target, pointer.StringValue(v.User.Name)) {
 			continue
 		}
 		prs = append(prs, v)
@@ -368,80 +878,104 @@ func (dlts *DetailLeadTimeStat) removePRsCreatedByTargetUser(target []string) {
 	dlts.PullRequests = prs
 }
 
-func (dlts *DetailLeadTimeStat) min() int {
-	if len(dlts.PullRequests) == 0 {
-		return 0
-	}
-
-	min := dlts.PullRequests[0].MergeTimeMinutes
-	for _, v := range dlts.PullRequests[1:] {
-		if v.MergeTimeMinutes < min {
-			min = v.MergeTimeMinutes
-		}
+// leadTimes return lead time of each PR.
+func (dlts *DetailLeadTimeStat) leadTimes() []int {
+	nums := make([]int, 0, len(dlts.PullRequests))
+	for _, v := range dlts.PullRequests {
+		nums = append(nums, v.MergeTimeMinutes)
 	}
+	return nums
+}
 
-	return min
+func (dlts *DetailLeadTimeStat) min() int {
+	return minimum(dlts.leadTimes())
 }
 
 func (dlts *DetailLeadTimeStat) max() int {
-	if len(dlts.PullRequests) == 0 {
-		return 0
-	}
+	return maximum(dlts.leadTimes())
+}
 
-	max := dlts.PullRequests[0].MergeTimeMinutes
-	for _, v := range dlts.PullRequests[1:] {
-		if v.MergeTimeMinutes > max {
-			max = v.MergeTimeMinutes
-		}
-	}
+func (dlts *DetailLeadTimeStat) average() float64 {
+	return average(dlts.leadTimes())
+}
 
-	return max
+func (dlts *DetailLeadTimeStat) sum() int {
+	return summation(dlts.leadTimes())
 }
 
-func (dlts *DetailLeadTimeStat) average() float64 {
-	if len(dlts.PullRequests) == 0 {
-		return 0
-	}
+func (dlts *DetailLeadTimeStat) median() float64 {
+	return median(dlts.leadTimes())
+}
 
-	sum := float64(0)
+// stageTimes return measurable stage time of each PR.
+// PRs that did not reach the stage (e.g. no review) are skipped.
+func (dlts *DetailLeadTimeStat) stageTimes(stage func(pr *usecase.PullRequest) *int) []int {
+	nums := make([]int, 0, len(dlts.PullRequests))
 	for _, v := range dlts.PullRequests {
-		sum += float64(v.MergeTimeMinutes)
+		if minutes := stage(v); minutes != nil {
+			nums = append(nums, *minutes)
+		}
 	}
-
-	return sum / float64(len(dlts.PullRequests))
+	return nums
 }
 
-func (dlts *DetailLeadTimeStat) sum() int {
-	if len(dlts.PullRequests) == 0 {
-		return 0
+func (dlts *DetailLeadTimeStat) stageStat(stage func(pr *usecase.PullRequest) *int) *StageStat {
+	nums := dlts.stageTimes(stage)
+	return &StageStat{
+		TotalPR: len(nums),
+		Maximum: maximum(nums),
+		Minimum: minimum(nums),
+		Average: average(nums),
+		Median:  median(nums),
 	}
+}
 
-	sum := 0
-	for _, v := range dlts.PullRequests {
-		sum += v.MergeTimeMinutes
-	}
+func timeToMerge(pr *usecase.PullRequest) *int {
+	return pr.TimeToMergeMinutes
+}
 
-	return sum
+func codingTime(pr *usecase.PullRequest) *int {
+	return pr.CodingTimeMinutes
 }
 
-func (dlts *DetailLeadTimeStat) median() float64 {
-	if len(dlts.PullRequests) == 0 {
-		return 0
-	}
+func pickupTime(pr *usecase.PullRequest) *int {
+	return pr.PickupTimeMinutes
+}
 
-	nums := make([]int, 0, len(dlts.PullRequests))
-	for _, v := range dlts.PullRequests {
-		nums = append(nums, v.MergeTimeMinutes)
-	}
-	sort.Ints(nums)
+func reviewTime(pr *usecase.PullRequest) *int {
+	return pr.ReviewTimeMinutes
+}
 
-	var median float64
-	mid := len(nums) / 2
-	if len(nums)%2 == 0 {
-		median = float64(nums[mid-1]+nums[mid]) / 2
-	} else {
-		median = float64(nums[mid])
+func mergeStageTime(pr *usecase.PullRequest) *int {
+	return pr.MergeStageTimeMinutes
+}
+
+// timeToMergeSummary return time to merge statistics with its display name.
+func (lts *LeadTimeStat) timeToMergeSummary() stageSummary {
+	return stageSummary{name: "Time to Merge", description: "create PR to merge PR", stat: lts.TimeToMerge}
+}
+
+// stageSummaries return stage statistics with their display names in the PR life cycle order.
+func (lts *LeadTimeStat) stageSummaries() []stageSummary {
+	return []stageSummary{
+		{name: "Coding Time", description: "first commit to create PR", stat: lts.CodingTime},
+		{name: "Pickup Time", description: "create PR to first review", stat: lts.PickupTime},
+		{name: "Review Time", description: "first review to approval", stat: lts.ReviewTime},
+		{name: "Merge Time", description: "approval to merge PR", stat: lts.MergeStageTime},
 	}
+}
 
-	return median
+// stageSummary is stage statistics with display information.
+type stageSummary struct {
+	name        string
+	description string
+	stat        *StageStat
+}
+
+// minutesString return minutes for table output. If minutes is nil, return "-".
+func minutesString(minutes *int) string {
+	if minutes == nil {
+		return "-"
+	}
+	return strconv.Itoa(*minutes)
 }
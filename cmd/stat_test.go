@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/shogo82148/pointer"
+)
+
+func prNumbers(prs []*usecase.PullRequest) []int {
+	numbers := make([]int, 0, len(prs))
+	for _, v := range prs {
+		numbers = append(numbers, v.Number)
+	}
+	return numbers
+}
+
+func Test_removePRsByUser_nilUser(t *testing.T) {
+	t.Parallel()
+
+	newStat := func() *DetailLeadTimeStat {
+		return &DetailLeadTimeStat{
+			PullRequests: []*usecase.PullRequest{
+				{Number: 1, User: &model.User{Name: pointer.String("alice")}},
+				{Number: 2, User: &model.User{Name: pointer.String("dependabot[bot]"), Bot: true}},
+				{Number: 3},
+			},
+		}
+	}
+
+	t.Run("Remove PRs created by bot", func(t *testing.T) {
+		t.Parallel()
+
+		dlts := newStat()
+		dlts.removePRCreatedByBot()
+		if diff := cmp.Diff([]int{1, 3}, prNumbers(dlts.PullRequests)); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Remove PRs created by target user", func(t *testing.T) {
+		t.Parallel()
+
+		dlts := newStat()
+		dlts.removePRsCreatedByTargetUser([]string{"alice"})
+		if diff := cmp.Diff([]int{2, 3}, prNumbers(dlts.PullRequests)); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+// newValidOption return option that passes option.valid.
+func newValidOption() *option {
+	return &option{
+		provider:       providerGitHub,
+		sizeThresholds: defaultSizeThresholds,
+		chartType:      chartTypeLine,
+		chartFormat:    "png",
+		chartWidth:     6,
+		chartHeight:    4,
+		pushJob:        "leadtime",
+	}
+}
+
+func Test_option_valid_groupCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		modify func(o *option)
+		want   error
+	}{
+		{
+			name:   "Group csv with group by",
+			modify: func(o *option) { o.groupCSV, o.groupBy = true, groupByWeek },
+		},
+		{
+			name:   "Group csv without group by",
+			modify: func(o *option) { o.groupCSV = true },
+			want:   ErrGroupCSVRequiresGroupBy,
+		},
+		{
+			name:   "Group csv with csv",
+			modify: func(o *option) { o.groupCSV, o.csv, o.groupBy = true, true, groupByWeek },
+			want:   ErrMultipleOutputFlag,
+		},
+		{
+			name:   "Csv with group by outputs every PR",
+			modify: func(o *option) { o.csv, o.groupBy = true, groupByWeek },
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := newValidOption()
+			tt.modify(o)
+			if err := o.valid(); !errors.Is(err, tt.want) {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, err)
+			}
+		})
+	}
+}
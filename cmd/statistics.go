@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// minimum return minimum value in nums. If nums is empty, return 0.
+func minimum(nums []int) int {
+	if len(nums) == 0 {
+		return 0
+	}
+
+	min := nums[0]
+	for _, v := range nums[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+// maximum return maximum value in nums. If nums is empty, return 0.
+func maximum(nums []int) int {
+	if len(nums) == 0 {
+		return 0
+	}
+
+	max := nums[0]
+	for _, v := range nums[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// summation return sum of nums.
+func summation(nums []int) int {
+	sum := 0
+	for _, v := range nums {
+		sum += v
+	}
+	return sum
+}
+
+// average return average of nums. If nums is empty, return 0.
+func average(nums []int) float64 {
+	if len(nums) == 0 {
+		return 0
+	}
+	return float64(summation(nums)) / float64(len(nums))
+}
+
+// median return median of nums. If nums is empty, return 0.
+func median(nums []int) float64 {
+	if len(nums) == 0 {
+		return 0
+	}
+
+	sorted := make([]int, len(nums))
+	copy(sorted, nums)
+	sort.Ints(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return float64(sorted[mid-1]+sorted[mid]) / 2
+	}
+	return float64(sorted[mid])
+}
+
+// percentile return p-th percentile (0 <= p <= 100) of nums with linear
+// interpolation between closest ranks. If nums is empty, return 0.
+// percentile(nums, 50) is equal to median(nums).
+func percentile(nums []int, p float64) float64 {
+	if len(nums) == 0 {
+		return 0
+	}
+
+	sorted := make([]int, len(nums))
+	copy(sorted, nums)
+	sort.Ints(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower >= len(sorted)-1 {
+		return float64(sorted[len(sorted)-1])
+	}
+	fraction := rank - float64(lower)
+	return float64(sorted[lower]) + fraction*float64(sorted[lower+1]-sorted[lower])
+}
+
+// standardDeviation return population standard deviation of nums. If nums is empty, return 0.
+func standardDeviation(nums []int) float64 {
+	if len(nums) == 0 {
+		return 0
+	}
+
+	ave := average(nums)
+	variance := 0.0
+	for _, v := range nums {
+		variance += (float64(v) - ave) * (float64(v) - ave)
+	}
+	return math.Sqrt(variance / float64(len(nums)))
+}
+
+// interquartileRange return difference between 75th and 25th percentile of nums.
+func interquartileRange(nums []int) float64 {
+	return percentile(nums, 75) - percentile(nums, 25)
+}
+
+// percentileName return name of p-th percentile (e.g. "p90", "p99.9").
+func percentileName(p float64) string {
+	return "p" + strconv.FormatFloat(p, 'f', -1, 64)
+}
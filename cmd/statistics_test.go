@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_percentile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		nums []int
+		p    float64
+		want float64
+	}{
+		{name: "Empty", nums: nil, p: 90, want: 0},
+		{name: "One value", nums: []int{7}, p: 99, want: 7},
+		{name: "P50 of even number of values is median", nums: []int{4, 1, 3, 2}, p: 50, want: 2.5},
+		{name: "Interpolate between closest ranks", nums: []int{10, 20, 30, 40, 50}, p: 90, want: 46},
+		{name: "Fractional percentile", nums: []int{0, 1000}, p: 99.9, want: 999},
+		{name: "P0 is minimum", nums: []int{5, 3, 9}, p: 0, want: 3},
+		{name: "P100 is maximum", nums: []int{5, 3, 9}, p: 100, want: 9},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := percentile(tt.nums, tt.p); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_percentile_doesNotSortInput(t *testing.T) {
+	t.Parallel()
+
+	nums := []int{3, 1, 2}
+	percentile(nums, 50)
+	if nums[0] != 3 || nums[1] != 1 || nums[2] != 2 {
+		t.Errorf("input is modified: %v", nums)
+	}
+}
+
+func Test_standardDeviation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		nums []int
+		want float64
+	}{
+		{name: "Empty", nums: nil, want: 0},
+		{name: "Same values", nums: []int{5, 5, 5}, want: 0},
+		{name: "Population standard deviation", nums: []int{2, 4, 4, 4, 5, 5, 7, 9}, want: 2},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := standardDeviation(tt.nums); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_interquartileRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		nums []int
+		want float64
+	}{
+		{name: "Empty", nums: nil, want: 0},
+		{name: "P75 - P25", nums: []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, want: 4},
+		{name: "Interpolated quartiles", nums: []int{10, 20, 30, 40}, want: 15},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := interquartileRange(tt.nums); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_percentileName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		p    float64
+		want string
+	}{
+		{name: "Integer percentile", p: 90, want: "p90"},
+		{name: "Percentile with one decimal place", p: 99.9, want: "p99.9"},
+		{name: "Percentile with two decimal places", p: 50.25, want: "p50.25"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := percentileName(tt.p); got != tt.want {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
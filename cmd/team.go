@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nao1215/leadtime/domain/usecase"
+)
+
+// unassignedTeam is team name of authors who are not in the team file.
+const unassignedTeam = "unassigned"
+
+// teamMap is team name of each login. Keys are lower case because logins are case insensitive.
+type teamMap map[string]string
+
+// loadTeamMap read the JSON file that maps logins to team names
+// (e.g. {"nao1215": "backend", "alice": "frontend"}).
+func loadTeamMap(path string) (teamMap, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var logins map[string]string
+	if err := json.Unmarshal(b, &logins); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidTeamFile, path, err.Error())
+	}
+
+	teams := make(teamMap, len(logins))
+	for login, team := range logins {
+		if strings.TrimSpace(login) == "" || strings.TrimSpace(team) == "" {
+			return nil, fmt.Errorf("%w: %s: login and team must not be empty", ErrInvalidTeamFile, path)
+		}
+		teams[strings.ToLower(login)] = team
+	}
+	return teams, nil
+}
+
+// team return team of PR author. If the author is not in the map, return unassignedTeam.
+func (t teamMap) team(pr *usecase.PullRequest) string {
+	if team, ok := t[strings.ToLower(authorName(pr))]; ok {
+		return team
+	}
+	return unassignedTeam
+}
+
+// groups return PR group of each team in ascending order of team name.
+// The group of authors who are not in the map is the last.
+func (t teamMap) groups(prs []*usecase.PullRequest) []*prGroup {
+	index := make(map[string]*prGroup)
+	groups := make([]*prGroup, 0)
+	for _, v := range prs {
+		name := t.team(v)
+		g, ok := index[name]
+		if !ok {
+			g = &prGroup{name: name}
+			index[name] = g
+			groups = append(groups, g)
+		}
+		g.prs = append(g.prs, v)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].name == unassignedTeam) != (groups[j].name == unassignedTeam) {
+			return groups[j].name == unassignedTeam
+		}
+		return groups[i].name < groups[j].name
+	})
+	return groups
+}
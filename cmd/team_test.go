@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/shogo82148/pointer"
+)
+
+func Test_loadTeamMap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    teamMap
+		wantErr error
+	}{
+		{
+			name:    "Login is lower case",
+			content: `{"Nao1215": "backend", "alice": "frontend"}`,
+			want:    teamMap{"nao1215": "backend", "alice": "frontend"},
+		},
+		{
+			name:    "Not JSON object",
+			content: `["nao1215"]`,
+			wantErr: ErrInvalidTeamFile,
+		},
+		{
+			name:    "Empty team",
+			content: `{"nao1215": " "}`,
+			wantErr: ErrInvalidTeamFile,
+		},
+		{
+			name:    "Empty login",
+			content: `{"": "backend"}`,
+			wantErr: ErrInvalidTeamFile,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "teams.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := loadTeamMap(path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("mismatch want=%v, got=%v", tt.wantErr, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("File does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := loadTeamMap(filepath.Join(t.TempDir(), "not_exist.json")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("mismatch want=%v, got=%v", os.ErrNotExist, err)
+		}
+	})
+}
+
+func Test_teamMap_groups(t *testing.T) {
+	t.Parallel()
+
+	pr := func(number int, login string) *usecase.PullRequest {
+		if login == "" {
+			return &usecase.PullRequest{Number: number}
+		}
+		return &usecase.PullRequest{Number: number, User: &model.User{Name: pointer.String(login)}}
+	}
+	prs := []*usecase.PullRequest{
+		pr(1, "Nao1215"),
+		pr(2, "carol"),
+		pr(3, "alice"),
+		pr(4, ""),
+		pr(5, "nao1215"),
+	}
+	teams := teamMap{"nao1215": "frontend", "alice": "backend"}
+
+	want := []groupNumbers{
+		{Name: "backend", Numbers: []int{3}},
+		{Name: "frontend", Numbers: []int{1, 5}},
+		{Name: unassignedTeam, Numbers: []int{2, 4}},
+	}
+	if diff := cmp.Diff(want, toGroupNumbers(teams.groups(prs))); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -2,6 +2,13 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/caarlos0/env/v7"
 	"github.com/nao1215/leadtime/domain/model"
 )
@@ -10,6 +17,15 @@ import (
 type GitHubConfig struct {
 	// AccessToken is access token for GitHub API.
 	AccessToken model.Token `env:"LT_GITHUB_ACCESS_TOKEN,required"`
+	// MaxRetries is maximum number of retries when GitHub API rate limit is exceeded.
+	MaxRetries int `env:"LT_GITHUB_MAX_RETRIES" envDefault:"5"`
+	// MaxRetryWait is maximum time to wait for GitHub API rate limit reset at one retry.
+	MaxRetryWait time.Duration `env:"LT_GITHUB_MAX_RETRY_WAIT" envDefault:"1h"`
+	// APIURL is base URL of GitHub Enterprise Server API (e.g. https://github.example.com/api/v3/).
+	// If APIURL is empty, github.com is used.
+	APIURL string `env:"LT_GITHUB_API_URL"`
+	// GraphQL is whether GitHub GraphQL API is used to fetch pull requests or not.
+	GraphQL bool
 }
 
 // NewGitHubConfig initialize github config.
@@ -18,13 +34,182 @@ type GitHubConfig struct {
 func NewGitHubConfig() (*GitHubConfig, error) {
 	cfg := &GitHubConfig{}
 	if err := env.Parse(cfg); err != nil {
-		return nil, ErrNotSetGitHubAccessToken
+		if errors.Is(err, env.EnvVarIsNotSetError{}) {
+			return nil, ErrNotSetGitHubAccessToken
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEnvironmentVariable, err.Error())
+	}
+
+	if err := cfg.Valid(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Valid check whether config is valid or not.
+func (c *GitHubConfig) Valid() error {
+	if c.APIURL == "" {
+		return nil
+	}
+	return validURL(c.APIURL, ErrInvalidGitHubAPIURL)
+}
+
+// validURL check whether rawURL is http(s) URL or not. If it is invalid, return
+// error that wraps sentinel.
+func validURL(rawURL string, sentinel error) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s", sentinel, err.Error())
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme must be http or https: %s", sentinel, rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%w: host is empty: %s", sentinel, rawURL)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("%w: query and fragment are not allowed: %s", sentinel, rawURL)
+	}
+	return nil
+}
+
+// GitLabConfig represents configuration for GitLab.
+type GitLabConfig struct {
+	// Token is personal access token for GitLab API.
+	Token model.Token `env:"LT_GITLAB_TOKEN,required"`
+	// URL is URL of GitLab (e.g. https://gitlab.example.com).
+	URL string `env:"LT_GITLAB_URL" envDefault:"https://gitlab.com"`
+}
+
+// NewGitLabConfig initialize gitlab config.
+// If user does not set environment variable LT_GITLAB_TOKEN,
+// return error.
+func NewGitLabConfig() (*GitLabConfig, error) {
+	cfg := &GitLabConfig{}
+	if err := env.Parse(cfg); err != nil {
+		if errors.Is(err, env.EnvVarIsNotSetError{}) {
+			return nil, ErrNotSetGitLabToken
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEnvironmentVariable, err.Error())
+	}
+
+	if err := cfg.Valid(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Valid check whether config is valid or not.
+func (c *GitLabConfig) Valid() error {
+	return validURL(c.URL, ErrInvalidGitLabURL)
+}
+
+// GiteaConfig represents configuration for Gitea or Forgejo.
+type GiteaConfig struct {
+	// Token is access token for Gitea API.
+	Token model.Token `env:"LT_GITEA_TOKEN,required"`
+	// URL is URL of Gitea (e.g. https://gitea.example.com).
+	URL string `env:"LT_GITEA_URL" envDefault:"https://gitea.com"`
+}
+
+// NewGiteaConfig initialize gitea config.
+// If user does not set environment variable LT_GITEA_TOKEN,
+// return error.
+func NewGiteaConfig() (*GiteaConfig, error) {
+	cfg := &GiteaConfig{}
+	if err := env.Parse(cfg); err != nil {
+		if errors.Is(err, env.EnvVarIsNotSetError{}) {
+			return nil, ErrNotSetGiteaToken
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEnvironmentVariable, err.Error())
+	}
+
+	if err := cfg.Valid(); err != nil {
+		return nil, err
 	}
+	return cfg, nil
+}
+
+// Valid check whether config is valid or not.
+func (c *GiteaConfig) Valid() error {
+	return validURL(c.URL, ErrInvalidGiteaURL)
+}
 
+// BitbucketConfig represents configuration for Bitbucket Cloud or Bitbucket Server/Data Center.
+type BitbucketConfig struct {
+	// Token is access token for Bitbucket API. If Username is set, it is app password.
+	Token model.Token `env:"LT_BITBUCKET_TOKEN,required"`
+	// Username is user name for basic authentication with app password.
+	// If it is empty, Token is sent as bearer token.
+	Username string `env:"LT_BITBUCKET_USERNAME"`
+	// URL is URL of Bitbucket. If URL is bitbucket.org or api.bitbucket.org,
+	// Bitbucket Cloud is used. Otherwise, Bitbucket Server/Data Center is used
+	// (e.g. https://bitbucket.example.com).
+	URL string `env:"LT_BITBUCKET_URL" envDefault:"https://bitbucket.org"`
+}
+
+// NewBitbucketConfig initialize bitbucket config.
+// If user does not set environment variable LT_BITBUCKET_TOKEN,
+// return error.
+func NewBitbucketConfig() (*BitbucketConfig, error) {
+	cfg := &BitbucketConfig{}
+	if err := env.Parse(cfg); err != nil {
+		if errors.Is(err, env.EnvVarIsNotSetError{}) {
+			return nil, ErrNotSetBitbucketToken
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEnvironmentVariable, err.Error())
+	}
+
+	if err := cfg.Valid(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
+// Valid check whether config is valid or not.
+func (c *BitbucketConfig) Valid() error {
+	return validURL(c.URL, ErrInvalidBitbucketURL)
+}
+
+// GitConfig represents configuration for local git repository.
+type GitConfig struct {
+	// Dir is path of local git repository.
+	Dir string
+}
+
+// FileConfig represents configuration for archive file exported by "leadtime export --raw".
+type FileConfig struct {
+	// Path is path of archive file.
+	Path string
+}
+
 // NewGitHubAccessToken return github access token
 func NewGitHubAccessToken(config *GitHubConfig) model.Token {
 	return config.AccessToken
 }
+
+// CacheConfig represents configuration for on-disk cache.
+type CacheConfig struct {
+	// Dir is cache directory. Default is leadtime directory under the user cache directory
+	// (e.g. $XDG_CACHE_HOME/leadtime).
+	Dir string `env:"LT_CACHE_DIR"`
+	// Enabled is whether the cache is used or not.
+	Enabled bool
+}
+
+// NewCacheConfig initialize cache config.
+func NewCacheConfig() (*CacheConfig, error) {
+	cfg := &CacheConfig{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEnvironmentVariable, err.Error())
+	}
+
+	if cfg.Dir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user cache directory: %w", err)
+		}
+		cfg.Dir = filepath.Join(dir, "leadtime")
+	}
+	return cfg, nil
+}
@@ -4,7 +4,9 @@ package config
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/nao1215/leadtime/domain/model"
@@ -18,7 +20,9 @@ func TestNewGitHubConfig(t *testing.T) { //nolint
 		t.Setenv("LT_GITHUB_ACCESS_TOKEN", token.String())
 
 		want := &GitHubConfig{
-			AccessToken: token,
+			AccessToken:  token,
+			MaxRetries:   5,
+			MaxRetryWait: time.Hour,
 		}
 		got, err := NewGitHubConfig()
 		if err != nil {
@@ -30,6 +34,59 @@ func TestNewGitHubConfig(t *testing.T) { //nolint
 		}
 	})
 
+	t.Run("Get retry policy from environment variable", func(t *testing.T) { //nolint
+		t.Setenv("LT_GITHUB_ACCESS_TOKEN", token.String())
+		t.Setenv("LT_GITHUB_MAX_RETRIES", "2")
+		t.Setenv("LT_GITHUB_MAX_RETRY_WAIT", "10m")
+
+		want := &GitHubConfig{
+			AccessToken:  token,
+			MaxRetries:   2,
+			MaxRetryWait: 10 * time.Minute,
+		}
+		got, err := NewGitHubConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("if user sets invalid retry policy", func(t *testing.T) { //nolint
+		t.Setenv("LT_GITHUB_ACCESS_TOKEN", token.String())
+		t.Setenv("LT_GITHUB_MAX_RETRIES", "many")
+
+		_, got := NewGitHubConfig()
+		if !errors.Is(got, ErrInvalidEnvironmentVariable) {
+			t.Errorf("mismatch want=%v, got=%v", ErrInvalidEnvironmentVariable, got)
+		}
+	})
+
+	t.Run("Get GitHub Enterprise Server API URL from environment variable", func(t *testing.T) { //nolint
+		t.Setenv("LT_GITHUB_ACCESS_TOKEN", token.String())
+		t.Setenv("LT_GITHUB_API_URL", "https://github.example.com/api/v3/")
+
+		got, err := NewGitHubConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.APIURL != "https://github.example.com/api/v3/" {
+			t.Errorf("mismatch want=%s, got=%s", "https://github.example.com/api/v3/", got.APIURL)
+		}
+	})
+
+	t.Run("if user sets invalid GitHub API URL", func(t *testing.T) { //nolint
+		t.Setenv("LT_GITHUB_ACCESS_TOKEN", token.String())
+		t.Setenv("LT_GITHUB_API_URL", "github.example.com/api/v3")
+
+		_, got := NewGitHubConfig()
+		if !errors.Is(got, ErrInvalidGitHubAPIURL) {
+			t.Errorf("mismatch want=%v, got=%v", ErrInvalidGitHubAPIURL, got)
+		}
+	})
+
 	t.Run("if user does not set github access token", func(t *testing.T) { //nolint
 		_, got := NewGitHubConfig()
 		if !errors.Is(got, ErrNotSetGitHubAccessToken) {
@@ -38,6 +95,153 @@ func TestNewGitHubConfig(t *testing.T) { //nolint
 	})
 }
 
+func TestNewGitLabConfig(t *testing.T) { //nolint
+	os.Unsetenv("LT_GITLAB_TOKEN")
+
+	t.Run("Get gitlab config", func(t *testing.T) { //nolint
+		t.Setenv("LT_GITLAB_TOKEN", "gitlab_token")
+
+		want := &GitLabConfig{Token: "gitlab_token", URL: "https://gitlab.com"}
+		got, err := NewGitLabConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Get self-hosted GitLab URL", func(t *testing.T) { //nolint
+		t.Setenv("LT_GITLAB_TOKEN", "gitlab_token")
+		t.Setenv("LT_GITLAB_URL", "https://gitlab.example.com")
+
+		got, err := NewGitLabConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.URL != "https://gitlab.example.com" {
+			t.Errorf("mismatch want=%s, got=%s", "https://gitlab.example.com", got.URL)
+		}
+	})
+
+	t.Run("if user sets invalid GitLab URL", func(t *testing.T) { //nolint
+		t.Setenv("LT_GITLAB_TOKEN", "gitlab_token")
+		t.Setenv("LT_GITLAB_URL", "gitlab.example.com")
+
+		_, got := NewGitLabConfig()
+		if !errors.Is(got, ErrInvalidGitLabURL) {
+			t.Errorf("mismatch want=%v, got=%v", ErrInvalidGitLabURL, got)
+		}
+	})
+
+	t.Run("if user does not set gitlab token", func(t *testing.T) { //nolint
+		_, got := NewGitLabConfig()
+		if !errors.Is(got, ErrNotSetGitLabToken) {
+			t.Errorf("mismatch want=%v, got=%v", ErrNotSetGitLabToken, got)
+		}
+	})
+}
+
+func TestNewGiteaConfig(t *testing.T) { //nolint
+	os.Unsetenv("LT_GITEA_TOKEN")
+
+	t.Run("Get gitea config", func(t *testing.T) { //nolint
+		t.Setenv("LT_GITEA_TOKEN", "gitea_token")
+		t.Setenv("LT_GITEA_URL", "https://gitea.example.com")
+
+		want := &GiteaConfig{Token: "gitea_token", URL: "https://gitea.example.com"}
+		got, err := NewGiteaConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("if user sets invalid Gitea URL", func(t *testing.T) { //nolint
+		t.Setenv("LT_GITEA_TOKEN", "gitea_token")
+		t.Setenv("LT_GITEA_URL", "ftp://gitea.example.com")
+
+		_, got := NewGiteaConfig()
+		if !errors.Is(got, ErrInvalidGiteaURL) {
+			t.Errorf("mismatch want=%v, got=%v", ErrInvalidGiteaURL, got)
+		}
+	})
+
+	t.Run("if user does not set gitea token", func(t *testing.T) { //nolint
+		_, got := NewGiteaConfig()
+		if !errors.Is(got, ErrNotSetGiteaToken) {
+			t.Errorf("mismatch want=%v, got=%v", ErrNotSetGiteaToken, got)
+		}
+	})
+}
+
+func TestNewBitbucketConfig(t *testing.T) { //nolint
+	os.Unsetenv("LT_BITBUCKET_TOKEN")
+
+	t.Run("Get bitbucket config", func(t *testing.T) { //nolint
+		t.Setenv("LT_BITBUCKET_TOKEN", "app_password")
+		t.Setenv("LT_BITBUCKET_USERNAME", "alice")
+
+		want := &BitbucketConfig{Token: "app_password", Username: "alice", URL: "https://bitbucket.org"}
+		got, err := NewBitbucketConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("if user sets invalid Bitbucket URL", func(t *testing.T) { //nolint
+		t.Setenv("LT_BITBUCKET_TOKEN", "token")
+		t.Setenv("LT_BITBUCKET_URL", "bitbucket.example.com")
+
+		_, got := NewBitbucketConfig()
+		if !errors.Is(got, ErrInvalidBitbucketURL) {
+			t.Errorf("mismatch want=%v, got=%v", ErrInvalidBitbucketURL, got)
+		}
+	})
+
+	t.Run("if user does not set bitbucket token", func(t *testing.T) { //nolint
+		_, got := NewBitbucketConfig()
+		if !errors.Is(got, ErrNotSetBitbucketToken) {
+			t.Errorf("mismatch want=%v, got=%v", ErrNotSetBitbucketToken, got)
+		}
+	})
+}
+
+func TestGitHubConfig_Valid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		apiURL string
+		want   error
+	}{
+		{name: "github.com", apiURL: "", want: nil},
+		{name: "https URL", apiURL: "https://github.example.com/api/v3/", want: nil},
+		{name: "http URL with port", apiURL: "http://localhost:8080", want: nil},
+		{name: "no scheme", apiURL: "github.example.com", want: ErrInvalidGitHubAPIURL},
+		{name: "unsupported scheme", apiURL: "ftp://github.example.com", want: ErrInvalidGitHubAPIURL},
+		{name: "no host", apiURL: "https:///api/v3", want: ErrInvalidGitHubAPIURL},
+		{name: "query", apiURL: "https://github.example.com/api/v3?x=1", want: ErrInvalidGitHubAPIURL},
+		{name: "parse error", apiURL: "https://github.example.com/%zz", want: ErrInvalidGitHubAPIURL},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &GitHubConfig{APIURL: tt.apiURL}
+			if err := cfg.Valid(); !errors.Is(err, tt.want) {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, err)
+			}
+		})
+	}
+}
+
 func TestNewGitHubAccessToken(t *testing.T) {
 	t.Run("Get access token", func(t *testing.T) {
 		config := &GitHubConfig{
@@ -51,3 +255,37 @@ func TestNewGitHubAccessToken(t *testing.T) {
 		}
 	})
 }
+
+func TestNewCacheConfig(t *testing.T) { //nolint
+	t.Run("Get cache directory from environment variable", func(t *testing.T) { //nolint
+		t.Setenv("LT_CACHE_DIR", "/tmp/leadtime")
+
+		got, err := NewCacheConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Dir != "/tmp/leadtime" {
+			t.Errorf("mismatch want=%s, got=%s", "/tmp/leadtime", got.Dir)
+		}
+	})
+
+	t.Run("Use user cache directory by default", func(t *testing.T) { //nolint
+		t.Setenv("LT_CACHE_DIR", "")
+		t.Setenv("XDG_CACHE_HOME", "/tmp/xdg")
+		t.Setenv("HOME", "/tmp/home")
+
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := NewCacheConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := filepath.Join(userCacheDir, "leadtime")
+		if got.Dir != want {
+			t.Errorf("mismatch want=%s, got=%s", want, got.Dir)
+		}
+	})
+}
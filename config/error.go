@@ -6,4 +6,20 @@ var (
 	// ErrNotSetGitHubAccessToken : for security concerns, set the environment variable
 	// LT_GITHUB_ACCESS_TOKEN to the GitHub access token. The token should not set by command argument.
 	ErrNotSetGitHubAccessToken = errors.New("GitHub access token is not set in the environment variable LT_GITHUB_ACCESS_TOKEN")
+	// ErrNotSetGitLabToken : set the environment variable LT_GITLAB_TOKEN to the GitLab access token.
+	ErrNotSetGitLabToken = errors.New("GitLab access token is not set in the environment variable LT_GITLAB_TOKEN")
+	// ErrNotSetGiteaToken : set the environment variable LT_GITEA_TOKEN to the Gitea access token.
+	ErrNotSetGiteaToken = errors.New("Gitea access token is not set in the environment variable LT_GITEA_TOKEN")
+	// ErrNotSetBitbucketToken : set the environment variable LT_BITBUCKET_TOKEN to the Bitbucket access token.
+	ErrNotSetBitbucketToken = errors.New("Bitbucket access token is not set in the environment variable LT_BITBUCKET_TOKEN")
+	// ErrInvalidEnvironmentVariable means "environment variable value is invalid"
+	ErrInvalidEnvironmentVariable = errors.New("environment variable value is invalid")
+	// ErrInvalidGitHubAPIURL means "GitHub API URL must be http(s) URL"
+	ErrInvalidGitHubAPIURL = errors.New("GitHub API URL must be http(s) URL")
+	// ErrInvalidGitLabURL means "GitLab URL must be http(s) URL"
+	ErrInvalidGitLabURL = errors.New("GitLab URL must be http(s) URL")
+	// ErrInvalidGiteaURL means "Gitea URL must be http(s) URL"
+	ErrInvalidGiteaURL = errors.New("Gitea URL must be http(s) URL")
+	// ErrInvalidBitbucketURL means "Bitbucket URL must be http(s) URL"
+	ErrInvalidBitbucketURL = errors.New("Bitbucket URL must be http(s) URL")
 )
@@ -7,23 +7,86 @@
 package di
 
 import (
+	"path/filepath"
+	"strings"
+
 	"github.com/nao1215/leadtime/config"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
 	"github.com/nao1215/leadtime/domain/usecase"
+	"github.com/nao1215/leadtime/infrastructure/archive"
+	"github.com/nao1215/leadtime/infrastructure/bitbucket"
+	"github.com/nao1215/leadtime/infrastructure/cache"
+	"github.com/nao1215/leadtime/infrastructure/git"
+	"github.com/nao1215/leadtime/infrastructure/gitea"
 	"github.com/nao1215/leadtime/infrastructure/github"
+	"github.com/nao1215/leadtime/infrastructure/gitlab"
 )
 
 // Injectors from wire.go:
 
-func NewLeadTime() (*LeadTime, error) {
-	gitHubConfig, err := config.NewGitHubConfig()
+func NewLeadTime(githubConfig *config.GitHubConfig, cacheConfig *config.CacheConfig) (*LeadTime, error) {
+	token := config.NewGitHubAccessToken(githubConfig)
+	retryPolicy := newRetryPolicy(githubConfig)
+	client, err := newGitHubClient(githubConfig, token, retryPolicy)
 	if err != nil {
 		return nil, err
 	}
-	token := config.NewGitHubAccessToken(gitHubConfig)
-	client := github.NewClient(token)
-	gitHubRepository := github.NewGitHubRepository(client)
+	gitHubRepository := newGitHubRepository(client, githubConfig, cacheConfig)
 	leadTimeUsecase := usecase.NewLeadTimeUsecase(gitHubRepository)
-	leadTime := newLeadTime(gitHubConfig, leadTimeUsecase)
+	leadTime := newLeadTime(githubConfig, leadTimeUsecase)
+	return leadTime, nil
+}
+
+func NewGitLabLeadTime(gitlabConfig *config.GitLabConfig, cacheConfig *config.CacheConfig) (*LeadTime, error) {
+	client, err := newGitLabClient(gitlabConfig)
+	if err != nil {
+		return nil, err
+	}
+	sourceRepository := newGitLabRepository(client, cacheConfig)
+	leadTimeUsecase := usecase.NewLeadTimeUsecase(sourceRepository)
+	leadTime := newSourceLeadTime(leadTimeUsecase)
+	return leadTime, nil
+}
+
+func NewGiteaLeadTime(giteaConfig *config.GiteaConfig, cacheConfig *config.CacheConfig) (*LeadTime, error) {
+	client, err := newGiteaClient(giteaConfig)
+	if err != nil {
+		return nil, err
+	}
+	sourceRepository := newGiteaRepository(client, cacheConfig)
+	leadTimeUsecase := usecase.NewLeadTimeUsecase(sourceRepository)
+	leadTime := newSourceLeadTime(leadTimeUsecase)
+	return leadTime, nil
+}
+
+func NewBitbucketLeadTime(bitbucketConfig *config.BitbucketConfig, cacheConfig *config.CacheConfig) (*LeadTime, error) {
+	sourceRepository, err := newBitbucketRepository(bitbucketConfig, cacheConfig)
+	if err != nil {
+		return nil, err
+	}
+	leadTimeUsecase := usecase.NewLeadTimeUsecase(sourceRepository)
+	leadTime := newSourceLeadTime(leadTimeUsecase)
+	return leadTime, nil
+}
+
+func NewGitLeadTime(gitConfig *config.GitConfig) (*LeadTime, error) {
+	sourceRepository, err := newGitRepository(gitConfig)
+	if err != nil {
+		return nil, err
+	}
+	leadTimeUsecase := usecase.NewLeadTimeUsecase(sourceRepository)
+	leadTime := newSourceLeadTime(leadTimeUsecase)
+	return leadTime, nil
+}
+
+func NewFileLeadTime(fileConfig *config.FileConfig) (*LeadTime, error) {
+	sourceRepository, err := newFileRepository(fileConfig)
+	if err != nil {
+		return nil, err
+	}
+	leadTimeUsecase := usecase.NewLeadTimeUsecase(sourceRepository)
+	leadTime := newSourceLeadTime(leadTimeUsecase)
 	return leadTime, nil
 }
 
@@ -31,6 +94,7 @@ func NewLeadTime() (*LeadTime, error) {
 
 // LeadTime is usecase set.
 type LeadTime struct {
+	// GithubConfig is nil if the source is not GitHub.
 	GithubConfig    *config.GitHubConfig
 	LeadTimeUsecase usecase.LeadTimeUsecase
 }
@@ -42,3 +106,115 @@ func newLeadTime(githubConfig *config.GitHubConfig, leadTimeUsecase usecase.Lead
 		LeadTimeUsecase: leadTimeUsecase,
 	}
 }
+
+// newGitHubRepository return repository for GitHub. If GraphQL is enabled,
+// pull requests are fetched by GitHub GraphQL API instead of REST API.
+// If the cache is enabled, fetched data is cached on disk per host.
+func newGitHubRepository(client *github.Client, githubConfig *config.GitHubConfig, cacheConfig *config.CacheConfig) repository.SourceRepository {
+	gitHubRepository := github.NewGitHubRepository(client)
+	if githubConfig.GraphQL {
+		gitHubRepository = github.NewGraphQLRepository(client)
+	}
+	if !cacheConfig.Enabled {
+		return gitHubRepository
+	}
+	host := client.BaseURL.Host
+	if host == "" || host == "api.github.com" {
+		host = "github.com"
+	}
+	return cache.NewRepository(gitHubRepository, cacheDir(cacheConfig, host))
+}
+
+// cacheDir return cache directory of the host so that the same repository name
+// on different hosts (e.g. github.com and GitHub Enterprise Server) are not mixed.
+func cacheDir(cacheConfig *config.CacheConfig, host string) string {
+	return filepath.Join(cacheConfig.Dir, strings.ReplaceAll(host, ":", "_"))
+}
+
+// newSourceLeadTime initialize LeadTime struct for the source other than GitHub.
+func newSourceLeadTime(leadTimeUsecase usecase.LeadTimeUsecase) *LeadTime {
+	return &LeadTime{
+		LeadTimeUsecase: leadTimeUsecase,
+	}
+}
+
+// newGitLabClient return client for GitLab.
+func newGitLabClient(gitlabConfig *config.GitLabConfig) (*gitlab.Client, error) {
+	return gitlab.NewClient(gitlabConfig.Token, gitlabConfig.URL)
+}
+
+// newGitLabRepository return repository for GitLab.
+// If the cache is enabled, fetched data is cached on disk per host.
+func newGitLabRepository(client *gitlab.Client, cacheConfig *config.CacheConfig) repository.SourceRepository {
+	gitLabRepository := gitlab.NewGitLabRepository(client)
+	if !cacheConfig.Enabled {
+		return gitLabRepository
+	}
+	return cache.NewRepository(gitLabRepository, cacheDir(cacheConfig, client.BaseURL.Host))
+}
+
+// newGiteaClient return client for Gitea or Forgejo.
+func newGiteaClient(giteaConfig *config.GiteaConfig) (*gitea.Client, error) {
+	return gitea.NewClient(giteaConfig.Token, giteaConfig.URL)
+}
+
+// newGiteaRepository return repository for Gitea or Forgejo.
+// If the cache is enabled, fetched data is cached on disk per host.
+func newGiteaRepository(client *gitea.Client, cacheConfig *config.CacheConfig) repository.SourceRepository {
+	giteaRepository := gitea.NewGiteaRepository(client)
+	if !cacheConfig.Enabled {
+		return giteaRepository
+	}
+	return cache.NewRepository(giteaRepository, cacheDir(cacheConfig, client.BaseURL.Host))
+}
+
+// newBitbucketRepository return repository for Bitbucket Cloud or Bitbucket Server/Data Center.
+// If the cache is enabled, fetched data is cached on disk per host.
+func newBitbucketRepository(bitbucketConfig *config.BitbucketConfig, cacheConfig *config.CacheConfig) (repository.SourceRepository, error) {
+	var (
+		client              *bitbucket.Client
+		bitbucketRepository repository.SourceRepository
+		err                 error
+	)
+	if bitbucket.IsCloud(bitbucketConfig.URL) {
+		client, err = bitbucket.NewCloudClient(bitbucketConfig.Token, bitbucketConfig.Username, "")
+		if err != nil {
+			return nil, err
+		}
+		bitbucketRepository = bitbucket.NewCloudRepository(client)
+	} else {
+		client, err = bitbucket.NewServerClient(bitbucketConfig.Token, bitbucketConfig.Username, bitbucketConfig.URL)
+		if err != nil {
+			return nil, err
+		}
+		bitbucketRepository = bitbucket.NewServerRepository(client)
+	}
+
+	if !cacheConfig.Enabled {
+		return bitbucketRepository, nil
+	}
+	return cache.NewRepository(bitbucketRepository, cacheDir(cacheConfig, client.BaseURL.Host)), nil
+}
+
+// newGitRepository return repository that derives pull requests from local git repository.
+func newGitRepository(gitConfig *config.GitConfig) (repository.SourceRepository, error) {
+	return git.NewGitRepository(gitConfig.Dir)
+}
+
+// newFileRepository return repository that replays archive file exported by "leadtime export --raw".
+func newFileRepository(fileConfig *config.FileConfig) (repository.SourceRepository, error) {
+	return archive.NewFileRepository(fileConfig.Path)
+}
+
+// newGitHubClient return client for github.com, or GitHub Enterprise Server if API URL is set.
+func newGitHubClient(githubConfig *config.GitHubConfig, token model.Token, policy *github.RetryPolicy) (*github.Client, error) {
+	if githubConfig.APIURL == "" {
+		return github.NewClient(token, policy), nil
+	}
+	return github.NewEnterpriseClient(token, policy, githubConfig.APIURL)
+}
+
+// newRetryPolicy return retry policy for GitHub API rate limit.
+func newRetryPolicy(githubConfig *config.GitHubConfig) *github.RetryPolicy {
+	return github.NewRetryPolicy(githubConfig.MaxRetries, githubConfig.MaxRetryWait)
+}
@@ -0,0 +1,95 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/nao1215/leadtime/config"
+)
+
+func TestNewLeadTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		githubConfig *config.GitHubConfig
+		cacheEnabled bool
+	}{
+		{
+			name:         "REST API without cache",
+			githubConfig: &config.GitHubConfig{AccessToken: "dummy"},
+		},
+		{
+			name:         "REST API with cache",
+			githubConfig: &config.GitHubConfig{AccessToken: "dummy"},
+			cacheEnabled: true,
+		},
+		{
+			name:         "GraphQL API on GitHub Enterprise Server with cache",
+			githubConfig: &config.GitHubConfig{AccessToken: "dummy", APIURL: "https://github.example.com/api/v3/", GraphQL: true},
+			cacheEnabled: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			lt, err := NewLeadTime(tt.githubConfig, &config.CacheConfig{Dir: t.TempDir(), Enabled: tt.cacheEnabled})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lt.LeadTimeUsecase == nil || lt.GithubConfig != tt.githubConfig {
+				t.Errorf("LeadTime is not built: %+v", lt)
+			}
+		})
+	}
+}
+
+func TestNewSourceLeadTime(t *testing.T) {
+	t.Parallel()
+
+	cacheConfig := &config.CacheConfig{Dir: t.TempDir(), Enabled: true}
+	tests := []struct {
+		name        string
+		newLeadTime func() (*LeadTime, error)
+	}{
+		{
+			name: "GitLab",
+			newLeadTime: func() (*LeadTime, error) {
+				return NewGitLabLeadTime(&config.GitLabConfig{Token: "dummy", URL: "https://gitlab.example.com"}, cacheConfig)
+			},
+		},
+		{
+			name: "Gitea",
+			newLeadTime: func() (*LeadTime, error) {
+				return NewGiteaLeadTime(&config.GiteaConfig{Token: "dummy", URL: "https://gitea.example.com"}, cacheConfig)
+			},
+		},
+		{
+			name: "Bitbucket Cloud",
+			newLeadTime: func() (*LeadTime, error) {
+				return NewBitbucketLeadTime(&config.BitbucketConfig{Token: "dummy", Username: "user", URL: "https://bitbucket.org"}, cacheConfig)
+			},
+		},
+		{
+			name: "Bitbucket Server",
+			newLeadTime: func() (*LeadTime, error) {
+				return NewBitbucketLeadTime(&config.BitbucketConfig{Token: "dummy", URL: "https://bitbucket.example.com"}, cacheConfig)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			lt, err := tt.newLeadTime()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lt.LeadTimeUsecase == nil || lt.GithubConfig != nil {
+				t.Errorf("LeadTime is not built: %+v", lt)
+			}
+		})
+	}
+}
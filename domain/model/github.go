@@ -32,8 +32,9 @@ type User struct {
 	Bot bool
 }
 
+// IsBot check whether user is bot or not. Unknown (nil) user is not bot.
 func (u *User) IsBot() bool {
-	return u.Bot
+	return u != nil && u.Bot
 }
 
 // Timestamp represents a time.
@@ -41,6 +42,14 @@ type Timestamp struct {
 	Time time.Time
 }
 
+// NewTimestamp return Timestamp of t. If t is nil, return nil.
+func NewTimestamp(t *time.Time) *Timestamp {
+	if t == nil {
+		return nil
+	}
+	return &Timestamp{Time: *t}
+}
+
 // PullRequest represents a GitHub pull request on a repository.
 type PullRequest struct {
 	// ID is PR's id.
@@ -53,6 +62,8 @@ type PullRequest struct {
 	Title *string
 	// CreatedAt is date of PR creation
 	CreatedAt *Timestamp
+	// UpdatedAt is date of PR last update
+	UpdatedAt *Timestamp
 	// ClosedAt is date of PR close
 	ClosedAt *Timestamp
 	// MergedAt is date of PR merged
@@ -67,6 +78,8 @@ type PullRequest struct {
 	Deletions *int
 	// ChangedFiles is number of changed files
 	ChangedFiles *int
+	// Labels is names of PR labels
+	Labels []string
 }
 
 // IsClosed check whether pull request is closed or not.
@@ -78,6 +91,44 @@ func (pr *PullRequest) IsClosed() bool {
 	return *pr.State == "closed"
 }
 
+// HasSize check whether pull request has number of added and deleted lines and changed files.
+func (pr *PullRequest) HasSize() bool {
+	return pr.Additions != nil && pr.Deletions != nil && pr.ChangedFiles != nil
+}
+
+// WithSize return copy of pull request whose size values that are nil are set from size.
+func (pr *PullRequest) WithSize(size *PullRequestSize) *PullRequest {
+	p := *pr
+	if size == nil {
+		return &p
+	}
+	if p.Additions == nil {
+		p.Additions = size.Additions
+	}
+	if p.Deletions == nil {
+		p.Deletions = size.Deletions
+	}
+	if p.ChangedFiles == nil {
+		p.ChangedFiles = size.ChangedFiles
+	}
+	if p.Comments == nil {
+		p.Comments = size.Comments
+	}
+	return &p
+}
+
+// PullRequestSize is size of pull request. A value that the source does not provide is nil.
+type PullRequestSize struct {
+	// Additions is number of addition lines
+	Additions *int `json:"additions,omitempty"`
+	// Deletions is number of deletion lines
+	Deletions *int `json:"deletions,omitempty"`
+	// ChangedFiles is number of changed files
+	ChangedFiles *int `json:"changed_files,omitempty"`
+	// Comments is PR comment count
+	Comments *int `json:"comments,omitempty"`
+}
+
 // Commit is git commit information
 type Commit struct {
 	// Author is author user
@@ -87,3 +138,31 @@ type Commit struct {
 	// Date is commit date
 	Date *Timestamp
 }
+
+// Review is pull request review information
+type Review struct {
+	// User is reviewer
+	User *User
+	// State is review state (e.g. APPROVED, CHANGES_REQUESTED, COMMENTED)
+	State *string
+	// SubmittedAt is date of review submission
+	SubmittedAt *Timestamp
+}
+
+// IsApproved check whether review approves the pull request or not.
+func (r *Review) IsApproved() bool {
+	if r.State == nil {
+		return false
+	}
+
+	return *r.State == "APPROVED"
+}
+
+// IsPending check whether review is not submitted yet or not.
+func (r *Review) IsPending() bool {
+	if r.State == nil {
+		return false
+	}
+
+	return *r.State == "PENDING"
+}
@@ -2,18 +2,46 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/nao1215/leadtime/domain/model"
 )
 
+// ListPullRequestsOptions is options for listing pull requests.
+type ListPullRequestsOptions struct {
+	// UpdatedSince limits pull requests to those updated at or after UpdatedSince.
+	// If UpdatedSince is zero, all pull requests are listed.
+	UpdatedSince time.Time
+}
+
 // GitHubRepository is interface for manipulating GitHub.
-type GitHubRepository interface {
-	// ListRepositories return repository list
-	ListRepositories(ctx context.Context) ([]*model.Repository, error)
-	// ListRepositories return pull request list
-	ListPullRequests(ctx context.Context, owner, repo string) ([]*model.PullRequest, error)
+type GitHubRepository = SourceRepository
+
+// SourceRepository is interface for manipulating source code hosting service
+// (e.g. GitHub, GitLab). Merge requests of GitLab are handled as pull requests.
+// If there is no pull request, ListPullRequests return ErrNoPullRequest.
+// If there is no commit in the pull request, GetFirstCommit return ErrNoCommit.
+type SourceRepository interface {
+	// ListRepositories return repository list of the organization (group).
+	// If org is empty, return repository list of the authenticated user.
+	ListRepositories(ctx context.Context, org string) ([]*model.Repository, error)
+	// ListPullRequests return pull request list. If opts is nil, all pull requests are listed.
+	ListPullRequests(ctx context.Context, owner, repo string, opts *ListPullRequestsOptions) ([]*model.PullRequest, error)
 	// ListCommitsInPR return commits in PR.
 	ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error)
 	// GetFirstCommit return first commit in PR.
 	GetFirstCommit(ctx context.Context, owner, repository string, number int) (*model.Commit, error)
+	// ListReviews return reviews in PR.
+	ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error)
+	// GetPullRequestSize return number of added and deleted lines, changed files and comments in PR.
+	// It is used for PRs whose list does not include them.
+	GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error)
+}
+
+// CloseDateResolver is implemented by SourceRepository whose pull request list does not
+// include exact merge and close dates (e.g. Bitbucket Cloud). ListPullRequests of such
+// source sets the update date to them, and ResolveCloseDate is called for each pull request.
+type CloseDateResolver interface {
+	// ResolveCloseDate return the pull request with exact merge and close dates.
+	ResolveCloseDate(ctx context.Context, owner, repo string, pr *model.PullRequest) (*model.PullRequest, error)
 }
@@ -9,4 +9,12 @@ var (
 	ErrEmptyGitHubOwnerName = errors.New("github owner name is empty")
 	// ErrEmptyRepositoryName means "github repository name is empty"
 	ErrEmptyRepositoryName = errors.New("github repository name is empty")
+	// ErrEmptyOrganizationName means "github organization name is empty"
+	ErrEmptyOrganizationName = errors.New("github organization name is empty")
+	// ErrInvalidConcurrency means "concurrency must be 1 or more"
+	ErrInvalidConcurrency = errors.New("concurrency must be 1 or more")
+	// ErrInvalidDateField means "date field must be created, merged or closed"
+	ErrInvalidDateField = errors.New("date field must be created, merged or closed")
+	// ErrInvalidDateRange means "since must be before until"
+	ErrInvalidDateRange = errors.New("since must be before until")
 )
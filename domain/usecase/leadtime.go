@@ -3,17 +3,72 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/nao1215/leadtime/domain/model"
 	"github.com/nao1215/leadtime/domain/repository"
-	"github.com/nao1215/leadtime/infrastructure/github"
 	"github.com/shogo82148/pointer"
 )
 
 // LeadTimeUsecase is use cases for stat leadtime
 type LeadTimeUsecase interface {
 	Stat(ctx context.Context, input *LeadTimeUsecaseStatInput) (*LeadTimeUsecaseStatOutput, error)
+	ListRepositories(ctx context.Context, input *LeadTimeUsecaseListRepositoriesInput) (*LeadTimeUsecaseListRepositoriesOutput, error)
+	Export(ctx context.Context, input *LeadTimeUsecaseExportInput) (*LeadTimeUsecaseExportOutput, error)
+}
+
+// Repository is target repository of statistics.
+type Repository struct {
+	// Owner is GitHub account or organization name
+	Owner string
+	// Name is GitHub repository name
+	Name string
+}
+
+// FullName return repository name in owner/name format.
+func (r *Repository) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// DateField is PR date used for filtering PRs by date range.
+type DateField string
+
+const (
+	// DateFieldCreated means PR creation date
+	DateFieldCreated DateField = "created"
+	// DateFieldMerged means PR merge date. PR closed without merge uses close date instead.
+	DateFieldMerged DateField = "merged"
+	// DateFieldClosed means PR close date
+	DateFieldClosed DateField = "closed"
+)
+
+// Valid check whether date field is supported or not.
+func (d DateField) Valid() bool {
+	switch d {
+	case DateFieldCreated, DateFieldMerged, DateFieldClosed:
+		return true
+	}
+	return false
+}
+
+// date return the date of PR specified by d. If the PR does not have the date, return nil.
+// The merge date of PR closed without merge is its close date, so that abandoned PRs are
+// in the same date range as merged PRs.
+func (d DateField) date(pr *model.PullRequest) *model.Timestamp {
+	switch d {
+	case DateFieldCreated:
+		return pr.CreatedAt
+	case DateFieldMerged:
+		if (pr.MergedAt == nil || pr.MergedAt.Time.IsZero()) && pr.IsClosed() {
+			return pr.ClosedAt
+		}
+		return pr.MergedAt
+	case DateFieldClosed:
+		return pr.ClosedAt
+	}
+	return nil
 }
 
 // LeadTimeUsecaseStatInput is input data for LeadTimeUsecase.Stat().
@@ -22,50 +77,252 @@ type LeadTimeUsecaseStatInput struct {
 	Owner string
 	// Repository is GitHub repository name
 	Repository string
+	// Repositories is target repositories. If Repositories is empty, Owner and Repository are used.
+	Repositories []*Repository
+	// Concurrency is number of workers that fetch PR details at the same time
+	Concurrency int
+	// Since limits PRs to those whose DateField is at or after Since. Zero means no limit.
+	Since time.Time
+	// Until limits PRs to those whose DateField is before Until. Zero means no limit.
+	Until time.Time
+	// DateField is PR date compared with Since and Until. Empty means DateFieldMerged.
+	DateField DateField
+	// FetchSize fetches size of PRs (e.g. added and deleted lines) that the PR list of the
+	// source does not include. It costs one more request for each PR, so it is only for
+	// statistics that need PR size.
+	FetchSize bool
+	// IncludeOpen keeps open PRs regardless of Since and Until, because open PRs
+	// do not have merge or close date (e.g. age of open PRs in serve mode).
+	IncludeOpen bool
 }
 
 // Valid is input data validation
 func (lt *LeadTimeUsecaseStatInput) Valid() error {
-	if lt.Owner == "" {
-		return ErrEmptyGitHubOwnerName
+	if err := validTargets(lt.targets()); err != nil {
+		return err
+	}
+	if lt.Concurrency < 1 {
+		return ErrInvalidConcurrency
 	}
-	if lt.Repository == "" {
-		return ErrEmptyRepositoryName
+	if lt.DateField != "" && !lt.DateField.Valid() {
+		return ErrInvalidDateField
+	}
+	if !lt.Since.IsZero() && !lt.Until.IsZero() && !lt.Since.Before(lt.Until) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// targets return target repositories.
+func (lt *LeadTimeUsecaseStatInput) targets() []*Repository {
+	return targets(lt.Owner, lt.Repository, lt.Repositories)
+}
+
+// targets return repos. If repos is empty, return the repository specified by owner and name.
+func targets(owner, name string, repos []*Repository) []*Repository {
+	if len(repos) != 0 {
+		return repos
+	}
+	return []*Repository{{Owner: owner, Name: name}}
+}
+
+// validTargets check whether all target repositories have owner and name.
+func validTargets(repos []*Repository) error {
+	for _, v := range repos {
+		if v.Owner == "" {
+			return ErrEmptyGitHubOwnerName
+		}
+		if v.Name == "" {
+			return ErrEmptyRepositoryName
+		}
 	}
 	return nil
 }
 
+// hasDateRange check whether PRs are filtered by date range or not.
+func (lt *LeadTimeUsecaseStatInput) hasDateRange() bool {
+	return !lt.Since.IsZero() || !lt.Until.IsZero()
+}
+
+// listOptions return options to list PRs of the target repository.
+func (lt *LeadTimeUsecaseStatInput) listOptions() *repository.ListPullRequestsOptions {
+	// PR is always updated at or after its creation, merge and close date, so PRs
+	// not updated since Since are out of the date range. But open PRs are kept
+	// with IncludeOpen even if they are not updated for a long time.
+	if lt.IncludeOpen {
+		return &repository.ListPullRequestsOptions{}
+	}
+	return &repository.ListPullRequestsOptions{UpdatedSince: lt.Since}
+}
+
+// inDateRange check whether the PR date is in the date range or not.
+// If the PR does not have the date (e.g. PR is not merged), return false.
+// Open PRs are always in the date range with IncludeOpen.
+func (lt *LeadTimeUsecaseStatInput) inDateRange(pr *model.PullRequest) bool {
+	if lt.IncludeOpen && !pr.IsClosed() {
+		return true
+	}
+
+	field := lt.DateField
+	if field == "" {
+		field = DateFieldMerged
+	}
+
+	date := field.date(pr)
+	if date == nil || date.Time.IsZero() {
+		return false
+	}
+	if !lt.Since.IsZero() && date.Time.Before(lt.Since) {
+		return false
+	}
+	if !lt.Until.IsZero() && !date.Time.Before(lt.Until) {
+		return false
+	}
+	return true
+}
+
 // LeadTimeUsecaseStatOutput is output data for LeadTimeUsecase.Stat().
 type LeadTimeUsecaseStatOutput struct {
 	LeadTime *LeadTime
 }
 
+// LeadTimeUsecaseListRepositoriesInput is input data for LeadTimeUsecase.ListRepositories().
+type LeadTimeUsecaseListRepositoriesInput struct {
+	// Organization is GitHub organization name
+	Organization string
+}
+
+// Valid is input data validation
+func (lt *LeadTimeUsecaseListRepositoriesInput) Valid() error {
+	if lt.Organization == "" {
+		return ErrEmptyOrganizationName
+	}
+	return nil
+}
+
+// LeadTimeUsecaseListRepositoriesOutput is output data for LeadTimeUsecase.ListRepositories().
+type LeadTimeUsecaseListRepositoriesOutput struct {
+	Repositories []*Repository
+}
+
+// LeadTimeUsecaseExportInput is input data for LeadTimeUsecase.Export().
+type LeadTimeUsecaseExportInput struct {
+	// Owner is GitHub account name
+	Owner string
+	// Repository is GitHub repository name
+	Repository string
+	// Repositories is target repositories. If Repositories is empty, Owner and Repository are used.
+	Repositories []*Repository
+	// Concurrency is number of workers that fetch PR details at the same time
+	Concurrency int
+}
+
+// Valid is input data validation
+func (lt *LeadTimeUsecaseExportInput) Valid() error {
+	if err := validTargets(lt.targets()); err != nil {
+		return err
+	}
+	if lt.Concurrency < 1 {
+		return ErrInvalidConcurrency
+	}
+	return nil
+}
+
+// targets return target repositories.
+func (lt *LeadTimeUsecaseExportInput) targets() []*Repository {
+	return targets(lt.Owner, lt.Repository, lt.Repositories)
+}
+
+// LeadTimeUsecaseExportOutput is output data for LeadTimeUsecase.Export().
+type LeadTimeUsecaseExportOutput struct {
+	Repositories []*RawRepository
+}
+
+// RawRepository is data fetched from the source for one repository.
+type RawRepository struct {
+	// Owner is GitHub account or organization name
+	Owner string
+	// Name is GitHub repository name
+	Name string
+	// PullRequests is PRs in the order returned by the source.
+	PullRequests []*RawPullRequest
+}
+
+// RawPullRequest is PR and its details fetched from the source.
+type RawPullRequest struct {
+	PullRequest *model.PullRequest
+	// FirstCommit is nil if the PR has no commit.
+	FirstCommit *model.Commit
+	// Reviews is nil if the PR has no commit, because reviews are not used for such PR.
+	Reviews []*model.Review
+}
+
 // LTUsecase implement LeadTimeUsecase
 type LTUsecase struct {
-	gitHubRepo repository.GitHubRepository
+	sourceRepo repository.SourceRepository
 }
 
 // NewLeadTimeUsecase initialize LTUsecase
-func NewLeadTimeUsecase(gitHubRepo repository.GitHubRepository) LeadTimeUsecase {
+func NewLeadTimeUsecase(sourceRepo repository.SourceRepository) LeadTimeUsecase {
 	return &LTUsecase{
-		gitHubRepo: gitHubRepo,
+		sourceRepo: sourceRepo,
 	}
 }
 
+// PRStatus is outcome of PR.
+type PRStatus string
+
+const (
+	// PRStatusMerged means PR is merged.
+	PRStatusMerged PRStatus = "merged"
+	// PRStatusClosedUnmerged means PR is closed without merge (abandoned).
+	PRStatusClosedUnmerged PRStatus = "closed-unmerged"
+	// PRStatusOpen means PR is not closed yet.
+	PRStatusOpen PRStatus = "open"
+)
+
 // PullRequest is PR information for presentation layer.
 type PullRequest struct {
+	// Repository is repository name in owner/name format.
+	Repository       string      `json:"repository,omitempty"`
 	Number           int         `json:"number,omitempty"`
 	State            string      `json:"state,omitempty"`
 	Title            string      `json:"title,omitempty"`
 	FirstCommitAt    time.Time   `json:"first_commit_at,omitempty"`
 	CreatedAt        time.Time   `json:"created_at,omitempty"`
+	FirstReviewAt    time.Time   `json:"first_review_at,omitempty"`
+	ApprovedAt       time.Time   `json:"approved_at,omitempty"`
 	ClosedAt         time.Time   `json:"closed_at,omitempty"`
 	MergedAt         time.Time   `json:"merged_at,omitempty"`
 	User             *model.User `json:"user,omitempty"`
 	MergeTimeMinutes int         `json:"merge_time_minutes,omitempty"`
+	// Status is whether PR is merged, closed without merge or open.
+	Status PRStatus `json:"status,omitempty"`
+	// TimeToMergeMinutes is time from PR creation to merge. nil means the PR is not merged.
+	TimeToMergeMinutes *int `json:"time_to_merge_minutes,omitempty"`
+	// TimeToAbandonMinutes is time from PR creation to close without merge. nil means the PR is not abandoned.
+	TimeToAbandonMinutes *int `json:"time_to_abandon_minutes,omitempty"`
+	// Additions is number of added lines. nil means the source does not provide it.
+	Additions *int `json:"additions,omitempty"`
+	// Deletions is number of deleted lines. nil means the source does not provide it.
+	Deletions *int `json:"deletions,omitempty"`
+	// ChangedFiles is number of changed files. nil means the source does not provide it.
+	ChangedFiles *int `json:"changed_files,omitempty"`
+	// Comments is number of comments. nil means the source does not provide it.
+	Comments *int `json:"comments,omitempty"`
+	// Labels is names of PR labels.
+	Labels []string `json:"labels,omitempty"`
+	// CodingTimeMinutes is time from first commit to PR creation.
+	CodingTimeMinutes *int `json:"coding_time_minutes,omitempty"`
+	// PickupTimeMinutes is time from PR creation to first review.
+	PickupTimeMinutes *int `json:"pickup_time_minutes,omitempty"`
+	// ReviewTimeMinutes is time from first review to approval.
+	ReviewTimeMinutes *int `json:"review_time_minutes,omitempty"`
+	// MergeStageTimeMinutes is time from approval to merge.
+	MergeStageTimeMinutes *int `json:"merge_stage_time_minutes,omitempty"`
 }
 
-func (p *PullRequest) toUsecasePullRequest(domainModelPR *model.PullRequest, firstCommitAt time.Time) *PullRequest {
+func (p *PullRequest) toUsecasePullRequest(domainModelPR *model.PullRequest, firstCommitAt time.Time, reviews []*model.Review) *PullRequest {
 	p.Number = pointer.IntValue(domainModelPR.Number)
 	p.Title = pointer.StringValue(domainModelPR.Title)
 	p.State = pointer.StringValue(domainModelPR.State)
@@ -83,6 +340,13 @@ func (p *PullRequest) toUsecasePullRequest(domainModelPR *model.PullRequest, fir
 	if domainModelPR.User != nil {
 		p.User = domainModelPR.User
 	}
+	p.Additions = domainModelPR.Additions
+	p.Deletions = domainModelPR.Deletions
+	p.ChangedFiles = domainModelPR.ChangedFiles
+	p.Comments = domainModelPR.Comments
+	p.Labels = domainModelPR.Labels
+	p.FirstReviewAt, p.ApprovedAt = reviewTimes(p.User, reviews)
+	p.Status = prStatus(p)
 
 	if p.MergedAt != (time.Time{}) {
 		p.MergeTimeMinutes = MinuteDiff(p.MergedAt, p.FirstCommitAt)
@@ -92,45 +356,340 @@ func (p *PullRequest) toUsecasePullRequest(domainModelPR *model.PullRequest, fir
 		p.MergeTimeMinutes = MinuteDiff(time.Now(), p.FirstCommitAt)
 	}
 
+	p.TimeToMergeMinutes = stageMinutes(p.MergedAt, p.CreatedAt)
+	if p.Status == PRStatusClosedUnmerged {
+		p.TimeToAbandonMinutes = stageMinutes(p.ClosedAt, p.CreatedAt)
+	}
+	p.CodingTimeMinutes = stageMinutes(p.CreatedAt, p.FirstCommitAt)
+	p.PickupTimeMinutes = stageMinutes(p.FirstReviewAt, p.CreatedAt)
+	p.ReviewTimeMinutes = stageMinutes(p.ApprovedAt, p.FirstReviewAt)
+	p.MergeStageTimeMinutes = stageMinutes(p.MergedAt, p.ApprovedAt)
+
 	return p
 }
 
+// prStatus classify PR by its outcome. A PR that has merge date is merged even if
+// the source reports other state.
+func prStatus(p *PullRequest) PRStatus {
+	if !p.MergedAt.IsZero() {
+		return PRStatusMerged
+	}
+	if p.State == "open" {
+		return PRStatusOpen
+	}
+	return PRStatusClosedUnmerged
+}
+
+// reviewTimes return the first submitted review date and the first approval date.
+// Reviews by the PR author and pending reviews are ignored.
+func reviewTimes(author *model.User, reviews []*model.Review) (firstReviewAt, approvedAt time.Time) {
+	for _, v := range reviews {
+		if v.SubmittedAt == nil || v.IsPending() {
+			continue
+		}
+		if author != nil && v.User != nil && pointer.StringValue(author.Name) == pointer.StringValue(v.User.Name) {
+			continue
+		}
+
+		if firstReviewAt.IsZero() || v.SubmittedAt.Time.Before(firstReviewAt) {
+			firstReviewAt = v.SubmittedAt.Time
+		}
+		if v.IsApproved() && (approvedAt.IsZero() || v.SubmittedAt.Time.Before(approvedAt)) {
+			approvedAt = v.SubmittedAt.Time
+		}
+	}
+	return firstReviewAt, approvedAt
+}
+
+// stageMinutes return minutes between two events of the PR life cycle.
+// If either event did not happen, return nil. A negative span (e.g. commits
+// rebased after the PR was opened) is treated as zero.
+func stageMinutes(after, before time.Time) *int {
+	if after.IsZero() || before.IsZero() {
+		return nil
+	}
+
+	diff := MinuteDiff(after, before)
+	if diff < 0 {
+		diff = 0
+	}
+	return &diff
+}
+
 type LeadTime struct {
 	PullRequests []*PullRequest `json:"pull_requests,omitempty"`
 }
 
-// Stat return lead time statistics
+// Stat return lead time statistics. PRs of target repositories are returned
+// in the order of input.Repositories.
 func (lt *LTUsecase) Stat(ctx context.Context, input *LeadTimeUsecaseStatInput) (*LeadTimeUsecaseStatOutput, error) {
-	prs, err := lt.gitHubRepo.ListPullRequests(ctx, input.Owner, input.Repository)
+	pullReqs := make([]*PullRequest, 0)
+	for _, target := range input.targets() {
+		prs, err := lt.sourceRepo.ListPullRequests(ctx, target.Owner, target.Name, input.listOptions())
+		if err != nil {
+			if len(input.Repositories) > 1 && errors.Is(err, repository.ErrNoPullRequest) {
+				continue
+			}
+			return nil, err
+		}
+
+		if prs, err = lt.withCloseDates(ctx, input.Concurrency, target, prs); err != nil {
+			return nil, err
+		}
+		if input.hasDateRange() {
+			prs = filterPullRequests(prs, input.inDateRange)
+		}
+
+		result, err := lt.pullRequests(ctx, input, target, prs)
+		if err != nil {
+			return nil, err
+		}
+		pullReqs = append(pullReqs, result...)
+	}
+
+	return &LeadTimeUsecaseStatOutput{
+		LeadTime: &LeadTime{
+			PullRequests: pullReqs,
+		},
+	}, nil
+}
+
+// ListRepositories return repositories of the organization.
+func (lt *LTUsecase) ListRepositories(ctx context.Context, input *LeadTimeUsecaseListRepositoriesInput) (*LeadTimeUsecaseListRepositoriesOutput, error) {
+	repos, err := lt.sourceRepo.ListRepositories(ctx, input.Organization)
 	if err != nil {
 		return nil, err
 	}
 
-	pullReqs := make([]*PullRequest, 0)
-	for _, v := range prs {
-		if v.Number == nil {
+	targets := make([]*Repository, 0, len(repos))
+	for _, v := range repos {
+		name := pointer.StringValue(v.Name)
+		if name == "" {
 			continue
 		}
+		owner := input.Organization
+		if fullName := pointer.StringValue(v.FullName); strings.HasSuffix(fullName, "/"+name) {
+			owner = strings.TrimSuffix(fullName, "/"+name)
+		}
+		targets = append(targets, &Repository{Owner: owner, Name: name})
+	}
+
+	return &LeadTimeUsecaseListRepositoriesOutput{
+		Repositories: targets,
+	}, nil
+}
+
+// filterPullRequests return PRs that satisfy match.
+func filterPullRequests(prs []*model.PullRequest, match func(pr *model.PullRequest) bool) []*model.PullRequest {
+	filtered := make([]*model.PullRequest, 0, len(prs))
+	for _, v := range prs {
+		if match(v) {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
 
-		commit, err := lt.gitHubRepo.GetFirstCommit(ctx, input.Owner, input.Repository, *v.Number)
+// pullRequests fetch commits and reviews of each PR with input.Concurrency workers.
+// The order of returned PRs is the same as prs. PRs without commits are skipped.
+func (lt *LTUsecase) pullRequests(ctx context.Context, input *LeadTimeUsecaseStatInput, target *Repository, prs []*model.PullRequest) ([]*PullRequest, error) {
+	results := make([]*PullRequest, len(prs))
+	err := forEachConcurrently(ctx, len(prs), input.Concurrency, func(ctx context.Context, index int) error {
+		pr, err := lt.pullRequest(ctx, input, target, prs[index])
 		if err != nil {
-			if errors.Is(err, github.ErrNoCommit) {
-				continue
+			return err
+		}
+		results[index] = pr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pullReqs := make([]*PullRequest, 0, len(results))
+	for _, v := range results {
+		if v != nil {
+			pullReqs = append(pullReqs, v)
+		}
+	}
+	return pullReqs, nil
+}
+
+// forEachConcurrently call fn for each index in [0, n) with concurrency workers.
+// If fn fails, the remaining workers are cancelled and the first error is returned.
+func forEachConcurrently(ctx context.Context, n, concurrency int, fn func(ctx context.Context, index int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := fn(ctx, index); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
 			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// pullRequest fetch commits and reviews of the PR in target repository. The size of the PR
+// is fetched only with input.FetchSize. If the PR has no number or commit, return nil without error.
+func (lt *LTUsecase) pullRequest(ctx context.Context, input *LeadTimeUsecaseStatInput, target *Repository, pr *model.PullRequest) (*PullRequest, error) {
+	if pr.Number == nil {
+		return nil, nil //nolint
+	}
+
+	commit, err := lt.sourceRepo.GetFirstCommit(ctx, target.Owner, target.Name, *pr.Number)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoCommit) {
+			return nil, nil //nolint
+		}
+		return nil, err
+	}
+
+	if input.FetchSize {
+		if pr, err = lt.withSize(ctx, target, pr); err != nil {
 			return nil, err
 		}
+	}
 
-		pr := &PullRequest{}
-		pullReqs = append(pullReqs, pr.toUsecasePullRequest(v, commit.Date.Time))
+	reviews, err := lt.sourceRepo.ListReviews(ctx, target.Owner, target.Name, *pr.Number)
+	if err != nil {
+		return nil, err
 	}
 
-	return &LeadTimeUsecaseStatOutput{
-		LeadTime: &LeadTime{
-			PullRequests: pullReqs,
-		},
+	p := &PullRequest{Repository: target.FullName()}
+	return p.toUsecasePullRequest(pr, commit.Date.Time, reviews), nil
+}
+
+// Export return PRs of target repositories with their first commits and reviews as
+// they are fetched from the source, so that statistics can be computed later without
+// the source. Repositories without PR are returned with empty PR list.
+func (lt *LTUsecase) Export(ctx context.Context, input *LeadTimeUsecaseExportInput) (*LeadTimeUsecaseExportOutput, error) {
+	repos := make([]*RawRepository, 0, len(input.targets()))
+	for _, target := range input.targets() {
+		prs, err := lt.sourceRepo.ListPullRequests(ctx, target.Owner, target.Name, nil)
+		if err != nil && !errors.Is(err, repository.ErrNoPullRequest) {
+			return nil, err
+		}
+		if prs, err = lt.withCloseDates(ctx, input.Concurrency, target, prs); err != nil {
+			return nil, err
+		}
+
+		raws := make([]*RawPullRequest, len(prs))
+		err = forEachConcurrently(ctx, len(prs), input.Concurrency, func(ctx context.Context, index int) error {
+			raw, err := lt.rawPullRequest(ctx, target, prs[index])
+			if err != nil {
+				return err
+			}
+			raws[index] = raw
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, &RawRepository{Owner: target.Owner, Name: target.Name, PullRequests: raws})
+	}
+
+	return &LeadTimeUsecaseExportOutput{
+		Repositories: repos,
 	}, nil
 }
 
+// rawPullRequest fetch the first commit and reviews of the PR in target repository.
+// Reviews are fetched only if the PR has commits, as Stat does.
+func (lt *LTUsecase) rawPullRequest(ctx context.Context, target *Repository, pr *model.PullRequest) (*RawPullRequest, error) {
+	raw := &RawPullRequest{PullRequest: pr}
+	if pr.Number == nil {
+		return raw, nil
+	}
+
+	commit, err := lt.sourceRepo.GetFirstCommit(ctx, target.Owner, target.Name, *pr.Number)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoCommit) {
+			return raw, nil
+		}
+		return nil, err
+	}
+	raw.FirstCommit = commit
+
+	if raw.PullRequest, err = lt.withSize(ctx, target, pr); err != nil {
+		return nil, err
+	}
+
+	if raw.Reviews, err = lt.sourceRepo.ListReviews(ctx, target.Owner, target.Name, *pr.Number); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// withCloseDates return PRs with exact merge and close dates. If the source lists PRs
+// without them (e.g. Bitbucket Cloud), they are resolved with concurrency workers.
+func (lt *LTUsecase) withCloseDates(ctx context.Context, concurrency int, target *Repository, prs []*model.PullRequest) ([]*model.PullRequest, error) {
+	resolver, ok := lt.sourceRepo.(repository.CloseDateResolver)
+	if !ok {
+		return prs, nil
+	}
+
+	resolved := make([]*model.PullRequest, len(prs))
+	err := forEachConcurrently(ctx, len(prs), concurrency, func(ctx context.Context, index int) error {
+		pr, err := resolver.ResolveCloseDate(ctx, target.Owner, target.Name, prs[index])
+		if err != nil {
+			return err
+		}
+		resolved[index] = pr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// withSize return the PR with number of added and deleted lines, changed files and comments.
+// If the PR list of the source does not include them, they are fetched.
+func (lt *LTUsecase) withSize(ctx context.Context, target *Repository, pr *model.PullRequest) (*model.PullRequest, error) {
+	if pr.HasSize() {
+		return pr, nil
+	}
+	size, err := lt.sourceRepo.GetPullRequestSize(ctx, target.Owner, target.Name, *pr.Number)
+	if err != nil {
+		return nil, err
+	}
+	return pr.WithSize(size), nil
+}
+
 func MinuteDiff(after, before time.Time) int {
 	diff := after.Sub(before)
 	return int(diff.Minutes())
@@ -0,0 +1,751 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"github.com/shogo82148/pointer"
+)
+
+// fakeGitHubRepository is repository.GitHubRepository for unit test.
+type fakeGitHubRepository struct {
+	prs          []*model.PullRequest
+	firstCommits map[int]*model.Commit
+	reviews      map[int][]*model.Review
+	// commitErr is returned by GetFirstCommit for the PR number.
+	commitErr map[int]error
+	// calls is number of GetFirstCommit calls.
+	calls int32
+	// listOpts is options passed to ListPullRequests.
+	listOpts *repository.ListPullRequestsOptions
+	repos    []*model.Repository
+	// emptyRepos is repositories without PR.
+	emptyRepos []string
+	// sizes is returned by GetPullRequestSize for the PR number.
+	sizes map[int]*model.PullRequestSize
+	// sizeCalls is number of GetPullRequestSize calls.
+	sizeCalls int32
+}
+
+func (f *fakeGitHubRepository) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	return f.repos, nil
+}
+
+func (f *fakeGitHubRepository) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	f.listOpts = opts
+	for _, v := range f.emptyRepos {
+		if v == owner+"/"+repo {
+			return nil, repository.ErrNoPullRequest
+		}
+	}
+	return f.prs, nil
+}
+
+func (f *fakeGitHubRepository) ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error) {
+	commit, err := f.GetFirstCommit(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return []*model.Commit{commit}, nil
+}
+
+func (f *fakeGitHubRepository) GetFirstCommit(ctx context.Context, owner, repository string, number int) (*model.Commit, error) {
+	atomic.AddInt32(&f.calls, 1)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err, ok := f.commitErr[number]; ok {
+		return nil, err
+	}
+	return f.firstCommits[number], nil
+}
+
+func (f *fakeGitHubRepository) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	return f.reviews[number], nil
+}
+
+func (f *fakeGitHubRepository) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	atomic.AddInt32(&f.sizeCalls, 1)
+	if size, ok := f.sizes[number]; ok {
+		return size, nil
+	}
+	return &model.PullRequestSize{}, nil
+}
+
+func newFakeGitHubRepository(n int, start time.Time) *fakeGitHubRepository {
+	f := &fakeGitHubRepository{
+		firstCommits: map[int]*model.Commit{},
+		reviews:      map[int][]*model.Review{},
+		commitErr:    map[int]error{},
+	}
+	for i := 1; i <= n; i++ {
+		f.prs = append(f.prs, &model.PullRequest{
+			Number:    pointer.Int(i),
+			State:     pointer.String("closed"),
+			CreatedAt: &model.Timestamp{Time: start.Add(time.Hour)},
+			ClosedAt:  &model.Timestamp{Time: start.Add(time.Duration(i) * 2 * time.Hour)},
+			MergedAt:  &model.Timestamp{Time: start.Add(time.Duration(i) * 2 * time.Hour)},
+		})
+		f.firstCommits[i] = &model.Commit{Date: &model.Timestamp{Time: start}}
+	}
+	return f
+}
+
+func TestLTUsecase_Stat(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 2, 24, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Keep PR order with multiple workers and skip PRs without commit", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeGitHubRepository(50, start)
+		repo.commitErr[7] = repository.ErrNoCommit
+		lt := NewLeadTimeUsecase(repo)
+
+		got, err := lt.Stat(context.Background(), &LeadTimeUsecaseStatInput{
+			Owner:       "owner",
+			Repository:  "repo",
+			Concurrency: 8,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := make([]int, 0, 49)
+		for i := 1; i <= 50; i++ {
+			if i != 7 {
+				want = append(want, i)
+			}
+		}
+		gotNumbers := make([]int, 0, len(got.LeadTime.PullRequests))
+		for _, v := range got.LeadTime.PullRequests {
+			gotNumbers = append(gotNumbers, v.Number)
+		}
+		if diff := cmp.Diff(want, gotNumbers); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Stop at the first fatal error", func(t *testing.T) {
+		t.Parallel()
+
+		wantErr := errors.New("fatal error")
+		repo := newFakeGitHubRepository(1000, start)
+		repo.commitErr[1] = wantErr
+		lt := NewLeadTimeUsecase(repo)
+
+		_, err := lt.Stat(context.Background(), &LeadTimeUsecaseStatInput{
+			Owner:       "owner",
+			Repository:  "repo",
+			Concurrency: 1,
+		})
+		if !errors.Is(err, wantErr) {
+			t.Errorf("mismatch want=%v, got=%v", wantErr, err)
+		}
+		if calls := atomic.LoadInt32(&repo.calls); calls != 1 {
+			t.Errorf("workers were not cancelled: %d calls", calls)
+		}
+	})
+
+	t.Run("Fetch size of PR only if PR list does not include it", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeGitHubRepository(2, start)
+		repo.prs[0].Additions, repo.prs[0].Deletions, repo.prs[0].ChangedFiles = pointer.Int(1), pointer.Int(2), pointer.Int(3)
+		repo.sizes = map[int]*model.PullRequestSize{
+			2: {Additions: pointer.Int(10), Deletions: pointer.Int(20), ChangedFiles: pointer.Int(30), Comments: pointer.Int(4)},
+		}
+		lt := NewLeadTimeUsecase(repo)
+
+		got, err := lt.Stat(context.Background(), &LeadTimeUsecaseStatInput{
+			Owner:       "owner",
+			Repository:  "repo",
+			Concurrency: 1,
+			FetchSize:   true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		type size struct{ Additions, Deletions, ChangedFiles, Comments *int }
+		want := []size{
+			{Additions: pointer.Int(1), Deletions: pointer.Int(2), ChangedFiles: pointer.Int(3)},
+			{Additions: pointer.Int(10), Deletions: pointer.Int(20), ChangedFiles: pointer.Int(30), Comments: pointer.Int(4)},
+		}
+		gotSizes := make([]size, 0, len(got.LeadTime.PullRequests))
+		for _, v := range got.LeadTime.PullRequests {
+			gotSizes = append(gotSizes, size{Additions: v.Additions, Deletions: v.Deletions, ChangedFiles: v.ChangedFiles, Comments: v.Comments})
+		}
+		if diff := cmp.Diff(want, gotSizes); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+		if calls := atomic.LoadInt32(&repo.sizeCalls); calls != 1 {
+			t.Errorf("mismatch want=1, got=%d", calls)
+		}
+	})
+
+	t.Run("Do not fetch size of PR without FetchSize", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeGitHubRepository(2, start)
+		repo.prs[0].Additions, repo.prs[0].Deletions = pointer.Int(1), pointer.Int(2)
+		lt := NewLeadTimeUsecase(repo)
+
+		got, err := lt.Stat(context.Background(), &LeadTimeUsecaseStatInput{
+			Owner:       "owner",
+			Repository:  "repo",
+			Concurrency: 1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.LeadTime.PullRequests[0].Additions == nil || got.LeadTime.PullRequests[1].Additions != nil {
+			t.Errorf("size is not the one of PR list: %v, %v", got.LeadTime.PullRequests[0].Additions, got.LeadTime.PullRequests[1].Additions)
+		}
+		if calls := atomic.LoadInt32(&repo.sizeCalls); calls != 0 {
+			t.Errorf("mismatch want=0, got=%d", calls)
+		}
+	})
+}
+
+func TestLTUsecase_StatRepositories(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 2, 24, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Return PRs of each repository and skip repositories without PR", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeGitHubRepository(2, start)
+		repo.emptyRepos = []string{"org/empty"}
+		lt := NewLeadTimeUsecase(repo)
+
+		got, err := lt.Stat(context.Background(), &LeadTimeUsecaseStatInput{
+			Repositories: []*Repository{
+				{Owner: "org", Name: "repo1"},
+				{Owner: "org", Name: "empty"},
+				{Owner: "alice", Name: "repo2"},
+			},
+			Concurrency: 2,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"org/repo1#1", "org/repo1#2", "alice/repo2#1", "alice/repo2#2"}
+		gotPRs := make([]string, 0, len(got.LeadTime.PullRequests))
+		for _, v := range got.LeadTime.PullRequests {
+			gotPRs = append(gotPRs, fmt.Sprintf("%s#%d", v.Repository, v.Number))
+		}
+		if diff := cmp.Diff(want, gotPRs); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Single repository without PR is error", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeGitHubRepository(2, start)
+		repo.emptyRepos = []string{"org/empty"}
+		lt := NewLeadTimeUsecase(repo)
+
+		_, err := lt.Stat(context.Background(), &LeadTimeUsecaseStatInput{
+			Owner:       "org",
+			Repository:  "empty",
+			Concurrency: 1,
+		})
+		if !errors.Is(err, repository.ErrNoPullRequest) {
+			t.Errorf("mismatch want=%v, got=%v", repository.ErrNoPullRequest, err)
+		}
+	})
+}
+
+func TestLTUsecase_Export(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 2, 24, 12, 0, 0, 0, time.UTC)
+	repo := newFakeGitHubRepository(3, start)
+	repo.emptyRepos = []string{"org/empty"}
+	repo.commitErr[2] = repository.ErrNoCommit
+	repo.reviews[1] = []*model.Review{{State: pointer.String("APPROVED"), SubmittedAt: &model.Timestamp{Time: start}}}
+	lt := NewLeadTimeUsecase(repo)
+
+	got, err := lt.Export(context.Background(), &LeadTimeUsecaseExportInput{
+		Repositories: []*Repository{
+			{Owner: "org", Name: "repo"},
+			{Owner: "org", Name: "empty"},
+		},
+		Concurrency: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &LeadTimeUsecaseExportOutput{
+		Repositories: []*RawRepository{
+			{
+				Owner: "org",
+				Name:  "repo",
+				PullRequests: []*RawPullRequest{
+					{PullRequest: repo.prs[0], FirstCommit: repo.firstCommits[1], Reviews: repo.reviews[1]},
+					{PullRequest: repo.prs[1]},
+					{PullRequest: repo.prs[2], FirstCommit: repo.firstCommits[3]},
+				},
+			},
+			{Owner: "org", Name: "empty", PullRequests: []*RawPullRequest{}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if repo.listOpts != nil {
+		t.Errorf("PRs must not be filtered: %+v", repo.listOpts)
+	}
+}
+
+func TestLTUsecase_ListRepositories(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeGitHubRepository(0, time.Now())
+	repo.repos = []*model.Repository{
+		{Name: pointer.String("repo1"), FullName: pointer.String("org/repo1")},
+		{Name: pointer.String("repo2")},
+		{FullName: pointer.String("org/no-name")},
+	}
+	lt := NewLeadTimeUsecase(repo)
+
+	got, err := lt.ListRepositories(context.Background(), &LeadTimeUsecaseListRepositoriesInput{Organization: "org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*Repository{{Owner: "org", Name: "repo1"}, {Owner: "org", Name: "repo2"}}
+	if diff := cmp.Diff(want, got.Repositories); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLTUsecase_StatDateRange(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 2, 24, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		since     time.Time
+		until     time.Time
+		dateField DateField
+		// modify modify PRs of the source before Stat.
+		modify func(prs []*model.PullRequest)
+		want   []int
+	}{
+		{
+			name:  "Filter by merge date",
+			since: start.Add(4 * time.Hour),
+			until: start.Add(8 * time.Hour),
+			want:  []int{2, 3},
+		},
+		{
+			name:      "Filter by creation date",
+			since:     start.Add(2 * time.Hour),
+			dateField: DateFieldCreated,
+			want:      []int{},
+		},
+		{
+			name:  "Until only",
+			until: start.Add(4 * time.Hour),
+			want:  []int{1},
+		},
+		{
+			name:   "Filter PR closed without merge by close date",
+			since:  start.Add(4 * time.Hour),
+			until:  start.Add(8 * time.Hour),
+			modify: func(prs []*model.PullRequest) { prs[2].MergedAt = nil },
+			want:   []int{2, 3},
+		},
+		{
+			name:  "Exclude open PR without merge date",
+			since: start.Add(4 * time.Hour),
+			until: start.Add(8 * time.Hour),
+			modify: func(prs []*model.PullRequest) {
+				prs[2].State, prs[2].ClosedAt, prs[2].MergedAt = pointer.String("open"), nil, nil
+			},
+			want: []int{2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeGitHubRepository(5, start)
+			if tt.modify != nil {
+				tt.modify(repo.prs)
+			}
+			lt := NewLeadTimeUsecase(repo)
+
+			got, err := lt.Stat(context.Background(), &LeadTimeUsecaseStatInput{
+				Owner:       "owner",
+				Repository:  "repo",
+				Concurrency: 2,
+				Since:       tt.since,
+				Until:       tt.until,
+				DateField:   tt.dateField,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gotNumbers := make([]int, 0, len(got.LeadTime.PullRequests))
+			for _, v := range got.LeadTime.PullRequests {
+				gotNumbers = append(gotNumbers, v.Number)
+			}
+			if diff := cmp.Diff(tt.want, gotNumbers); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+			if !repo.listOpts.UpdatedSince.Equal(tt.since) {
+				t.Errorf("since is not pushed down: want=%v, got=%v", tt.since, repo.listOpts.UpdatedSince)
+			}
+			if want := int32(len(tt.want)); atomic.LoadInt32(&repo.calls) != want {
+				t.Errorf("commits of filtered PRs are fetched: want=%d, got=%d", want, repo.calls)
+			}
+		})
+	}
+}
+
+func TestLTUsecase_StatIncludeOpen(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 2, 24, 12, 0, 0, 0, time.UTC)
+	repo := newFakeGitHubRepository(5, start)
+	repo.prs[3].State, repo.prs[3].ClosedAt, repo.prs[3].MergedAt = pointer.String("open"), nil, nil
+	lt := NewLeadTimeUsecase(repo)
+
+	got, err := lt.Stat(context.Background(), &LeadTimeUsecaseStatInput{
+		Owner:       "owner",
+		Repository:  "repo",
+		Concurrency: 2,
+		Since:       start.Add(4 * time.Hour),
+		Until:       start.Add(8 * time.Hour),
+		IncludeOpen: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotNumbers := make([]int, 0, len(got.LeadTime.PullRequests))
+	for _, v := range got.LeadTime.PullRequests {
+		gotNumbers = append(gotNumbers, v.Number)
+	}
+	if diff := cmp.Diff([]int{2, 3, 4}, gotNumbers); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if !repo.listOpts.UpdatedSince.IsZero() {
+		t.Errorf("open PRs not updated since the date are not listed: since=%v", repo.listOpts.UpdatedSince)
+	}
+}
+
+// fakeResolverRepository is fakeGitHubRepository whose PR list has update date as merge
+// and close dates, like Bitbucket Cloud.
+type fakeResolverRepository struct {
+	*fakeGitHubRepository
+	// resolved is PRs with exact dates for the PR number.
+	resolved map[int]*model.PullRequest
+}
+
+func (f *fakeResolverRepository) ResolveCloseDate(ctx context.Context, owner, repo string, pr *model.PullRequest) (*model.PullRequest, error) {
+	return f.resolved[*pr.Number], nil
+}
+
+func TestLTUsecase_StatResolveCloseDate(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 2, 24, 12, 0, 0, 0, time.UTC)
+	exact := newFakeGitHubRepository(5, start)
+	repo := &fakeResolverRepository{fakeGitHubRepository: newFakeGitHubRepository(5, start), resolved: map[int]*model.PullRequest{}}
+	for i, v := range repo.prs {
+		updatedAt := &model.Timestamp{Time: start.Add(24 * time.Hour)}
+		v.ClosedAt, v.MergedAt = updatedAt, updatedAt
+		repo.resolved[*v.Number] = exact.prs[i]
+	}
+	lt := NewLeadTimeUsecase(repo)
+
+	got, err := lt.Stat(context.Background(), &LeadTimeUsecaseStatInput{
+		Owner:       "owner",
+		Repository:  "repo",
+		Concurrency: 2,
+		Until:       start.Add(4 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotNumbers := make([]int, 0, len(got.LeadTime.PullRequests))
+	for _, v := range got.LeadTime.PullRequests {
+		gotNumbers = append(gotNumbers, v.Number)
+	}
+	if diff := cmp.Diff([]int{1}, gotNumbers); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if want := start.Add(2 * time.Hour); !got.LeadTime.PullRequests[0].MergedAt.Equal(want) {
+		t.Errorf("mismatch want=%v, got=%v", want, got.LeadTime.PullRequests[0].MergedAt)
+	}
+}
+
+func TestLeadTimeUsecaseStatInput_Valid(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2023, 2, 24, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input *LeadTimeUsecaseStatInput
+		want  error
+	}{
+		{
+			name:  "Concurrency is zero",
+			input: &LeadTimeUsecaseStatInput{Owner: "owner", Repository: "repo"},
+			want:  ErrInvalidConcurrency,
+		},
+		{
+			name:  "Unknown date field",
+			input: &LeadTimeUsecaseStatInput{Owner: "owner", Repository: "repo", Concurrency: 1, DateField: "updated"},
+			want:  ErrInvalidDateField,
+		},
+		{
+			name:  "Since is after until",
+			input: &LeadTimeUsecaseStatInput{Owner: "owner", Repository: "repo", Concurrency: 1, Since: since, Until: since.Add(-time.Hour)},
+			want:  ErrInvalidDateRange,
+		},
+		{
+			name:  "Repository without owner",
+			input: &LeadTimeUsecaseStatInput{Repositories: []*Repository{{Owner: "owner", Name: "repo"}, {Name: "repo"}}, Concurrency: 1},
+			want:  ErrEmptyGitHubOwnerName,
+		},
+		{
+			name:  "Valid repositories",
+			input: &LeadTimeUsecaseStatInput{Repositories: []*Repository{{Owner: "owner", Name: "repo1"}, {Owner: "owner", Name: "repo2"}}, Concurrency: 1},
+			want:  nil,
+		},
+		{
+			name:  "Valid input",
+			input: &LeadTimeUsecaseStatInput{Owner: "owner", Repository: "repo", Concurrency: 1, Since: since, DateField: DateFieldClosed},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tt.input.Valid(); !errors.Is(err, tt.want) {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestPullRequest_toUsecasePullRequest(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 2, 24, 12, 0, 0, 0, time.UTC)
+	firstCommitAt := start.Add(-time.Hour)
+
+	tests := []struct {
+		name              string
+		pr                *model.PullRequest
+		wantStatus        PRStatus
+		wantLeadTime      int
+		wantTimeToMerge   *int
+		wantTimeToAbandon *int
+		wantCodingTime    *int
+	}{
+		{
+			name: "Time to merge is from PR creation to merge",
+			pr: &model.PullRequest{
+				Number:    pointer.Int(1),
+				State:     pointer.String("closed"),
+				CreatedAt: &model.Timestamp{Time: start},
+				ClosedAt:  &model.Timestamp{Time: start.Add(3 * time.Hour)},
+				MergedAt:  &model.Timestamp{Time: start.Add(2 * time.Hour)},
+			},
+			wantStatus:        PRStatusMerged,
+			wantLeadTime:      180,
+			wantTimeToMerge:   pointer.Int(120),
+			wantTimeToAbandon: nil,
+			wantCodingTime:    pointer.Int(60),
+		},
+		{
+			name: "PR closed without merge is abandoned and has no time to merge",
+			pr: &model.PullRequest{
+				Number:    pointer.Int(2),
+				State:     pointer.String("closed"),
+				CreatedAt: &model.Timestamp{Time: start},
+				ClosedAt:  &model.Timestamp{Time: start.Add(time.Hour)},
+			},
+			wantStatus:        PRStatusClosedUnmerged,
+			wantLeadTime:      120,
+			wantTimeToMerge:   nil,
+			wantTimeToAbandon: pointer.Int(60),
+			wantCodingTime:    pointer.Int(60),
+		},
+		{
+			name: "Open PR is neither merged nor abandoned",
+			pr: &model.PullRequest{
+				Number:    pointer.Int(3),
+				State:     pointer.String("open"),
+				CreatedAt: &model.Timestamp{Time: start},
+			},
+			wantStatus:        PRStatusOpen,
+			wantTimeToMerge:   nil,
+			wantTimeToAbandon: nil,
+			wantCodingTime:    pointer.Int(60),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := (&PullRequest{}).toUsecasePullRequest(tt.pr, firstCommitAt, nil)
+			if !got.CreatedAt.Equal(tt.pr.CreatedAt.Time) {
+				t.Errorf("mismatch want=%v, got=%v", tt.pr.CreatedAt.Time, got.CreatedAt)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("mismatch want=%v, got=%v", tt.wantStatus, got.Status)
+			}
+			// Lead time of open PR depends on the current time.
+			if tt.wantStatus != PRStatusOpen && got.MergeTimeMinutes != tt.wantLeadTime {
+				t.Errorf("mismatch want=%v, got=%v", tt.wantLeadTime, got.MergeTimeMinutes)
+			}
+			if diff := cmp.Diff(tt.wantTimeToMerge, got.TimeToMergeMinutes); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantTimeToAbandon, got.TimeToAbandonMinutes); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantCodingTime, got.CodingTimeMinutes); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_reviewTimes(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 2, 24, 12, 0, 0, 0, time.UTC)
+	review := func(user, state string, submittedAt time.Time) *model.Review {
+		return &model.Review{
+			User:        &model.User{Name: pointer.String(user)},
+			State:       pointer.String(state),
+			SubmittedAt: &model.Timestamp{Time: submittedAt},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		reviews         []*model.Review
+		wantFirstReview time.Time
+		wantApproved    time.Time
+	}{
+		{
+			name:            "No review",
+			reviews:         nil,
+			wantFirstReview: time.Time{},
+			wantApproved:    time.Time{},
+		},
+		{
+			name: "First review and first approval in any order",
+			reviews: []*model.Review{
+				review("bob", "APPROVED", start.Add(3*time.Hour)),
+				review("carol", "COMMENTED", start.Add(time.Hour)),
+				review("carol", "APPROVED", start.Add(2*time.Hour)),
+			},
+			wantFirstReview: start.Add(time.Hour),
+			wantApproved:    start.Add(2 * time.Hour),
+		},
+		{
+			name: "Ignore reviews by author, pending reviews and reviews without date",
+			reviews: []*model.Review{
+				review("alice", "APPROVED", start),
+				review("bob", "PENDING", start.Add(time.Hour)),
+				{User: &model.User{Name: pointer.String("carol")}, State: pointer.String("APPROVED")},
+				review("dave", "CHANGES_REQUESTED", start.Add(2*time.Hour)),
+			},
+			wantFirstReview: start.Add(2 * time.Hour),
+			wantApproved:    time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			firstReviewAt, approvedAt := reviewTimes(&model.User{Name: pointer.String("alice")}, tt.reviews)
+			if !firstReviewAt.Equal(tt.wantFirstReview) {
+				t.Errorf("mismatch want=%v, got=%v", tt.wantFirstReview, firstReviewAt)
+			}
+			if !approvedAt.Equal(tt.wantApproved) {
+				t.Errorf("mismatch want=%v, got=%v", tt.wantApproved, approvedAt)
+			}
+		})
+	}
+}
+
+func Test_stageMinutes(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 2, 24, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		after  time.Time
+		before time.Time
+		want   *int
+	}{
+		{
+			name:   "Minutes between two events",
+			after:  start.Add(90 * time.Minute),
+			before: start,
+			want:   pointer.Int(90),
+		},
+		{
+			name:   "Negative span is zero",
+			after:  start,
+			before: start.Add(time.Hour),
+			want:   pointer.Int(0),
+		},
+		{
+			name:   "Later event did not happen",
+			after:  time.Time{},
+			before: start,
+			want:   nil,
+		},
+		{
+			name:   "Earlier event did not happen",
+			after:  start,
+			before: time.Time{},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, stageMinutes(tt.after, tt.before)); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,407 @@
+// Package archive is JSON file of pull requests, first commits and reviews exported
+// by "leadtime export --raw". Statistics can be recomputed from the file later
+// without calling API of hosting service.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+)
+
+// Version is archive format version. It is incremented when the format changes incompatibly.
+// Version 1 stored the domain model with Go field names.
+const Version = 2
+
+// Archive is exported data of repositories.
+type Archive struct {
+	// Version is archive format version
+	Version int `json:"version"`
+	// ExportedAt is date of export
+	ExportedAt time.Time `json:"exported_at"`
+	// Repositories is exported repositories
+	Repositories []*Repository `json:"repositories"`
+}
+
+// Repository is exported data of one repository.
+type Repository struct {
+	// Owner is repository owner
+	Owner string `json:"owner"`
+	// Name is repository name
+	Name string `json:"name"`
+	// PullRequests is pull requests in the order returned by the source
+	PullRequests []*PullRequest `json:"pull_requests"`
+}
+
+// PullRequest is exported data of one pull request. A value that the source does not provide is nil.
+type PullRequest struct {
+	// ID is PR's id.
+	ID *int64 `json:"id,omitempty"`
+	// Number is PR number
+	Number *int `json:"number,omitempty"`
+	// State is PR state(e.g. closed)
+	State *string `json:"state,omitempty"`
+	// Title is PR title
+	Title *string `json:"title,omitempty"`
+	// CreatedAt is date of PR creation
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	// UpdatedAt is date of PR last update
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// ClosedAt is date of PR close
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+	// MergedAt is date of PR merged
+	MergedAt *time.Time `json:"merged_at,omitempty"`
+	// User is user who created the PR
+	User *User `json:"user,omitempty"`
+	// Comments is PR comment count
+	Comments *int `json:"comments,omitempty"`
+	// Additions is number of addition lines
+	Additions *int `json:"additions,omitempty"`
+	// Deletions is number of deletion lines
+	Deletions *int `json:"deletions,omitempty"`
+	// ChangedFiles is number of changed files
+	ChangedFiles *int `json:"changed_files,omitempty"`
+	// Labels is names of PR labels
+	Labels []string `json:"labels,omitempty"`
+	// FirstCommit is first commit in the pull request. nil means the pull request has no commit.
+	FirstCommit *Commit `json:"first_commit,omitempty"`
+	// Reviews is reviews in the pull request.
+	Reviews []*Review `json:"reviews,omitempty"`
+}
+
+// User is exported user.
+type User struct {
+	// Name is user name
+	Name *string `json:"name,omitempty"`
+	// Bot is whether user is bot or not
+	Bot bool `json:"bot,omitempty"`
+}
+
+// Commit is exported git commit.
+type Commit struct {
+	// Author is author user
+	Author *User `json:"author,omitempty"`
+	// Committer is committer user
+	Committer *User `json:"committer,omitempty"`
+	// Date is commit date
+	Date *time.Time `json:"date,omitempty"`
+}
+
+// Review is exported review.
+type Review struct {
+	// User is reviewer
+	User *User `json:"user,omitempty"`
+	// State is review state (e.g. APPROVED)
+	State *string `json:"state,omitempty"`
+	// SubmittedAt is date of review
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+}
+
+// NewPullRequest return exported data of the pull request with its first commit and reviews.
+func NewPullRequest(pr *model.PullRequest, firstCommit *model.Commit, reviews []*model.Review) *PullRequest {
+	p := &PullRequest{
+		ID:           pr.ID,
+		Number:       pr.Number,
+		State:        pr.State,
+		Title:        pr.Title,
+		CreatedAt:    toTime(pr.CreatedAt),
+		UpdatedAt:    toTime(pr.UpdatedAt),
+		ClosedAt:     toTime(pr.ClosedAt),
+		MergedAt:     toTime(pr.MergedAt),
+		User:         newUser(pr.User),
+		Comments:     pr.Comments,
+		Additions:    pr.Additions,
+		Deletions:    pr.Deletions,
+		ChangedFiles: pr.ChangedFiles,
+		Labels:       pr.Labels,
+	}
+	if firstCommit != nil {
+		p.FirstCommit = &Commit{
+			Author:    newUser(firstCommit.Author),
+			Committer: newUser(firstCommit.Committer),
+			Date:      toTime(firstCommit.Date),
+		}
+	}
+	for _, v := range reviews {
+		p.Reviews = append(p.Reviews, &Review{
+			User:        newUser(v.User),
+			State:       v.State,
+			SubmittedAt: toTime(v.SubmittedAt),
+		})
+	}
+	return p
+}
+
+func (pr *PullRequest) toDomainModelPR() *model.PullRequest {
+	return &model.PullRequest{
+		ID:           pr.ID,
+		Number:       pr.Number,
+		State:        pr.State,
+		Title:        pr.Title,
+		CreatedAt:    model.NewTimestamp(pr.CreatedAt),
+		UpdatedAt:    model.NewTimestamp(pr.UpdatedAt),
+		ClosedAt:     model.NewTimestamp(pr.ClosedAt),
+		MergedAt:     model.NewTimestamp(pr.MergedAt),
+		User:         pr.User.toDomainModelUser(),
+		Comments:     pr.Comments,
+		Additions:    pr.Additions,
+		Deletions:    pr.Deletions,
+		ChangedFiles: pr.ChangedFiles,
+		Labels:       pr.Labels,
+	}
+}
+
+func (c *Commit) toDomainModelCommit() *model.Commit {
+	return &model.Commit{
+		Author:    c.Author.toDomainModelUser(),
+		Committer: c.Committer.toDomainModelUser(),
+		Date:      model.NewTimestamp(c.Date),
+	}
+}
+
+func (r *Review) toDomainModelReview() *model.Review {
+	return &model.Review{
+		User:        r.User.toDomainModelUser(),
+		State:       r.State,
+		SubmittedAt: model.NewTimestamp(r.SubmittedAt),
+	}
+}
+
+func newUser(u *model.User) *User {
+	if u == nil {
+		return nil
+	}
+	return &User{Name: u.Name, Bot: u.Bot}
+}
+
+func (u *User) toDomainModelUser() *model.User {
+	if u == nil {
+		return nil
+	}
+	return &model.User{Name: u.Name, Bot: u.Bot}
+}
+
+func toTime(t *model.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return &t.Time
+}
+
+// Write write the archive to w as indented JSON.
+func Write(w io.Writer, a *Archive) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(a); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// Read read the archive from r. If the archive format version is not supported, return error.
+// The archive of older version must be exported again because the format changed.
+func Read(r io.Reader) (*Archive, error) {
+	a := &Archive{}
+	if err := json.NewDecoder(r).Decode(a); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArchive, err.Error())
+	}
+	switch {
+	case a.Version > 0 && a.Version < Version:
+		return nil, fmt.Errorf("%w: the archive format changed since version %d, so export it again with 'leadtime export --raw' (supported version is %d)",
+			ErrOutdatedVersion, a.Version, Version)
+	case a.Version != Version:
+		return nil, fmt.Errorf("%w: version %d (supported version is %d)", ErrUnsupportedVersion, a.Version, Version)
+	}
+	return a, nil
+}
+
+// FileRepository is repository.SourceRepository that replays the archive file.
+// It returns the same data and errors as the source returned at export.
+type FileRepository struct {
+	archive *Archive
+	// repositories is repositories in the archive indexed by "owner/name".
+	repositories map[string]*archivedRepository
+}
+
+// archivedRepository is repository in the archive converted to the domain model.
+type archivedRepository struct {
+	// pullRequests is pull requests in the order of the archive.
+	pullRequests []*archivedPullRequest
+	// numbers is pull requests indexed by PR number.
+	numbers map[int]*archivedPullRequest
+}
+
+// archivedPullRequest is pull request in the archive converted to the domain model.
+type archivedPullRequest struct {
+	pullRequest *model.PullRequest
+	firstCommit *model.Commit
+	reviews     []*model.Review
+}
+
+// newFileRepository return FileRepository that indexes pull requests of the archive.
+// Pull requests without number are not indexed because they cannot be looked up.
+func newFileRepository(a *Archive) *FileRepository {
+	r := &FileRepository{
+		archive:      a,
+		repositories: make(map[string]*archivedRepository, len(a.Repositories)),
+	}
+	for _, v := range a.Repositories {
+		repo := &archivedRepository{
+			pullRequests: make([]*archivedPullRequest, 0, len(v.PullRequests)),
+			numbers:      make(map[int]*archivedPullRequest, len(v.PullRequests)),
+		}
+		for _, pr := range v.PullRequests {
+			archived := &archivedPullRequest{pullRequest: pr.toDomainModelPR()}
+			if pr.FirstCommit != nil {
+				archived.firstCommit = pr.FirstCommit.toDomainModelCommit()
+			}
+			if pr.Reviews != nil {
+				archived.reviews = make([]*model.Review, 0, len(pr.Reviews))
+				for _, review := range pr.Reviews {
+					archived.reviews = append(archived.reviews, review.toDomainModelReview())
+				}
+			}
+			repo.pullRequests = append(repo.pullRequests, archived)
+			if pr.Number != nil {
+				repo.numbers[*pr.Number] = archived
+			}
+		}
+		r.repositories[v.Owner+"/"+v.Name] = repo
+	}
+	return r
+}
+
+// NewFileRepository read the archive file at path and return repository.SourceRepository that replays it.
+func NewFileRepository(path string) (repository.SourceRepository, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close() //nolint
+
+	a, err := Read(f)
+	if err != nil {
+		return nil, err
+	}
+	return newFileRepository(a), nil
+}
+
+// ListRepositories return repositories of org in the archive.
+// If org is empty, return all repositories in the archive.
+func (r *FileRepository) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	repos := make([]*model.Repository, 0, len(r.archive.Repositories))
+	for _, v := range r.archive.Repositories {
+		if org != "" && v.Owner != org {
+			continue
+		}
+		name := v.Name
+		fullName := v.Owner + "/" + v.Name
+		repos = append(repos, &model.Repository{Name: &name, FullName: &fullName})
+	}
+	return repos, nil
+}
+
+// ListPullRequests return pull requests in the archive.
+// If opts.UpdatedSince is set, pull requests updated before it are not listed.
+func (r *FileRepository) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	if opts == nil {
+		opts = &repository.ListPullRequestsOptions{}
+	}
+
+	archived, err := r.repository(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]*model.PullRequest, 0, len(archived.pullRequests))
+	for _, v := range archived.pullRequests {
+		if !opts.UpdatedSince.IsZero() && v.pullRequest.UpdatedAt != nil &&
+			v.pullRequest.UpdatedAt.Time.Before(opts.UpdatedSince) {
+			continue
+		}
+		prs = append(prs, v.pullRequest)
+	}
+
+	if len(prs) == 0 && opts.UpdatedSince.IsZero() {
+		return nil, repository.ErrNoPullRequest
+	}
+	return prs, nil
+}
+
+// ListCommitsInPR return the first commit in the pull request because the archive
+// does not have the other commits.
+func (r *FileRepository) ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error) {
+	commit, err := r.GetFirstCommit(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return []*model.Commit{commit}, nil
+}
+
+// GetFirstCommit return the first commit in the pull request.
+func (r *FileRepository) GetFirstCommit(ctx context.Context, owner, repo string, number int) (*model.Commit, error) {
+	pr, err := r.pullRequest(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	if pr.firstCommit == nil {
+		return nil, repository.ErrNoCommit
+	}
+	return pr.firstCommit, nil
+}
+
+// ListReviews return reviews in the pull request.
+func (r *FileRepository) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	pr, err := r.pullRequest(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	if pr.reviews == nil {
+		return []*model.Review{}, nil
+	}
+	return pr.reviews, nil
+}
+
+// GetPullRequestSize return size of the pull request in the archive.
+// A value that the archive does not have is nil.
+func (r *FileRepository) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	pr, err := r.pullRequest(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return &model.PullRequestSize{
+		Additions:    pr.pullRequest.Additions,
+		Deletions:    pr.pullRequest.Deletions,
+		ChangedFiles: pr.pullRequest.ChangedFiles,
+		Comments:     pr.pullRequest.Comments,
+	}, nil
+}
+
+// repository return the repository in the archive.
+func (r *FileRepository) repository(owner, repo string) (*archivedRepository, error) {
+	archived, ok := r.repositories[owner+"/"+repo]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrRepositoryNotFound, owner, repo)
+	}
+	return archived, nil
+}
+
+// pullRequest return the pull request in the archive.
+func (r *FileRepository) pullRequest(owner, repo string, number int) (*archivedPullRequest, error) {
+	archived, err := r.repository(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	pr, ok := archived.numbers[number]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s#%d", ErrPullRequestNotFound, owner, repo, number)
+	}
+	return pr, nil
+}
@@ -0,0 +1,258 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"github.com/shogo82148/pointer"
+)
+
+// testPR2 is merged pull request in the test archive.
+var testPR2 = &model.PullRequest{
+	Number:    pointer.Int(2),
+	State:     pointer.String("closed"),
+	UpdatedAt: &model.Timestamp{Time: time.Date(2023, 2, 2, 0, 0, 0, 0, time.UTC)},
+	MergedAt:  &model.Timestamp{Time: time.Date(2023, 2, 2, 0, 0, 0, 0, time.UTC)},
+	User:      &model.User{Name: pointer.String("alice")},
+	Additions: pointer.Int(10),
+	Deletions: pointer.Int(2),
+	Labels:    []string{"bug"},
+}
+
+// testPR1 is pull request without commit in the test archive.
+var testPR1 = &model.PullRequest{
+	Number:    pointer.Int(1),
+	State:     pointer.String("closed"),
+	UpdatedAt: &model.Timestamp{Time: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+}
+
+var testFirstCommit = &model.Commit{
+	Author: &model.User{Name: pointer.String("alice")},
+	Date:   &model.Timestamp{Time: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+var testReviews = []*model.Review{
+	{
+		User:        &model.User{Name: pointer.String("bob")},
+		State:       pointer.String("APPROVED"),
+		SubmittedAt: &model.Timestamp{Time: time.Date(2023, 2, 1, 12, 0, 0, 0, time.UTC)},
+	},
+}
+
+func newTestArchive() *Archive {
+	return &Archive{
+		Version:    Version,
+		ExportedAt: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+		Repositories: []*Repository{
+			{
+				Owner: "org",
+				Name:  "repo",
+				PullRequests: []*PullRequest{
+					NewPullRequest(testPR2, testFirstCommit, testReviews),
+					NewPullRequest(testPR1, nil, nil),
+				},
+			},
+			{Owner: "alice", Name: "empty", PullRequests: []*PullRequest{}},
+		},
+	}
+}
+
+// writeTestArchive write archive to temporary file and return the path.
+func writeTestArchive(t *testing.T, a *Archive) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "archive.json")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := Write(f, a); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Read written archive", func(t *testing.T) {
+		t.Parallel()
+
+		want := newTestArchive()
+		var buf bytes.Buffer
+		if err := Write(&buf, want); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := Read(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Write fields with json names", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		if err := Write(&buf, newTestArchive()); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{`"version": 2`, `"number": 2`, `"merged_at": "2023-02-02T00:00:00Z"`,
+			`"user": {`, `"name": "alice"`, `"labels": [`, `"first_commit": {`, `"submitted_at": "2023-02-01T12:00:00Z"`} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("archive does not contain %s:\n%s", want, buf.String())
+			}
+		}
+		if strings.Contains(buf.String(), `"MergedAt"`) {
+			t.Errorf("archive contains Go field name:\n%s", buf.String())
+		}
+	})
+
+	t.Run("Unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Read(strings.NewReader(`{"version":99,"repositories":[]}`))
+		if !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("mismatch want=%v, got=%v", ErrUnsupportedVersion, err)
+		}
+	})
+
+	t.Run("Outdated version must be exported again", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Read(strings.NewReader(`{"version":1,"repositories":[]}`))
+		if !errors.Is(err, ErrOutdatedVersion) {
+			t.Errorf("mismatch want=%v, got=%v", ErrOutdatedVersion, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "format changed") {
+			t.Errorf("error does not tell why the archive must be exported again: %v", err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "leadtime export --raw") {
+			t.Errorf("error does not tell how to export the archive again: %v", err)
+		}
+	})
+
+	t.Run("Not JSON", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Read(strings.NewReader(`version: 1`))
+		if !errors.Is(err, ErrInvalidArchive) {
+			t.Errorf("mismatch want=%v, got=%v", ErrInvalidArchive, err)
+		}
+	})
+}
+
+func TestFileRepository(t *testing.T) {
+	t.Parallel()
+
+	a := newTestArchive()
+	repo, err := NewFileRepository(writeTestArchive(t, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	t.Run("ListRepositories", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := repo.ListRepositories(ctx, "org")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []*model.Repository{{Name: pointer.String("repo"), FullName: pointer.String("org/repo")}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		got, err = repo.ListRepositories(ctx, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 {
+			t.Errorf("mismatch want=2, got=%d", len(got))
+		}
+	})
+
+	t.Run("ListPullRequests", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := repo.ListPullRequests(ctx, "org", "repo", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []*model.PullRequest{testPR2, testPR1}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		got, err = repo.ListPullRequests(ctx, "org", "repo",
+			&repository.ListPullRequestsOptions{UpdatedSince: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || *got[0].Number != 2 {
+			t.Errorf("unexpected pull requests: %+v", got)
+		}
+
+		if _, err := repo.ListPullRequests(ctx, "alice", "empty", nil); !errors.Is(err, repository.ErrNoPullRequest) {
+			t.Errorf("mismatch want=%v, got=%v", repository.ErrNoPullRequest, err)
+		}
+		if _, err := repo.ListPullRequests(ctx, "org", "unknown", nil); !errors.Is(err, ErrRepositoryNotFound) {
+			t.Errorf("mismatch want=%v, got=%v", ErrRepositoryNotFound, err)
+		}
+	})
+
+	t.Run("GetPullRequestSize", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := repo.GetPullRequestSize(ctx, "org", "repo", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &model.PullRequestSize{Additions: pointer.Int(10), Deletions: pointer.Int(2)}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("GetFirstCommit and ListReviews", func(t *testing.T) {
+		t.Parallel()
+
+		commit, err := repo.GetFirstCommit(ctx, "org", "repo", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(testFirstCommit, commit); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		reviews, err := repo.ListReviews(ctx, "org", "repo", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(testReviews, reviews); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		if _, err := repo.GetFirstCommit(ctx, "org", "repo", 1); !errors.Is(err, repository.ErrNoCommit) {
+			t.Errorf("mismatch want=%v, got=%v", repository.ErrNoCommit, err)
+		}
+		if _, err := repo.ListReviews(ctx, "org", "repo", 3); !errors.Is(err, ErrPullRequestNotFound) {
+			t.Errorf("mismatch want=%v, got=%v", ErrPullRequestNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,16 @@
+package archive
+
+import "errors"
+
+var (
+	// ErrInvalidArchive means "file is not archive exported by leadtime"
+	ErrInvalidArchive = errors.New("invalid archive")
+	// ErrUnsupportedVersion means "archive format version is not supported"
+	ErrUnsupportedVersion = errors.New("unsupported archive version")
+	// ErrOutdatedVersion means "archive was exported by older leadtime and must be exported again"
+	ErrOutdatedVersion = errors.New("outdated archive version")
+	// ErrRepositoryNotFound means "repository is not in the archive"
+	ErrRepositoryNotFound = errors.New("repository is not in the archive")
+	// ErrPullRequestNotFound means "pull request is not in the archive"
+	ErrPullRequestNotFound = errors.New("pull request is not in the archive")
+)
@@ -0,0 +1,78 @@
+// Package bitbucket is http client for Bitbucket Cloud REST API and
+// Bitbucket Server/Data Center REST API.
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/infrastructure/httpclient"
+)
+
+// CloudAPIURL is base URL of Bitbucket Cloud REST API.
+const CloudAPIURL = "https://api.bitbucket.org/2.0/"
+
+// Client is http client for Bitbucket REST API. BaseURL is base URL of Bitbucket REST API
+// (e.g. https://api.bitbucket.org/2.0/, https://bitbucket.example.com/rest/api/1.0/).
+type Client struct {
+	*httpclient.Client
+}
+
+// NewCloudClient return http client for Bitbucket Cloud REST API.
+// If apiURL is empty, CloudAPIURL is used.
+func NewCloudClient(token model.Token, username, apiURL string) (*Client, error) {
+	if apiURL == "" {
+		apiURL = CloudAPIURL
+	}
+	return newClient(token, username, apiURL, "")
+}
+
+// NewServerClient return http client for Bitbucket Server/Data Center REST API.
+// baseURL is URL of Bitbucket Server (e.g. https://bitbucket.example.com).
+// If baseURL does not end with /rest/api/1.0/, it is appended.
+func NewServerClient(token model.Token, username, baseURL string) (*Client, error) {
+	return newClient(token, username, baseURL, "rest/api/1.0/")
+}
+
+// newClient return http client for Bitbucket REST API. username is used for basic
+// authentication with app password. If it is empty, token is sent as bearer token.
+func newClient(token model.Token, username, baseURL, apiPath string) (*Client, error) {
+	client, err := httpclient.NewClient("Bitbucket", baseURL, apiPath, func(req *http.Request) {
+		if username != "" {
+			req.SetBasicAuth(username, token.String())
+		} else {
+			req.Header.Set("Authorization", "Bearer "+token.String())
+		}
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidURL, err.Error())
+	}
+	return &Client{Client: client}, nil
+}
+
+// url return absolute URL of the API path. path is escaped in it.
+func (c *Client) url(path string, query url.Values) string {
+	u := *c.BaseURL
+	u.Path += path
+	u.RawPath = ""
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// get send GET request to rawURL and decode response body to v.
+func (c *Client) get(ctx context.Context, rawURL string, v interface{}, message string) error {
+	_, err := c.Get(ctx, rawURL, v, message)
+	return err
+}
+
+// IsCloud check whether baseURL is Bitbucket Cloud or not.
+func IsCloud(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	return u.Host == "bitbucket.org" || u.Host == "api.bitbucket.org"
+}
@@ -0,0 +1,454 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"golang.org/x/exp/slices"
+)
+
+// cloudPagingLimit is number of items fetched by one request. Bitbucket Cloud
+// limits it to 50 for pull requests.
+const cloudPagingLimit = 50
+
+// CloudRepository is http client for Bitbucket Cloud REST API.
+// Paginated response has absolute URL of the next page in "next".
+// owner is workspace and repository is repository slug.
+type CloudRepository struct {
+	client *Client
+	// mu protects activities.
+	mu sync.Mutex
+	// activities is activities of closed pull requests fetched by ListPullRequests.
+	// They are reused by ListReviews. Key is "owner/repo#number".
+	activities map[string][]*cloudActivity
+}
+
+// NewCloudRepository initialize repository.SourceRepository for Bitbucket Cloud.
+func NewCloudRepository(client *Client) repository.SourceRepository {
+	return &CloudRepository{
+		client:     client,
+		activities: make(map[string][]*cloudActivity),
+	}
+}
+
+// ListRepositories return List the repositories of the workspace.
+// If org is empty, return the repositories that the authenticated user is a member of.
+func (b *CloudRepository) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	query := url.Values{"pagelen": {"100"}}
+	path := "repositories"
+	if org == "" {
+		query.Set("role", "member")
+	} else {
+		path += "/" + org
+	}
+
+	repoList := make([]*model.Repository, 0)
+	for rawURL := b.client.url(path, query); rawURL != ""; {
+		page := &struct {
+			Values []*cloudRepo `json:"values"`
+			Next   string       `json:"next"`
+		}{}
+		if err := b.client.get(ctx, rawURL, page, "failed to get repository list"); err != nil {
+			return nil, err
+		}
+		for _, v := range page.Values {
+			repoList = append(repoList, v.toDomainModelRepository())
+		}
+		rawURL = page.Next
+	}
+	return repoList, nil
+}
+
+// ListPullRequests return List the pull requests in order of creation date (newest first).
+// If opts.UpdatedSince is set, pull requests are listed in order of update time
+// and paging stops at the first pull request updated before opts.UpdatedSince.
+//
+// Bitbucket Cloud pull request does not have merge date, so the activity of each
+// closed pull request is fetched to get the date when it was merged or declined.
+func (b *CloudRepository) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	if opts == nil {
+		opts = &repository.ListPullRequestsOptions{}
+	}
+
+	query := url.Values{
+		"state":   {"OPEN", "MERGED", "DECLINED", "SUPERSEDED"},
+		"sort":    {"-created_on"},
+		"pagelen": {strconv.Itoa(cloudPagingLimit)},
+	}
+	if !opts.UpdatedSince.IsZero() {
+		query.Set("sort", "-updated_on")
+	}
+
+	prs := make([]*cloudPullRequest, 0)
+	rawURL := b.client.url(cloudRepoPath(owner, repo)+"/pullrequests", query)
+	for rawURL != "" {
+		page := &struct {
+			Values []*cloudPullRequest `json:"values"`
+			Next   string              `json:"next"`
+		}{}
+		if err := b.client.get(ctx, rawURL, page, "failed to get pull request list"); err != nil {
+			return nil, err
+		}
+
+		reachedOldPR := false
+		for _, v := range page.Values {
+			if !opts.UpdatedSince.IsZero() && v.UpdatedOn != nil && v.UpdatedOn.Before(opts.UpdatedSince) {
+				reachedOldPR = true
+				break
+			}
+			prs = append(prs, v)
+		}
+		if reachedOldPR {
+			break
+		}
+		rawURL = page.Next
+	}
+
+	if len(prs) == 0 && opts.UpdatedSince.IsZero() {
+		return nil, repository.ErrNoPullRequest
+	}
+
+	pullReqs := make([]*model.PullRequest, 0, len(prs))
+	for _, v := range prs {
+		pullReqs = append(pullReqs, v.toDomainModelPR())
+	}
+	return pullReqs, nil
+}
+
+// ResolveCloseDate return the pull request whose merge and close dates are taken from
+// its activities. The pull request list does not include them, so ListPullRequests sets
+// the update date instead. If there is no activity that closes the pull request, or the
+// pull request is open, it is returned as it is.
+func (b *CloudRepository) ResolveCloseDate(ctx context.Context, owner, repo string, pr *model.PullRequest) (*model.PullRequest, error) {
+	if !pr.IsClosed() || pr.Number == nil {
+		return pr, nil
+	}
+
+	activities, err := b.listActivities(ctx, owner, repo, *pr.Number)
+	if err != nil {
+		return nil, err
+	}
+	states := []string{"DECLINED", "SUPERSEDED"}
+	if pr.MergedAt != nil {
+		states = []string{"MERGED"}
+	}
+	closedAt := closedDate(activities, states...)
+	if closedAt == nil {
+		return pr, nil
+	}
+
+	p := *pr
+	p.ClosedAt = model.NewTimestamp(closedAt)
+	if p.MergedAt != nil {
+		p.MergedAt = p.ClosedAt
+	}
+	return &p, nil
+}
+
+// ListCommitsInPR return List the commits in the pull request.
+// order is oldest to newest.
+func (b *CloudRepository) ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error) {
+	query := url.Values{"pagelen": {strconv.Itoa(cloudPagingLimit)}}
+	path := fmt.Sprintf("%s/pullrequests/%d/commits", cloudRepoPath(owner, repo), number)
+
+	commits := make([]*cloudCommit, 0)
+	for rawURL := b.client.url(path, query); rawURL != ""; {
+		page := &struct {
+			Values []*cloudCommit `json:"values"`
+			Next   string         `json:"next"`
+		}{}
+		if err := b.client.get(ctx, rawURL, page, "failed to get commit list"); err != nil {
+			return nil, err
+		}
+		commits = append(commits, page.Values...)
+		rawURL = page.Next
+	}
+	if len(commits) == 0 {
+		return nil, repository.ErrNoCommit
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Date.Before(commits[j].Date)
+	})
+	commitsInPR := make([]*model.Commit, 0, len(commits))
+	for _, v := range commits {
+		commitsInPR = append(commitsInPR, v.toDomainModelCommit())
+	}
+	return commitsInPR, nil
+}
+
+// GetFirstCommit return the first commit in the pull request.
+func (b *CloudRepository) GetFirstCommit(ctx context.Context, owner, repository string, number int) (*model.Commit, error) {
+	list, err := b.ListCommitsInPR(ctx, owner, repository, number)
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// ListReviews return List the reviews in the pull request. Bitbucket Cloud does
+// not have review object like GitHub, so comments, approvals and change requests
+// in the activity are converted to reviews. order is oldest to newest.
+func (b *CloudRepository) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	activities, err := b.listActivities(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := make([]*model.Review, 0, len(activities))
+	for _, v := range activities {
+		if review := v.toDomainModelReview(); review != nil {
+			reviews = append(reviews, review)
+		}
+	}
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].SubmittedAt.Time.Before(reviews[j].SubmittedAt.Time)
+	})
+	return reviews, nil
+}
+
+// GetPullRequestSize return number of added and deleted lines and changed files
+// in the pull request from the diffstat.
+func (b *CloudRepository) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	query := url.Values{"pagelen": {"500"}}
+	path := fmt.Sprintf("%s/pullrequests/%d/diffstat", cloudRepoPath(owner, repo), number)
+
+	additions, deletions, files := 0, 0, 0
+	for rawURL := b.client.url(path, query); rawURL != ""; {
+		page := &struct {
+			Values []struct {
+				LinesAdded   int `json:"lines_added"`
+				LinesRemoved int `json:"lines_removed"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}{}
+		if err := b.client.get(ctx, rawURL, page, "failed to get diffstat"); err != nil {
+			return nil, err
+		}
+		for _, v := range page.Values {
+			additions += v.LinesAdded
+			deletions += v.LinesRemoved
+			files++
+		}
+		rawURL = page.Next
+	}
+
+	return &model.PullRequestSize{
+		Additions:    &additions,
+		Deletions:    &deletions,
+		ChangedFiles: &files,
+	}, nil
+}
+
+// listActivities return activities of the pull request. Fetched activities are
+// kept and reused.
+func (b *CloudRepository) listActivities(ctx context.Context, owner, repo string, number int) ([]*cloudActivity, error) {
+	key := fmt.Sprintf("%s/%s#%d", owner, repo, number)
+	b.mu.Lock()
+	activities, ok := b.activities[key]
+	b.mu.Unlock()
+	if ok {
+		return activities, nil
+	}
+
+	query := url.Values{"pagelen": {strconv.Itoa(cloudPagingLimit)}}
+	path := fmt.Sprintf("%s/pullrequests/%d/activity", cloudRepoPath(owner, repo), number)
+	activities = make([]*cloudActivity, 0)
+	for rawURL := b.client.url(path, query); rawURL != ""; {
+		page := &struct {
+			Values []*cloudActivity `json:"values"`
+			Next   string           `json:"next"`
+		}{}
+		if err := b.client.get(ctx, rawURL, page, "failed to get activity list"); err != nil {
+			return nil, err
+		}
+		activities = append(activities, page.Values...)
+		rawURL = page.Next
+	}
+
+	b.mu.Lock()
+	b.activities[key] = activities
+	b.mu.Unlock()
+	return activities, nil
+}
+
+// cloudRepoPath return API path of the repository.
+func cloudRepoPath(owner, repo string) string {
+	return "repositories/" + owner + "/" + repo
+}
+
+// closedDate return the latest date when pull request changed to one of states.
+// If there is no such activity, return nil.
+func closedDate(activities []*cloudActivity, states ...string) *time.Time {
+	var date *time.Time
+	for _, v := range activities {
+		if v.Update == nil || !slices.Contains(states, v.Update.State) || v.Update.Date == nil {
+			continue
+		}
+		if date == nil || v.Update.Date.After(*date) {
+			date = v.Update.Date
+		}
+	}
+	return date
+}
+
+// cloudUser is user in Bitbucket Cloud REST API.
+type cloudUser struct {
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname"`
+	Type        string `json:"type"`
+}
+
+func (u *cloudUser) toDomainModelUser() *model.User {
+	if u == nil {
+		return nil
+	}
+	name := u.Nickname
+	if name == "" {
+		name = u.DisplayName
+	}
+	return &model.User{
+		Name: &name,
+		Bot:  u.Type == "app_user",
+	}
+}
+
+// cloudRepo is repository in Bitbucket Cloud REST API.
+type cloudRepo struct {
+	Slug        string `json:"slug"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+}
+
+func (r *cloudRepo) toDomainModelRepository() *model.Repository {
+	return &model.Repository{
+		Name:        &r.Slug,
+		FullName:    &r.FullName,
+		Description: &r.Description,
+	}
+}
+
+// cloudPullRequest is pull request in Bitbucket Cloud REST API.
+type cloudPullRequest struct {
+	ID           int        `json:"id"`
+	Title        string     `json:"title"`
+	State        string     `json:"state"`
+	CreatedOn    *time.Time `json:"created_on"`
+	UpdatedOn    *time.Time `json:"updated_on"`
+	Author       *cloudUser `json:"author"`
+	CommentCount int        `json:"comment_count"`
+}
+
+// toDomainModelPR convert pull request to *model.PullRequest. Bitbucket state
+// "OPEN" is converted to "open", and the other states are converted to "closed".
+// The list does not include close date, so the update date is used until
+// ResolveCloseDate resolves it.
+func (pr *cloudPullRequest) toDomainModelPR() *model.PullRequest {
+	id := int64(pr.ID)
+	state := "closed"
+	var closedAt, mergedAt *time.Time
+	if pr.State == "OPEN" {
+		state = "open"
+	} else {
+		closedAt = pr.UpdatedOn
+	}
+	if pr.State == "MERGED" {
+		mergedAt = closedAt
+	}
+
+	return &model.PullRequest{
+		ID:        &id,
+		Number:    &pr.ID,
+		State:     &state,
+		Title:     &pr.Title,
+		CreatedAt: model.NewTimestamp(pr.CreatedOn),
+		UpdatedAt: model.NewTimestamp(pr.UpdatedOn),
+		ClosedAt:  model.NewTimestamp(closedAt),
+		MergedAt:  model.NewTimestamp(mergedAt),
+		User:      pr.Author.toDomainModelUser(),
+		Comments:  &pr.CommentCount,
+	}
+}
+
+// cloudCommit is git commit in Bitbucket Cloud REST API.
+type cloudCommit struct {
+	Date   time.Time `json:"date"`
+	Author struct {
+		Raw  string     `json:"raw"`
+		User *cloudUser `json:"user"`
+	} `json:"author"`
+}
+
+func (c *cloudCommit) toDomainModelCommit() *model.Commit {
+	author := c.Author.User.toDomainModelUser()
+	if author == nil {
+		// raw is "name <email>" of git commit. It is used when the committer is
+		// not linked to Bitbucket account.
+		name, _, _ := strings.Cut(c.Author.Raw, " <")
+		author = &model.User{Name: &name}
+	}
+	return &model.Commit{
+		Author:    author,
+		Committer: author,
+		Date:      &model.Timestamp{Time: c.Date},
+	}
+}
+
+// cloudActivity is activity of pull request in Bitbucket Cloud REST API.
+// Only one of the fields is set.
+type cloudActivity struct {
+	Update *struct {
+		State  string     `json:"state"`
+		Date   *time.Time `json:"date"`
+		Author *cloudUser `json:"author"`
+	} `json:"update"`
+	Approval *struct {
+		Date *time.Time `json:"date"`
+		User *cloudUser `json:"user"`
+	} `json:"approval"`
+	ChangesRequested *struct {
+		Date *time.Time `json:"date"`
+		User *cloudUser `json:"user"`
+	} `json:"changes_requested"`
+	Comment *struct {
+		CreatedOn *time.Time `json:"created_on"`
+		User      *cloudUser `json:"user"`
+	} `json:"comment"`
+}
+
+// toDomainModelReview convert activity to *model.Review.
+// Return nil for the activity that is not review (e.g. update).
+func (a *cloudActivity) toDomainModelReview() *model.Review {
+	var (
+		state string
+		date  *time.Time
+		user  *cloudUser
+	)
+	switch {
+	case a.Approval != nil:
+		state, date, user = "APPROVED", a.Approval.Date, a.Approval.User
+	case a.ChangesRequested != nil:
+		state, date, user = "CHANGES_REQUESTED", a.ChangesRequested.Date, a.ChangesRequested.User
+	case a.Comment != nil:
+		state, date, user = "COMMENTED", a.Comment.CreatedOn, a.Comment.User
+	default:
+		return nil
+	}
+	if date == nil {
+		return nil
+	}
+
+	return &model.Review{
+		User:        user.toDomainModelUser(),
+		State:       &state,
+		SubmittedAt: model.NewTimestamp(date),
+	}
+}
@@ -0,0 +1,276 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"github.com/shogo82148/pointer"
+)
+
+// newCloudTestRepository return CloudRepository that sends requests to handler.
+func newCloudTestRepository(t *testing.T, handler http.Handler) (repository.SourceRepository, string) {
+	t.Helper()
+
+	testServer := httptest.NewServer(handler)
+	t.Cleanup(testServer.Close)
+
+	client, err := NewCloudClient(model.Token("test_token"), "", testServer.URL+"/2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewCloudRepository(client), testServer.URL
+}
+
+func write(t *testing.T, w http.ResponseWriter, body string) {
+	t.Helper()
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsCloud(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		want    bool
+	}{
+		{name: "bitbucket.org", baseURL: "https://bitbucket.org", want: true},
+		{name: "Cloud API", baseURL: "https://api.bitbucket.org/2.0/", want: true},
+		{name: "Bitbucket Server", baseURL: "https://bitbucket.example.com", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsCloud(tt.baseURL); got != tt.want {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCloudRepository_ListPullRequests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Get pull requests over pages and resolve merge date from activity", func(t *testing.T) {
+		t.Parallel()
+
+		var activityCalls int32
+		mux := http.NewServeMux()
+		var serverURL string
+		mux.HandleFunc("/2.0/repositories/workspace/repo/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer test_token" {
+				t.Errorf("unexpected authorization: %s", r.Header.Get("Authorization"))
+			}
+			if r.URL.Query().Get("page") == "" {
+				if got := r.URL.Query()["state"]; len(got) != 4 {
+					t.Errorf("unexpected state: %v", got)
+				}
+				write(t, w, `{"values":[{"id":2,"title":"pr2","state":"MERGED","created_on":"2023-01-02T00:00:00.000000+00:00",
+"updated_on":"2023-01-03T00:00:00.000000+00:00","author":{"display_name":"Alice","nickname":"alice","type":"user"},"comment_count":1}],
+"next":"`+serverURL+`/2.0/repositories/workspace/repo/pullrequests?page=2"}`)
+				return
+			}
+			write(t, w, `{"values":[{"id":1,"title":"pr1","state":"OPEN","created_on":"2023-01-01T00:00:00+00:00",
+"updated_on":"2023-01-01T00:00:00+00:00","author":{"display_name":"Renovate","type":"app_user"},"comment_count":0}]}`)
+		})
+		mux.HandleFunc("/2.0/repositories/workspace/repo/pullrequests/2/activity", func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&activityCalls, 1)
+			write(t, w, `{"values":[
+{"update":{"state":"MERGED","date":"2023-01-02T03:00:00+00:00","author":{"nickname":"bob"}}},
+{"approval":{"date":"2023-01-02T02:00:00+00:00","user":{"nickname":"bob"}}},
+{"comment":{"created_on":"2023-01-02T01:00:00+00:00","user":{"nickname":"bob"}}},
+{"update":{"state":"OPEN","date":"2023-01-02T00:00:00+00:00","author":{"nickname":"alice"}}}]}`)
+		})
+		repo, url := newCloudTestRepository(t, mux)
+		serverURL = url
+		ctx := context.Background()
+
+		got, err := repo.ListPullRequests(ctx, "workspace", "repo", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The list does not include merge date, so the update date is used until it is resolved.
+		updated := &model.Timestamp{Time: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)}
+		want := []*model.PullRequest{
+			{
+				ID:        pointer.Int64(2),
+				Number:    pointer.Int(2),
+				State:     pointer.String("closed"),
+				Title:     pointer.String("pr2"),
+				CreatedAt: &model.Timestamp{Time: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+				UpdatedAt: &model.Timestamp{Time: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)},
+				ClosedAt:  updated,
+				MergedAt:  updated,
+				User:      &model.User{Name: pointer.String("alice")},
+				Comments:  pointer.Int(1),
+			},
+			{
+				ID:        pointer.Int64(1),
+				Number:    pointer.Int(1),
+				State:     pointer.String("open"),
+				Title:     pointer.String("pr1"),
+				CreatedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+				UpdatedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+				User:      &model.User{Name: pointer.String("Renovate"), Bot: true},
+				Comments:  pointer.Int(0),
+			},
+		}
+		if diff := cmp.Diff(want, got, cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) })); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+		if calls := atomic.LoadInt32(&activityCalls); calls != 0 {
+			t.Errorf("mismatch activity calls want=0, got=%d", calls)
+		}
+
+		resolver, ok := repo.(repository.CloseDateResolver)
+		if !ok {
+			t.Fatal("CloudRepository does not implement repository.CloseDateResolver")
+		}
+		open, err := resolver.ResolveCloseDate(ctx, "workspace", "repo", got[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if open != got[1] {
+			t.Errorf("open pull request is changed: %+v", open)
+		}
+		resolved, err := resolver.ResolveCloseDate(ctx, "workspace", "repo", got[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		merged := &model.Timestamp{Time: time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)}
+		if diff := cmp.Diff(merged, resolved.MergedAt); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(merged, resolved.ClosedAt); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		// Activity fetched by ResolveCloseDate is reused.
+		reviews, err := repo.ListReviews(ctx, "workspace", "repo", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantReviews := []*model.Review{
+			{
+				User:        &model.User{Name: pointer.String("bob")},
+				State:       pointer.String("COMMENTED"),
+				SubmittedAt: &model.Timestamp{Time: time.Date(2023, 1, 2, 1, 0, 0, 0, time.UTC)},
+			},
+			{
+				User:        &model.User{Name: pointer.String("bob")},
+				State:       pointer.String("APPROVED"),
+				SubmittedAt: &model.Timestamp{Time: time.Date(2023, 1, 2, 2, 0, 0, 0, time.UTC)},
+			},
+		}
+		if diff := cmp.Diff(wantReviews, reviews, cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) })); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+		if calls := atomic.LoadInt32(&activityCalls); calls != 1 {
+			t.Errorf("mismatch activity calls want=1, got=%d", calls)
+		}
+	})
+
+	t.Run("No pull request", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _ := newCloudTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			write(t, w, `{"values":[]}`)
+		}))
+
+		if _, err := repo.ListPullRequests(context.Background(), "workspace", "repo", nil); !errors.Is(err, repository.ErrNoPullRequest) {
+			t.Errorf("mismatch want=%v, got=%v", repository.ErrNoPullRequest, err)
+		}
+	})
+
+	t.Run("Return status code 404 from Bitbucket", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _ := newCloudTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			if _, err := w.Write([]byte(`{"type":"error","error":{"message":"Repository not found"}}`)); err != nil {
+				t.Fatal(err)
+			}
+		}))
+
+		_, err := repo.ListPullRequests(context.Background(), "workspace", "repo", nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("mismatch expect=%T, got=%T", &APIError{}, err)
+		}
+		if apiErr.StatusCode != http.StatusNotFound {
+			t.Errorf("mismatch expect=%d, got=%d", http.StatusNotFound, apiErr.StatusCode)
+		}
+	})
+}
+
+func TestCloudRepository_GetFirstCommit(t *testing.T) {
+	t.Parallel()
+
+	repo, _ := newCloudTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/repositories/workspace/repo/pullrequests/3/commits" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		write(t, w, `{"values":[
+{"hash":"b","date":"2023-01-03T00:00:00+00:00","author":{"raw":"Alice <alice@example.com>","user":{"nickname":"alice"}}},
+{"hash":"a","date":"2023-01-01T00:00:00+00:00","author":{"raw":"Bob <bob@example.com>"}}]}`)
+	}))
+
+	got, err := repo.GetFirstCommit(context.Background(), "workspace", "repo", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &model.Commit{
+		Author:    &model.User{Name: pointer.String("Bob")},
+		Committer: &model.User{Name: pointer.String("Bob")},
+		Date:      &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) })); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCloudRepository_GetPullRequestSize(t *testing.T) {
+	t.Parallel()
+
+	var serverURL string
+	repo, url := newCloudTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/repositories/workspace/repo/pullrequests/3/diffstat" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") == "" {
+			write(t, w, `{"values":[{"lines_added":10,"lines_removed":2}],
+"next":"`+serverURL+`/2.0/repositories/workspace/repo/pullrequests/3/diffstat?page=2"}`)
+			return
+		}
+		write(t, w, `{"values":[{"lines_added":1,"lines_removed":0},{"lines_added":0,"lines_removed":5}]}`)
+	}))
+	serverURL = url
+
+	got, err := repo.GetPullRequestSize(context.Background(), "workspace", "repo", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &model.PullRequestSize{
+		Additions:    pointer.Int(11),
+		Deletions:    pointer.Int(7),
+		ChangedFiles: pointer.Int(3),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,15 @@
+package bitbucket
+
+import (
+	"errors"
+
+	"github.com/nao1215/leadtime/infrastructure/httpclient"
+)
+
+// APIError is error for Bitbucket API.
+type APIError = httpclient.APIError
+
+var (
+	// ErrInvalidURL means "invalid Bitbucket URL"
+	ErrInvalidURL = errors.New("invalid Bitbucket URL")
+)
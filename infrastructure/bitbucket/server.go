@@ -0,0 +1,417 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+)
+
+// serverPagingLimit is number of items fetched by one request.
+const serverPagingLimit = 100
+
+// ServerRepository is http client for Bitbucket Server/Data Center REST API.
+// Paginated response has "isLastPage" and "nextPageStart" (offset of the next page).
+// owner is project key (e.g. PROJ, ~user for personal repository) and repository
+// is repository slug.
+type ServerRepository struct {
+	client *Client
+}
+
+// NewServerRepository initialize repository.SourceRepository for Bitbucket Server/Data Center.
+func NewServerRepository(client *Client) repository.SourceRepository {
+	return &ServerRepository{client: client}
+}
+
+// serverPage is common fields of paginated response.
+type serverPage struct {
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// next return whether there is next page or not. If there is, set start of query.
+func (p *serverPage) next(query url.Values) bool {
+	if p.IsLastPage {
+		return false
+	}
+	query.Set("start", strconv.Itoa(p.NextPageStart))
+	return true
+}
+
+// ListRepositories return List the repositories of the project.
+// If org is empty, return the repositories that the authenticated user can access.
+func (b *ServerRepository) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	query := url.Values{"limit": {strconv.Itoa(serverPagingLimit)}}
+	path := "repos"
+	if org != "" {
+		path = "projects/" + org + "/repos"
+	}
+
+	repoList := make([]*model.Repository, 0)
+	for {
+		page := &struct {
+			serverPage
+			Values []*serverRepo `json:"values"`
+		}{}
+		if err := b.client.get(ctx, b.client.url(path, query), page, "failed to get repository list"); err != nil {
+			return nil, err
+		}
+		for _, v := range page.Values {
+			repoList = append(repoList, v.toDomainModelRepository())
+		}
+		if !page.next(query) {
+			break
+		}
+	}
+	return repoList, nil
+}
+
+// ListPullRequests return List the pull requests in order of creation date (newest first).
+// If opts.UpdatedSince is set, pull requests updated before it are not listed.
+// Bitbucket Server does not support sorting by update time, so all pages are fetched.
+func (b *ServerRepository) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	if opts == nil {
+		opts = &repository.ListPullRequestsOptions{}
+	}
+
+	query := url.Values{
+		"state": {"ALL"},
+		"order": {"NEWEST"},
+		"limit": {strconv.Itoa(serverPagingLimit)},
+	}
+
+	pullReqs := make([]*model.PullRequest, 0)
+	for {
+		page := &struct {
+			serverPage
+			Values []*serverPullRequest `json:"values"`
+		}{}
+		if err := b.client.get(ctx, b.client.url(serverRepoPath(owner, repo)+"/pull-requests", query), page, "failed to get pull request list"); err != nil {
+			return nil, err
+		}
+		for _, v := range page.Values {
+			if !opts.UpdatedSince.IsZero() && v.UpdatedDate.time().Before(opts.UpdatedSince) {
+				continue
+			}
+			pullReqs = append(pullReqs, v.toDomainModelPR())
+		}
+		if !page.next(query) {
+			break
+		}
+	}
+
+	if len(pullReqs) == 0 && opts.UpdatedSince.IsZero() {
+		return nil, repository.ErrNoPullRequest
+	}
+	return pullReqs, nil
+}
+
+// ListCommitsInPR return List the commits in the pull request.
+// order is oldest to newest.
+func (b *ServerRepository) ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error) {
+	query := url.Values{"limit": {strconv.Itoa(serverPagingLimit)}}
+	path := fmt.Sprintf("%s/pull-requests/%d/commits", serverRepoPath(owner, repo), number)
+
+	commits := make([]*serverCommit, 0)
+	for {
+		page := &struct {
+			serverPage
+			Values []*serverCommit `json:"values"`
+		}{}
+		if err := b.client.get(ctx, b.client.url(path, query), page, "failed to get commit list"); err != nil {
+			return nil, err
+		}
+		commits = append(commits, page.Values...)
+		if !page.next(query) {
+			break
+		}
+	}
+	if len(commits) == 0 {
+		return nil, repository.ErrNoCommit
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].CommitterTimestamp < commits[j].CommitterTimestamp
+	})
+	commitsInPR := make([]*model.Commit, 0, len(commits))
+	for _, v := range commits {
+		commitsInPR = append(commitsInPR, v.toDomainModelCommit())
+	}
+	return commitsInPR, nil
+}
+
+// GetFirstCommit return the first commit in the pull request.
+func (b *ServerRepository) GetFirstCommit(ctx context.Context, owner, repository string, number int) (*model.Commit, error) {
+	list, err := b.ListCommitsInPR(ctx, owner, repository, number)
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// ListReviews return List the reviews in the pull request. Bitbucket Server does
+// not have review object like GitHub, so comments, approvals and "needs work"
+// in the activities are converted to reviews. order is oldest to newest.
+func (b *ServerRepository) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	query := url.Values{"limit": {strconv.Itoa(serverPagingLimit)}}
+	path := fmt.Sprintf("%s/pull-requests/%d/activities", serverRepoPath(owner, repo), number)
+
+	reviews := make([]*model.Review, 0)
+	for {
+		page := &struct {
+			serverPage
+			Values []*serverActivity `json:"values"`
+		}{}
+		if err := b.client.get(ctx, b.client.url(path, query), page, "failed to get activity list"); err != nil {
+			return nil, err
+		}
+		for _, v := range page.Values {
+			if review := v.toDomainModelReview(); review != nil {
+				reviews = append(reviews, review)
+			}
+		}
+		if !page.next(query) {
+			break
+		}
+	}
+
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].SubmittedAt.Time.Before(reviews[j].SubmittedAt.Time)
+	})
+	return reviews, nil
+}
+
+// GetPullRequestSize return number of added and deleted lines and changed files in the
+// pull request. Changed files are counted from the changes, and lines are counted from
+// the diff. If Bitbucket Server truncates the large diff, number of lines is nil.
+func (b *ServerRepository) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	query := url.Values{"limit": {strconv.Itoa(serverPagingLimit)}}
+	path := fmt.Sprintf("%s/pull-requests/%d/changes", serverRepoPath(owner, repo), number)
+
+	files := 0
+	for {
+		page := &struct {
+			serverPage
+			Values []struct{} `json:"values"`
+		}{}
+		if err := b.client.get(ctx, b.client.url(path, query), page, "failed to get change list"); err != nil {
+			return nil, err
+		}
+		files += len(page.Values)
+		if !page.next(query) {
+			break
+		}
+	}
+
+	diff := &serverDiff{}
+	path = fmt.Sprintf("%s/pull-requests/%d/diff", serverRepoPath(owner, repo), number)
+	if err := b.client.get(ctx, b.client.url(path, url.Values{"contextLines": {"0"}}), diff, "failed to get diff"); err != nil {
+		return nil, err
+	}
+
+	size := &model.PullRequestSize{ChangedFiles: &files}
+	if !diff.Truncated {
+		additions, deletions := diff.lines()
+		size.Additions, size.Deletions = &additions, &deletions
+	}
+	return size, nil
+}
+
+// serverRepoPath return API path of the repository.
+func serverRepoPath(owner, repo string) string {
+	return "projects/" + owner + "/repos/" + repo
+}
+
+// epochMillis is unix time in milliseconds used by Bitbucket Server REST API.
+type epochMillis int64
+
+// time convert epochMillis to time.Time. If it is zero, return zero time.
+func (e epochMillis) time() time.Time {
+	if e == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(e)).UTC()
+}
+
+// timestamp convert epochMillis to *model.Timestamp. If it is zero, return nil.
+func (e epochMillis) timestamp() *model.Timestamp {
+	if e == 0 {
+		return nil
+	}
+	return &model.Timestamp{Time: e.time()}
+}
+
+// serverUser is user in Bitbucket Server REST API.
+type serverUser struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func (u *serverUser) toDomainModelUser() *model.User {
+	if u == nil {
+		return nil
+	}
+	name := u.Name
+	return &model.User{
+		Name: &name,
+		Bot:  u.Type == "SERVICE",
+	}
+}
+
+// serverRepo is repository in Bitbucket Server REST API.
+type serverRepo struct {
+	ID          int64  `json:"id"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Project     struct {
+		Key string `json:"key"`
+	} `json:"project"`
+}
+
+func (r *serverRepo) toDomainModelRepository() *model.Repository {
+	fullName := r.Project.Key + "/" + r.Slug
+	return &model.Repository{
+		ID:          &r.ID,
+		Name:        &r.Slug,
+		FullName:    &fullName,
+		Description: &r.Description,
+	}
+}
+
+// serverPullRequest is pull request in Bitbucket Server REST API.
+type serverPullRequest struct {
+	ID          int         `json:"id"`
+	Title       string      `json:"title"`
+	State       string      `json:"state"`
+	CreatedDate epochMillis `json:"createdDate"`
+	UpdatedDate epochMillis `json:"updatedDate"`
+	// ClosedDate is available on Bitbucket Server 7.0 or later.
+	ClosedDate epochMillis `json:"closedDate"`
+	Author     struct {
+		User *serverUser `json:"user"`
+	} `json:"author"`
+	Properties struct {
+		CommentCount int `json:"commentCount"`
+	} `json:"properties"`
+}
+
+// toDomainModelPR convert pull request to *model.PullRequest. Bitbucket state
+// "OPEN" is converted to "open", and the other states are converted to "closed".
+// If closedDate is not available, updatedDate is used as it.
+func (pr *serverPullRequest) toDomainModelPR() *model.PullRequest {
+	id := int64(pr.ID)
+	state := "closed"
+	if pr.State == "OPEN" {
+		state = "open"
+	}
+
+	var closedAt, mergedAt *model.Timestamp
+	if state == "closed" {
+		closedAt = pr.ClosedDate.timestamp()
+		if closedAt == nil {
+			closedAt = pr.UpdatedDate.timestamp()
+		}
+		if pr.State == "MERGED" {
+			mergedAt = closedAt
+		}
+	}
+
+	return &model.PullRequest{
+		ID:        &id,
+		Number:    &pr.ID,
+		State:     &state,
+		Title:     &pr.Title,
+		CreatedAt: pr.CreatedDate.timestamp(),
+		UpdatedAt: pr.UpdatedDate.timestamp(),
+		ClosedAt:  closedAt,
+		MergedAt:  mergedAt,
+		User:      pr.Author.User.toDomainModelUser(),
+		Comments:  &pr.Properties.CommentCount,
+	}
+}
+
+// serverCommit is git commit in Bitbucket Server REST API.
+type serverCommit struct {
+	Author             *serverUser `json:"author"`
+	Committer          *serverUser `json:"committer"`
+	CommitterTimestamp epochMillis `json:"committerTimestamp"`
+}
+
+func (c *serverCommit) toDomainModelCommit() *model.Commit {
+	return &model.Commit{
+		Author:    c.Author.toDomainModelUser(),
+		Committer: c.Committer.toDomainModelUser(),
+		Date:      c.CommitterTimestamp.timestamp(),
+	}
+}
+
+// serverActivity is activity of pull request in Bitbucket Server REST API.
+type serverActivity struct {
+	Action      string      `json:"action"`
+	CreatedDate epochMillis `json:"createdDate"`
+	User        *serverUser `json:"user"`
+}
+
+// toDomainModelReview convert activity to *model.Review. "REVIEWED" action means
+// that the reviewer marked the pull request as "needs work", so it is converted to
+// CHANGES_REQUESTED. Return nil for the activity that is not review (e.g. OPENED).
+func (a *serverActivity) toDomainModelReview() *model.Review {
+	var state string
+	switch a.Action {
+	case "APPROVED":
+		state = "APPROVED"
+	case "REVIEWED":
+		state = "CHANGES_REQUESTED"
+	case "COMMENTED":
+		state = "COMMENTED"
+	default:
+		return nil
+	}
+	if a.CreatedDate == 0 {
+		return nil
+	}
+
+	return &model.Review{
+		User:        a.User.toDomainModelUser(),
+		State:       &state,
+		SubmittedAt: a.CreatedDate.timestamp(),
+	}
+}
+
+// serverDiff is diff of pull request in Bitbucket Server REST API.
+type serverDiff struct {
+	Diffs []struct {
+		Hunks []struct {
+			Segments []struct {
+				// Type is ADDED, REMOVED or CONTEXT.
+				Type  string            `json:"type"`
+				Lines []json.RawMessage `json:"lines"`
+			} `json:"segments"`
+		} `json:"hunks"`
+	} `json:"diffs"`
+	// Truncated is whether the diff is too large to be returned entirely.
+	Truncated bool `json:"truncated"`
+}
+
+// lines return number of added and deleted lines in the diff.
+func (d *serverDiff) lines() (additions, deletions int) {
+	for _, diff := range d.Diffs {
+		for _, hunk := range diff.Hunks {
+			for _, segment := range hunk.Segments {
+				switch segment.Type {
+				case "ADDED":
+					additions += len(segment.Lines)
+				case "REMOVED":
+					deletions += len(segment.Lines)
+				}
+			}
+		}
+	}
+	return additions, deletions
+}
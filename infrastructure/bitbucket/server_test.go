@@ -0,0 +1,256 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"github.com/shogo82148/pointer"
+)
+
+// newServerTestRepository return ServerRepository that sends requests to handler.
+func newServerTestRepository(t *testing.T, handler http.Handler) repository.SourceRepository {
+	t.Helper()
+
+	testServer := httptest.NewServer(handler)
+	t.Cleanup(testServer.Close)
+
+	client, err := NewServerClient(model.Token("test_token"), "user", testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewServerRepository(client)
+}
+
+func TestNewServerClient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+	}{
+		{name: "Host only", baseURL: "https://bitbucket.example.com", want: "https://bitbucket.example.com/rest/api/1.0/"},
+		{name: "API path", baseURL: "https://bitbucket.example.com/rest/api/1.0", want: "https://bitbucket.example.com/rest/api/1.0/"},
+		{name: "Context path", baseURL: "https://example.com/bitbucket/", want: "https://example.com/bitbucket/rest/api/1.0/"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := NewServerClient(model.Token("token"), "", tt.baseURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := client.BaseURL.String(); got != tt.want {
+				t.Errorf("mismatch want=%s, got=%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestServerRepository_ListPullRequests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Get pull requests over pages", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newServerTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/rest/api/1.0/projects/PROJ/repos/repo/pull-requests" {
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+			if user, token, ok := r.BasicAuth(); !ok || user != "user" || token != "test_token" {
+				t.Errorf("unexpected authorization: %s", r.Header.Get("Authorization"))
+			}
+			if r.URL.Query().Get("state") != "ALL" {
+				t.Errorf("unexpected state: %s", r.URL.Query().Get("state"))
+			}
+
+			if r.URL.Query().Get("start") == "" {
+				// 1672628400000 is 2023-01-02T03:00:00Z
+				write(t, w, `{"isLastPage":false,"nextPageStart":1,"values":[{"id":2,"title":"pr2","state":"MERGED",
+"createdDate":1672617600000,"updatedDate":1672628400000,"closedDate":1672624800000,
+"author":{"user":{"name":"alice","type":"NORMAL"}},"properties":{"commentCount":1}}]}`)
+				return
+			}
+			if r.URL.Query().Get("start") != "1" {
+				t.Errorf("unexpected start: %s", r.URL.Query().Get("start"))
+			}
+			write(t, w, `{"isLastPage":true,"values":[{"id":1,"title":"pr1","state":"DECLINED",
+"createdDate":1672531200000,"updatedDate":1672534800000,"author":{"user":{"name":"ci","type":"SERVICE"}}}]}`)
+		}))
+
+		got, err := repo.ListPullRequests(context.Background(), "PROJ", "repo", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		merged := &model.Timestamp{Time: time.Date(2023, 1, 2, 2, 0, 0, 0, time.UTC)}
+		want := []*model.PullRequest{
+			{
+				ID:        pointer.Int64(2),
+				Number:    pointer.Int(2),
+				State:     pointer.String("closed"),
+				Title:     pointer.String("pr2"),
+				CreatedAt: &model.Timestamp{Time: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+				UpdatedAt: &model.Timestamp{Time: time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)},
+				ClosedAt:  merged,
+				MergedAt:  merged,
+				User:      &model.User{Name: pointer.String("alice")},
+				Comments:  pointer.Int(1),
+			},
+			{
+				ID:        pointer.Int64(1),
+				Number:    pointer.Int(1),
+				State:     pointer.String("closed"),
+				Title:     pointer.String("pr1"),
+				CreatedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+				UpdatedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC)},
+				ClosedAt:  &model.Timestamp{Time: time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC)},
+				User:      &model.User{Name: pointer.String("ci"), Bot: true},
+				Comments:  pointer.Int(0),
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Filter PRs updated before UpdatedSince", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newServerTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			write(t, w, `{"isLastPage":true,"values":[
+{"id":2,"state":"OPEN","createdDate":1672617600000,"updatedDate":1672617600000},
+{"id":1,"state":"OPEN","createdDate":1669852800000,"updatedDate":1669852800000}]}`)
+		}))
+
+		got, err := repo.ListPullRequests(context.Background(), "PROJ", "repo",
+			&repository.ListPullRequestsOptions{UpdatedSince: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || *got[0].Number != 2 {
+			t.Errorf("unexpected pull requests: %+v", got)
+		}
+	})
+}
+
+func TestServerRepository_GetFirstCommit(t *testing.T) {
+	t.Parallel()
+
+	repo := newServerTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PROJ/repos/repo/pull-requests/3/commits" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		write(t, w, `{"isLastPage":true,"values":[
+{"id":"b","author":{"name":"alice"},"committer":{"name":"alice"},"committerTimestamp":1672704000000},
+{"id":"a","author":{"name":"bob"},"committer":{"name":"bob"},"committerTimestamp":1672531200000}]}`)
+	}))
+
+	got, err := repo.GetFirstCommit(context.Background(), "PROJ", "repo", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &model.Commit{
+		Author:    &model.User{Name: pointer.String("bob")},
+		Committer: &model.User{Name: pointer.String("bob")},
+		Date:      &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerRepository_ListReviews(t *testing.T) {
+	t.Parallel()
+
+	repo := newServerTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		write(t, w, `{"isLastPage":true,"values":[
+{"action":"MERGED","createdDate":1672542000000,"user":{"name":"bob"}},
+{"action":"APPROVED","createdDate":1672538400000,"user":{"name":"bob"}},
+{"action":"REVIEWED","createdDate":1672534800000,"user":{"name":"bob"}},
+{"action":"OPENED","createdDate":1672531200000,"user":{"name":"alice"}}]}`)
+	}))
+
+	got, err := repo.ListReviews(context.Background(), "PROJ", "repo", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*model.Review{
+		{
+			User:        &model.User{Name: pointer.String("bob")},
+			State:       pointer.String("CHANGES_REQUESTED"),
+			SubmittedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC)},
+		},
+		{
+			User:        &model.User{Name: pointer.String("bob")},
+			State:       pointer.String("APPROVED"),
+			SubmittedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC)},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerRepository_GetPullRequestSize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Count changed files and lines", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newServerTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/rest/api/1.0/projects/PROJ/repos/repo/pull-requests/3/changes":
+				write(t, w, `{"isLastPage":true,"values":[{"path":{"toString":"a.go"}},{"path":{"toString":"b.go"}}]}`)
+			case "/rest/api/1.0/projects/PROJ/repos/repo/pull-requests/3/diff":
+				write(t, w, `{"diffs":[{"hunks":[{"segments":[
+{"type":"REMOVED","lines":[{"line":"a"}]},
+{"type":"ADDED","lines":[{"line":"b"},{"line":"c"}]}]}]}],"truncated":false}`)
+			default:
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+		}))
+
+		got, err := repo.GetPullRequestSize(context.Background(), "PROJ", "repo", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &model.PullRequestSize{
+			Additions:    pointer.Int(2),
+			Deletions:    pointer.Int(1),
+			ChangedFiles: pointer.Int(2),
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Lines of truncated diff are unknown", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newServerTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/changes") {
+				write(t, w, `{"isLastPage":true,"values":[{}]}`)
+				return
+			}
+			write(t, w, `{"diffs":[],"truncated":true}`)
+		}))
+
+		got, err := repo.GetPullRequestSize(context.Background(), "PROJ", "repo", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(&model.PullRequestSize{ChangedFiles: pointer.Int(1)}, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,398 @@
+// Package cache is on-disk cache of pull requests, first commits and reviews.
+// Closed pull requests never change, so leadtime stores them locally and
+// fetches only pull requests updated since the last synchronization.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+)
+
+const (
+	// version is cache format version. If the format changes, the cache is discarded.
+	version = 3
+	// syncFileName is file name that stores synchronization information.
+	syncFileName = "sync.json"
+	// pullsDirName is directory name that stores pull request entries.
+	pullsDirName = "pulls"
+	// syncMargin is margin for clock difference between GitHub and local machine.
+	syncMargin = time.Minute
+)
+
+// syncInfo is synchronization information of one repository.
+type syncInfo struct {
+	// Version is cache format version
+	Version int `json:"version"`
+	// Owner is repository owner
+	Owner string `json:"owner"`
+	// Repository is repository name
+	Repository string `json:"repository"`
+	// SyncedAt is date of last synchronization
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// entry is cached data of one pull request.
+type entry struct {
+	// PullRequest is pull request information
+	PullRequest *model.PullRequest `json:"pull_request"`
+	// FirstCommit is first commit in the pull request. nil means not fetched yet.
+	FirstCommit *model.Commit `json:"first_commit,omitempty"`
+	// Reviews is reviews in the pull request. nil means not fetched yet.
+	Reviews []*model.Review `json:"reviews,omitempty"`
+	// ReviewsFetched is whether reviews are fetched or not. It distinguishes no review from not fetched.
+	ReviewsFetched bool `json:"reviews_fetched,omitempty"`
+	// Size is size of the pull request. nil means not fetched yet.
+	Size *model.PullRequestSize `json:"size,omitempty"`
+	// CloseDateResolved is whether merge and close dates of PullRequest are resolved
+	// by repository.CloseDateResolver or not.
+	CloseDateResolved bool `json:"close_date_resolved,omitempty"`
+}
+
+// Repository is repository.SourceRepository that caches the data fetched by source.
+type Repository struct {
+	source repository.SourceRepository
+	dir    string
+	now    func() time.Time
+	mu     sync.Mutex
+}
+
+// NewRepository return repository.SourceRepository that caches data under dir.
+func NewRepository(source repository.SourceRepository, dir string) repository.SourceRepository {
+	return &Repository{
+		source: source,
+		dir:    dir,
+		now:    time.Now,
+	}
+}
+
+// ListRepositories return repository list. Repository list is not cached.
+func (r *Repository) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	return r.source.ListRepositories(ctx, org)
+}
+
+// ListPullRequests return pull request list. At first, all pull requests are fetched and cached.
+// After that, only pull requests updated since the last synchronization are fetched.
+func (r *Repository) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	startedAt := r.now()
+
+	info, err := r.readSyncInfo(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedSince := time.Time{}
+	if info != nil {
+		updatedSince = info.SyncedAt.Add(-syncMargin)
+	}
+
+	prs, err := r.source.ListPullRequests(ctx, owner, repo, &repository.ListPullRequestsOptions{UpdatedSince: updatedSince})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range prs {
+		if v.Number == nil {
+			continue
+		}
+		// The PR may have new commits or reviews, so cached details are discarded.
+		if err := r.writeEntry(owner, repo, *v.Number, &entry{PullRequest: v}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.writeSyncInfo(&syncInfo{
+		Version:    version,
+		Owner:      owner,
+		Repository: repo,
+		SyncedAt:   startedAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return r.cachedPullRequests(owner, repo, opts)
+}
+
+// ListCommitsInPR return commits in the PR. Commit list is not cached.
+func (r *Repository) ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error) {
+	return r.source.ListCommitsInPR(ctx, owner, repo, number)
+}
+
+// GetFirstCommit return first commit in the PR from cache if exists.
+func (r *Repository) GetFirstCommit(ctx context.Context, owner, repo string, number int) (*model.Commit, error) {
+	e, err := r.readEntry(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	if e != nil && e.FirstCommit != nil {
+		return e.FirstCommit, nil
+	}
+
+	commit, err := r.source.GetFirstCommit(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if e != nil {
+		if err := r.updateEntry(owner, repo, number, func(e *entry) { e.FirstCommit = commit }); err != nil {
+			return nil, err
+		}
+	}
+	return commit, nil
+}
+
+// ListReviews return reviews in the PR from cache if exists.
+func (r *Repository) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	e, err := r.readEntry(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	if e != nil && e.ReviewsFetched {
+		return e.Reviews, nil
+	}
+
+	reviews, err := r.source.ListReviews(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if e != nil {
+		if err := r.updateEntry(owner, repo, number, func(e *entry) {
+			e.Reviews = reviews
+			e.ReviewsFetched = true
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return reviews, nil
+}
+
+// GetPullRequestSize return size of the PR from cache if exists.
+func (r *Repository) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	e, err := r.readEntry(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	if e != nil && e.Size != nil {
+		return e.Size, nil
+	}
+
+	size, err := r.source.GetPullRequestSize(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if e != nil {
+		if err := r.updateEntry(owner, repo, number, func(e *entry) { e.Size = size }); err != nil {
+			return nil, err
+		}
+	}
+	return size, nil
+}
+
+// ResolveCloseDate return the PR with exact merge and close dates. If the source does not
+// implement repository.CloseDateResolver, the PR is returned as it is. Resolved PR is cached.
+func (r *Repository) ResolveCloseDate(ctx context.Context, owner, repo string, pr *model.PullRequest) (*model.PullRequest, error) {
+	resolver, ok := r.source.(repository.CloseDateResolver)
+	if !ok || pr.Number == nil {
+		return pr, nil
+	}
+
+	e, err := r.readEntry(owner, repo, *pr.Number)
+	if err != nil {
+		return nil, err
+	}
+	if e != nil && e.CloseDateResolved && e.PullRequest != nil {
+		return e.PullRequest, nil
+	}
+
+	resolved, err := resolver.ResolveCloseDate(ctx, owner, repo, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	if e != nil {
+		if err := r.updateEntry(owner, repo, *pr.Number, func(e *entry) {
+			e.PullRequest = resolved
+			e.CloseDateResolved = true
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// cachedPullRequests return cached pull requests in order of PR number descending.
+func (r *Repository) cachedPullRequests(owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	files, err := os.ReadDir(r.pullsDir(owner, repo))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []*model.PullRequest{}, nil
+		}
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	prs := make([]*model.PullRequest, 0, len(files))
+	for _, f := range files {
+		number, err := strconv.Atoi(strings.TrimSuffix(f.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+
+		e, err := r.readEntry(owner, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil || e.PullRequest == nil {
+			continue
+		}
+		if opts != nil && !opts.UpdatedSince.IsZero() &&
+			e.PullRequest.UpdatedAt != nil && e.PullRequest.UpdatedAt.Time.Before(opts.UpdatedSince) {
+			continue
+		}
+		prs = append(prs, e.PullRequest)
+	}
+
+	sort.Slice(prs, func(i, j int) bool {
+		return prNumber(prs[i]) > prNumber(prs[j])
+	})
+	return prs, nil
+}
+
+// prNumber return PR number. If PR does not have number, return 0.
+func prNumber(pr *model.PullRequest) int {
+	if pr.Number == nil {
+		return 0
+	}
+	return *pr.Number
+}
+
+// repositoryDir return cache directory for the repository. owner is escaped to one
+// path element because GitLab owner may have subgroups (e.g. group/subgroup), so that
+// every repository is at the same depth and no repository is under another one.
+func (r *Repository) repositoryDir(owner, repo string) string {
+	return filepath.Join(r.dir, url.PathEscape(owner), repo)
+}
+
+// pullsDir return cache directory for pull requests in the repository.
+func (r *Repository) pullsDir(owner, repo string) string {
+	return filepath.Join(r.repositoryDir(owner, repo), pullsDirName)
+}
+
+// entryPath return cache file path for the pull request.
+func (r *Repository) entryPath(owner, repo string, number int) string {
+	return filepath.Join(r.pullsDir(owner, repo), strconv.Itoa(number)+".json")
+}
+
+// readSyncInfo return synchronization information. If the repository is not cached
+// or the cache format is old, return nil.
+func (r *Repository) readSyncInfo(owner, repo string) (*syncInfo, error) {
+	info := &syncInfo{}
+	found, err := readJSON(filepath.Join(r.repositoryDir(owner, repo), syncFileName), info)
+	if err != nil || !found {
+		return nil, err
+	}
+	if info.Version != version {
+		return nil, nil
+	}
+	return info, nil
+}
+
+func (r *Repository) writeSyncInfo(info *syncInfo) error {
+	return writeJSON(filepath.Join(r.repositoryDir(info.Owner, info.Repository), syncFileName), info)
+}
+
+// readEntry return cached pull request. If it is not cached, return nil.
+func (r *Repository) readEntry(owner, repo string, number int) (*entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e := &entry{}
+	found, err := readJSON(r.entryPath(owner, repo, number), e)
+	if err != nil || !found {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *Repository) writeEntry(owner, repo string, number int, e *entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return writeJSON(r.entryPath(owner, repo, number), e)
+}
+
+// updateEntry read cached pull request, apply update and write it.
+func (r *Repository) updateEntry(owner, repo string, number int, update func(e *entry)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := r.entryPath(owner, repo, number)
+	e := &entry{}
+	found, err := readJSON(path, e)
+	if err != nil || !found {
+		return err
+	}
+	update(e)
+	return writeJSON(path, e)
+}
+
+// readJSON read json file into v. If the file does not exist, return false.
+func readJSON(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		// Broken cache file is treated as not cached. It is overwritten later.
+		return false, nil //nolint
+	}
+	return true, nil
+}
+
+// writeJSON write v to path as json. The file is replaced atomically.
+func writeJSON(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()           //nolint
+		os.Remove(tmp.Name()) //nolint
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name()) //nolint
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name()) //nolint
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,368 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"github.com/shogo82148/pointer"
+)
+
+// fakeSource is repository.SourceRepository that counts API calls.
+type fakeSource struct {
+	prs          []*model.PullRequest
+	updatedSince []time.Time
+	commitCalls  int
+	reviewCalls  int
+	sizeCalls    int
+}
+
+func (f *fakeSource) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	return nil, nil
+}
+
+func (f *fakeSource) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	f.updatedSince = append(f.updatedSince, opts.UpdatedSince)
+
+	prs := make([]*model.PullRequest, 0)
+	for _, v := range f.prs {
+		if v.UpdatedAt.Time.Before(opts.UpdatedSince) {
+			continue
+		}
+		prs = append(prs, v)
+	}
+	return prs, nil
+}
+
+func (f *fakeSource) ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error) {
+	return nil, nil
+}
+
+func (f *fakeSource) GetFirstCommit(ctx context.Context, owner, repository string, number int) (*model.Commit, error) {
+	f.commitCalls++
+	return &model.Commit{Date: &model.Timestamp{Time: time.Date(2023, 1, number, 0, 0, 0, 0, time.UTC)}}, nil
+}
+
+func (f *fakeSource) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	f.reviewCalls++
+	return []*model.Review{}, nil
+}
+
+func (f *fakeSource) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	f.sizeCalls++
+	return &model.PullRequestSize{Additions: pointer.Int(number)}, nil
+}
+
+// fakeResolverSource is fakeSource that resolves close date of pull requests.
+type fakeResolverSource struct {
+	fakeSource
+	resolveCalls int
+}
+
+func (f *fakeResolverSource) ResolveCloseDate(ctx context.Context, owner, repo string, pr *model.PullRequest) (*model.PullRequest, error) {
+	f.resolveCalls++
+	p := *pr
+	p.ClosedAt = &model.Timestamp{Time: pr.UpdatedAt.Time.Add(-time.Hour)}
+	return &p, nil
+}
+
+func newPR(number int, updatedAt time.Time, title string) *model.PullRequest {
+	return &model.PullRequest{
+		Number:    pointer.Int(number),
+		Title:     pointer.String(title),
+		State:     pointer.String("closed"),
+		UpdatedAt: &model.Timestamp{Time: updatedAt},
+	}
+}
+
+func TestRepository_ListPullRequests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Fetch only PRs updated since the last synchronization", func(t *testing.T) {
+		t.Parallel()
+
+		firstSync := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+		source := &fakeSource{
+			prs: []*model.PullRequest{
+				newPR(1, firstSync.Add(-48*time.Hour), "pr1"),
+				newPR(2, firstSync.Add(-24*time.Hour), "pr2"),
+			},
+		}
+		repo := &Repository{source: source, dir: t.TempDir(), now: func() time.Time { return firstSync }}
+		ctx := context.Background()
+
+		if _, err := repo.ListPullRequests(ctx, "owner", "repo", nil); err != nil {
+			t.Fatal(err)
+		}
+
+		// PR #2 is updated and PR #3 is created after the first synchronization.
+		source.prs = []*model.PullRequest{
+			newPR(1, firstSync.Add(-48*time.Hour), "pr1"),
+			newPR(2, firstSync.Add(time.Hour), "pr2 updated"),
+			newPR(3, firstSync.Add(2*time.Hour), "pr3"),
+		}
+		repo.now = func() time.Time { return firstSync.Add(3 * time.Hour) }
+
+		got, err := repo.ListPullRequests(ctx, "owner", "repo", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []*model.PullRequest{
+			newPR(3, firstSync.Add(2*time.Hour), "pr3"),
+			newPR(2, firstSync.Add(time.Hour), "pr2 updated"),
+			newPR(1, firstSync.Add(-48*time.Hour), "pr1"),
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		wantUpdatedSince := []time.Time{{}, firstSync.Add(-syncMargin)}
+		if diff := cmp.Diff(wantUpdatedSince, source.updatedSince); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Filter cached PRs by UpdatedSince option", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+		source := &fakeSource{
+			prs: []*model.PullRequest{
+				newPR(1, now.Add(-48*time.Hour), "pr1"),
+				newPR(2, now.Add(-time.Hour), "pr2"),
+			},
+		}
+		repo := &Repository{source: source, dir: t.TempDir(), now: func() time.Time { return now }}
+
+		got, err := repo.ListPullRequests(context.Background(), "owner", "repo",
+			&repository.ListPullRequestsOptions{UpdatedSince: now.Add(-24 * time.Hour)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]*model.PullRequest{newPR(2, now.Add(-time.Hour), "pr2")}, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestRepository_GetFirstCommitListReviewsAndGetPullRequestSize(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	source := &fakeSource{prs: []*model.PullRequest{newPR(1, now, "pr1")}}
+	dir := t.TempDir()
+	repo := &Repository{source: source, dir: dir, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	if _, err := repo.ListPullRequests(ctx, "owner", "repo", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		// Use new instance to read the cache from disk.
+		repo := &Repository{source: source, dir: dir, now: func() time.Time { return now }}
+
+		commit, err := repo.GetFirstCommit(ctx, "owner", "repo", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC); !commit.Date.Time.Equal(want) {
+			t.Errorf("mismatch want=%v, got=%v", want, commit.Date.Time)
+		}
+
+		if _, err := repo.ListReviews(ctx, "owner", "repo", 1); err != nil {
+			t.Fatal(err)
+		}
+
+		size, err := repo.GetPullRequestSize(ctx, "owner", "repo", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(&model.PullRequestSize{Additions: pointer.Int(1)}, size); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	if source.commitCalls != 1 {
+		t.Errorf("first commit is not cached: %d calls", source.commitCalls)
+	}
+	if source.reviewCalls != 1 {
+		t.Errorf("reviews are not cached: %d calls", source.reviewCalls)
+	}
+	if source.sizeCalls != 1 {
+		t.Errorf("size is not cached: %d calls", source.sizeCalls)
+	}
+}
+
+func TestRepository_ResolveCloseDate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("Resolve close date once and cache it", func(t *testing.T) {
+		t.Parallel()
+
+		updatedAt := now.Add(-24 * time.Hour)
+		source := &fakeResolverSource{fakeSource: fakeSource{prs: []*model.PullRequest{newPR(1, updatedAt, "pr1")}}}
+		dir := t.TempDir()
+		want := &model.Timestamp{Time: updatedAt.Add(-time.Hour)}
+
+		for i := 0; i < 2; i++ {
+			// Use new instance to read the cache from disk.
+			repo := &Repository{source: source, dir: dir, now: func() time.Time { return now }}
+			prs, err := repo.ListPullRequests(ctx, "owner", "repo", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pr, err := repo.ResolveCloseDate(ctx, "owner", "repo", prs[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, pr.ClosedAt); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		}
+		if source.resolveCalls != 1 {
+			t.Errorf("close date is not cached: %d calls", source.resolveCalls)
+		}
+	})
+
+	t.Run("Return PR as it is if source does not resolve close date", func(t *testing.T) {
+		t.Parallel()
+
+		pr := newPR(1, now, "pr1")
+		repo := &Repository{source: &fakeSource{prs: []*model.PullRequest{pr}}, dir: t.TempDir(), now: func() time.Time { return now }}
+		got, err := repo.ResolveCloseDate(ctx, "owner", "repo", pr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != pr {
+			t.Errorf("mismatch want=%v, got=%v", pr, got)
+		}
+	})
+}
+
+func TestListPruneClear(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	ctx := context.Background()
+	old := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, v := range []struct {
+		owner, repo string
+		syncedAt    time.Time
+	}{
+		{owner: "nao1215", repo: "sqly", syncedAt: old},
+		{owner: "nao1215", repo: "gup", syncedAt: recent},
+		{owner: "alice", repo: "tool", syncedAt: recent},
+	} {
+		syncedAt := v.syncedAt
+		source := &fakeSource{prs: []*model.PullRequest{newPR(1, syncedAt, "pr1"), newPR(2, syncedAt, "pr2")}}
+		repo := &Repository{source: source, dir: root + "/github.com", now: func() time.Time { return syncedAt }}
+		if _, err := repo.ListPullRequests(ctx, v.owner, v.repo, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	infos, err := List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 3 {
+		t.Fatalf("mismatch want=3, got=%d", len(infos))
+	}
+	for _, v := range infos {
+		if v.Host != "github.com" || v.PullRequests != 2 || v.Size == 0 {
+			t.Errorf("unexpected cache info: %+v", v)
+		}
+	}
+
+	removed, err := Prune(root, recent.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0].Repository != "sqly" {
+		t.Errorf("unexpected pruned repositories: %+v", removed)
+	}
+
+	if err := Clear(root, "", "gup"); !errors.Is(err, ErrNoOwner) {
+		t.Errorf("mismatch want=%v, got=%v", ErrNoOwner, err)
+	}
+	if err := Clear(root, "nao1215", ""); err != nil {
+		t.Fatal(err)
+	}
+	infos, err = List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 || infos[0].Owner != "alice" {
+		t.Errorf("unexpected cached repositories: %+v", infos)
+	}
+
+	if err := Clear(root, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	infos, err = List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 0 {
+		t.Errorf("cache is not cleared: %+v", infos)
+	}
+}
+
+func TestListClear_subgroupOwner(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	ctx := context.Background()
+	now := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, v := range []struct{ owner, repo string }{
+		{owner: "group/sub", repo: "tool"},
+		{owner: "group", repo: "sub"},
+		{owner: "group/sub/deep", repo: "lib"},
+	} {
+		source := &fakeSource{prs: []*model.PullRequest{newPR(1, now, "pr1")}}
+		repo := &Repository{source: source, dir: root + "/gitlab.com", now: func() time.Time { return now }}
+		if _, err := repo.ListPullRequests(ctx, v.owner, v.repo, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	infos, err := List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 0, len(infos))
+	for _, v := range infos {
+		if v.Host != "gitlab.com" || v.PullRequests != 1 {
+			t.Errorf("unexpected cache info: %+v", v)
+		}
+		got = append(got, v.Owner+"/"+v.Repository)
+	}
+	// Owners are escaped in the paths, so "group/sub" repository does not include "group/sub/tool" repository.
+	want := []string{"group/sub/deep/lib", "group/sub/tool", "group/sub"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := Clear(root, "group/sub", ""); err != nil {
+		t.Fatal(err)
+	}
+	infos, err = List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 || infos[0].Owner != "group/sub/deep" || infos[1].Owner != "group" {
+		t.Errorf("unexpected cached repositories: %+v", infos)
+	}
+}
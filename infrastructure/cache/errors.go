@@ -0,0 +1,8 @@
+package cache
+
+import "errors"
+
+var (
+	// ErrNoOwner means "repository is specified without owner"
+	ErrNoOwner = errors.New("repository is specified without owner")
+)
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Info is summary of one cached repository.
+type Info struct {
+	// Host is host name of the source (e.g. github.com)
+	Host string
+	// Owner is repository owner
+	Owner string
+	// Repository is repository name
+	Repository string
+	// PullRequests is number of cached pull requests
+	PullRequests int
+	// SyncedAt is date of last synchronization
+	SyncedAt time.Time
+	// Size is total file size in bytes
+	Size int64
+	// Path is cache directory of the repository
+	Path string
+}
+
+// List return cached repositories under root. Cache directory layout is root/host/owner/repository,
+// and owner that has subgroups is escaped to one path element (e.g. group%2Fsubgroup).
+func List(root string) ([]*Info, error) {
+	paths, err := filepath.Glob(filepath.Join(root, "*", "*", "*", syncFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search cache: %w", err)
+	}
+
+	infos := make([]*Info, 0, len(paths))
+	for _, path := range paths {
+		sync := &syncInfo{}
+		found, err := readJSON(path, sync)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+
+		dir := filepath.Dir(path)
+		info := &Info{
+			Host:       filepath.Base(filepath.Dir(filepath.Dir(dir))),
+			Owner:      sync.Owner,
+			Repository: sync.Repository,
+			SyncedAt:   sync.SyncedAt,
+			Path:       dir,
+		}
+		if err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+			info.Size += fi.Size()
+			if filepath.Base(filepath.Dir(path)) == pullsDirName {
+				info.PullRequests++
+			}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to read cache: %w", err)
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Path < infos[j].Path
+	})
+	return infos, nil
+}
+
+// Prune remove cached repositories that are not synchronized since before.
+// Return removed repositories.
+func Prune(root string, before time.Time) ([]*Info, error) {
+	infos, err := List(root)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]*Info, 0)
+	for _, v := range infos {
+		if !v.SyncedAt.Before(before) {
+			continue
+		}
+		if err := os.RemoveAll(v.Path); err != nil {
+			return nil, fmt.Errorf("failed to remove cache: %w", err)
+		}
+		removed = append(removed, v)
+	}
+	return removed, nil
+}
+
+// Clear remove cached data. If owner is empty, all cached repositories under root are removed.
+// If repo is empty, all repositories of the owner are removed.
+// Only directories created by the cache are removed.
+func Clear(root, owner, repo string) error {
+	if owner == "" && repo != "" {
+		return ErrNoOwner
+	}
+
+	infos, err := List(root)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range infos {
+		if owner != "" && v.Owner != owner {
+			continue
+		}
+		if repo != "" && v.Repository != repo {
+			continue
+		}
+		if err := os.RemoveAll(v.Path); err != nil {
+			return fmt.Errorf("failed to remove cache: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,367 @@
+// Package git derives pull requests from the history of local git repository.
+// It does not use API of hosting service, so access token is not needed.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+)
+
+var (
+	// ErrNotGitRepository means "directory is not git repository"
+	ErrNotGitRepository = errors.New("not a git repository")
+)
+
+// mergePatterns is patterns of PR number in merge commit message.
+var mergePatterns = []*regexp.Regexp{
+	// GitHub: "Merge pull request #123 from owner/branch"
+	regexp.MustCompile(`^Merge pull request #(\d+)`),
+	// Bitbucket: "Merged in branch (pull request #123)"
+	regexp.MustCompile(`\(pull request #(\d+)\)`),
+	// GitLab: "See merge request group/project!123" in body
+	regexp.MustCompile(`(?m)^See merge request \S*!(\d+)$`),
+}
+
+// squashPattern is pattern of PR number at the end of squash commit subject.
+// e.g. "Fix typo (#123)"
+var squashPattern = regexp.MustCompile(`\s*\(#(\d+)\)$`)
+
+// GitRepository derives pull requests from merge commits and squash commits on the
+// first-parent history of HEAD. owner and repository arguments of its methods are
+// ignored because it handles only one local repository.
+type GitRepository struct {
+	dir string
+	// mu protects pullRequests.
+	mu sync.Mutex
+	// pullRequests is pull requests found by ListPullRequests. Key is PR number.
+	pullRequests map[int]*pullRequest
+}
+
+// NewGitRepository initialize repository.SourceRepository for local git repository.
+// If dir is not git repository, return error.
+func NewGitRepository(dir string) (repository.SourceRepository, error) {
+	r := &GitRepository{dir: dir}
+	if _, err := r.git(context.Background(), "rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrNotGitRepository, dir, err.Error())
+	}
+	return r, nil
+}
+
+// git execute git command in the repository and return stdout.
+func (r *GitRepository) git(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// ListRepositories return the local repository. org is ignored.
+func (r *GitRepository) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	abs, err := filepath.Abs(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(abs)
+	return []*model.Repository{{Name: &name, FullName: &name}}, nil
+}
+
+// ListPullRequests return List the pull requests derived from merge commits and squash
+// commits with "(#123)" in order of the history (newest first). Merge commits without PR
+// number (e.g. merge of local branch) are not pull requests, so they are ignored.
+// If opts.UpdatedSince is set, pull requests merged before it are not listed.
+func (r *GitRepository) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	if opts == nil {
+		opts = &repository.ListPullRequestsOptions{}
+	}
+
+	out, err := r.git(ctx, "log", "--first-parent", "-z", "--format=%H%x1f%P%x1f%an%x1f%aI%x1f%cn%x1f%cI%x1f%s%x1f%b", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make(map[int]*pullRequest)
+	pullReqs := make([]*model.PullRequest, 0)
+	for _, record := range strings.Split(strings.Trim(out, "\x00\n"), "\x00") {
+		c, err := parseLogRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		pr := c.pullRequest()
+		if pr == nil {
+			continue
+		}
+		if _, ok := prs[pr.number]; ok {
+			// The newest merge is used if the same PR number appears more than once (e.g. revert and merge again).
+			continue
+		}
+		if !opts.UpdatedSince.IsZero() && c.committedAt.Before(opts.UpdatedSince) {
+			continue
+		}
+
+		if pr.commits, err = r.mergedCommits(ctx, c); err != nil {
+			return nil, err
+		}
+		prs[pr.number] = pr
+		pullReqs = append(pullReqs, pr.toDomainModelPR())
+	}
+
+	r.mu.Lock()
+	r.pullRequests = prs
+	r.mu.Unlock()
+
+	if len(pullReqs) == 0 && opts.UpdatedSince.IsZero() {
+		return nil, repository.ErrNoPullRequest
+	}
+	return pullReqs, nil
+}
+
+// mergedCommits return commits reachable only from the merged side of the merge commit.
+// For squash commit, return the commit itself. order is oldest to newest.
+func (r *GitRepository) mergedCommits(ctx context.Context, c *commit) ([]*commit, error) {
+	if len(c.parents) < 2 {
+		// Squash commit loses the branch history. Author date is the only date before merge.
+		return []*commit{{authorName: c.authorName, committerName: c.committerName, committedAt: c.authoredAt}}, nil
+	}
+
+	args := []string{"log", "--format=%H%x1f%P%x1f%an%x1f%aI%x1f%cn%x1f%cI"}
+	args = append(args, c.parents[1:]...)
+	args = append(args, "^"+c.parents[0])
+	out, err := r.git(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]*commit, 0)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		v, err := parseLogRecord(line)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, v)
+	}
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].committedAt.Before(commits[j].committedAt)
+	})
+	return commits, nil
+}
+
+// ListCommitsInPR return List the commits in the pull request found by ListPullRequests.
+// order is oldest to newest.
+func (r *GitRepository) ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error) {
+	r.mu.Lock()
+	pr, ok := r.pullRequests[number]
+	r.mu.Unlock()
+	if !ok || len(pr.commits) == 0 {
+		return nil, repository.ErrNoCommit
+	}
+
+	commits := make([]*model.Commit, 0, len(pr.commits))
+	for _, v := range pr.commits {
+		commits = append(commits, v.toDomainModelCommit())
+	}
+	return commits, nil
+}
+
+// GetFirstCommit return the first commit in the pull request.
+func (r *GitRepository) GetFirstCommit(ctx context.Context, owner, repository string, number int) (*model.Commit, error) {
+	list, err := r.ListCommitsInPR(ctx, owner, repository, number)
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// ListReviews return empty list because git history does not have reviews.
+func (r *GitRepository) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	return []*model.Review{}, nil
+}
+
+// GetPullRequestSize return number of added and deleted lines and changed files between
+// the first parent of the merge commit (or squash commit) and the commit. Lines of binary
+// files are not counted. Git history does not have comments, so Comments is nil.
+func (r *GitRepository) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	r.mu.Lock()
+	pr, ok := r.pullRequests[number]
+	r.mu.Unlock()
+	if !ok || len(pr.merge.parents) == 0 {
+		return &model.PullRequestSize{}, nil
+	}
+
+	out, err := r.git(ctx, "diff", "--numstat", pr.merge.parents[0], pr.merge.hash)
+	if err != nil {
+		return nil, err
+	}
+
+	additions, deletions, files := 0, 0, 0
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		files++
+		// Binary file is "-" instead of number of lines.
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			additions += n
+		}
+		if n, err := strconv.Atoi(fields[1]); err == nil {
+			deletions += n
+		}
+	}
+
+	return &model.PullRequestSize{
+		Additions:    &additions,
+		Deletions:    &deletions,
+		ChangedFiles: &files,
+	}, nil
+}
+
+// commit is git commit in git log output.
+type commit struct {
+	hash          string
+	parents       []string
+	authorName    string
+	authoredAt    time.Time
+	committerName string
+	committedAt   time.Time
+	subject       string
+	body          string
+}
+
+// parseLogRecord parse one commit of git log. Fields are separated by 0x1f
+// in the order of hash, parents, author, author date, committer, committer date,
+// and optionally subject and body.
+func parseLogRecord(record string) (*commit, error) {
+	fields := strings.Split(strings.TrimPrefix(record, "\n"), "\x1f")
+	if len(fields) < 6 {
+		return nil, fmt.Errorf("unexpected git log output: %q", record)
+	}
+
+	authoredAt, err := time.Parse(time.RFC3339, fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected git log output: %w", err)
+	}
+	committedAt, err := time.Parse(time.RFC3339, fields[5])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected git log output: %w", err)
+	}
+
+	c := &commit{
+		hash:          fields[0],
+		parents:       strings.Fields(fields[1]),
+		authorName:    fields[2],
+		authoredAt:    authoredAt,
+		committerName: fields[4],
+		committedAt:   committedAt,
+	}
+	if len(fields) >= 8 {
+		c.subject = fields[6]
+		c.body = strings.TrimSpace(fields[7])
+	}
+	return c, nil
+}
+
+// pullRequest return pull request merged by the commit.
+// If the commit does not merge pull request, return nil.
+func (c *commit) pullRequest() *pullRequest {
+	if len(c.parents) >= 2 {
+		message := c.subject + "\n" + c.body
+		for _, pattern := range mergePatterns {
+			if m := pattern.FindStringSubmatch(message); m != nil {
+				return c.newPullRequest(m[1], mergeTitle(c))
+			}
+		}
+	}
+	if m := squashPattern.FindStringSubmatch(c.subject); m != nil {
+		return c.newPullRequest(m[1], strings.TrimSuffix(c.subject, m[0]))
+	}
+	return nil
+}
+
+func (c *commit) newPullRequest(number, title string) *pullRequest {
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return nil
+	}
+	return &pullRequest{number: n, title: title, merge: c}
+}
+
+// mergeTitle return PR title in merge commit. GitHub and GitLab write it in the
+// first line of body. If body does not have it, return subject.
+func mergeTitle(c *commit) string {
+	for _, line := range strings.Split(c.body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "See merge request ") {
+			continue
+		}
+		return line
+	}
+	return c.subject
+}
+
+func (c *commit) toDomainModelCommit() *model.Commit {
+	authorName := c.authorName
+	committerName := c.committerName
+	return &model.Commit{
+		Author:    &model.User{Name: &authorName},
+		Committer: &model.User{Name: &committerName},
+		Date:      &model.Timestamp{Time: c.committedAt},
+	}
+}
+
+// pullRequest is pull request derived from merge commit.
+type pullRequest struct {
+	number int
+	title  string
+	// merge is merge commit or squash commit.
+	merge *commit
+	// commits is commits in the pull request. order is oldest to newest.
+	commits []*commit
+}
+
+// toDomainModelPR convert pull request to *model.PullRequest. Git history does not
+// have the date when PR was created, so CreatedAt is nil. User is the author of
+// the first commit.
+func (pr *pullRequest) toDomainModelPR() *model.PullRequest {
+	id := int64(pr.number)
+	number := pr.number
+	state := "closed"
+	title := pr.title
+	mergedAt := &model.Timestamp{Time: pr.merge.committedAt}
+
+	var user *model.User
+	if len(pr.commits) != 0 {
+		name := pr.commits[0].authorName
+		user = &model.User{Name: &name}
+	}
+
+	return &model.PullRequest{
+		ID:        &id,
+		Number:    &number,
+		State:     &state,
+		Title:     &title,
+		UpdatedAt: mergedAt,
+		ClosedAt:  mergedAt,
+		MergedAt:  mergedAt,
+		User:      user,
+	}
+}
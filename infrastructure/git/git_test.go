@@ -0,0 +1,217 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"github.com/shogo82148/pointer"
+)
+
+// testRepo is git repository for test.
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git command is not installed")
+	}
+	r := &testRepo{t: t, dir: t.TempDir()}
+	r.run("2023-01-01T00:00:00Z", "alice", "init", "--quiet", "--initial-branch=main")
+	r.commit("2023-01-01T00:00:00Z", "alice", "initial commit")
+	return r
+}
+
+// run execute git command as user at date.
+func (r *testRepo) run(date, user string, args ...string) {
+	r.t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME="+user, "GIT_AUTHOR_EMAIL="+user+"@example.com", "GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME="+user, "GIT_COMMITTER_EMAIL="+user+"@example.com", "GIT_COMMITTER_DATE="+date,
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		r.t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+}
+
+// commit create empty commit as user at date.
+func (r *testRepo) commit(date, user, message string) {
+	r.t.Helper()
+	r.run(date, user, "commit", "--quiet", "--allow-empty", "-m", message)
+}
+
+func TestNewGitRepository(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewGitRepository(t.TempDir()); !errors.Is(err, ErrNotGitRepository) {
+		t.Errorf("mismatch want=%v, got=%v", ErrNotGitRepository, err)
+	}
+}
+
+func TestGitRepository_ListPullRequests(t *testing.T) {
+	t.Parallel()
+
+	r := newTestRepo(t)
+	// PR #1 is merged by merge commit.
+	r.run("2023-01-02T00:00:00Z", "alice", "checkout", "--quiet", "-b", "feature")
+	r.commit("2023-01-02T00:00:00Z", "bob", "first commit of feature")
+	r.commit("2023-01-02T01:00:00Z", "bob", "second commit of feature")
+	r.run("2023-01-02T00:00:00Z", "alice", "checkout", "--quiet", "main")
+	r.commit("2023-01-02T02:00:00Z", "alice", "commit on main")
+	r.run("2023-01-02T03:00:00Z", "alice", "merge", "--quiet", "--no-ff", "feature",
+		"-m", "Merge pull request #1 from nao1215/feature", "-m", "Add feature")
+	// Merge of local branch is not PR.
+	r.run("2023-01-03T00:00:00Z", "alice", "checkout", "--quiet", "-b", "local")
+	r.commit("2023-01-03T00:00:00Z", "alice", "local commit")
+	r.run("2023-01-03T00:00:00Z", "alice", "checkout", "--quiet", "main")
+	r.run("2023-01-03T01:00:00Z", "alice", "merge", "--quiet", "--no-ff", "local", "-m", "Merge branch 'local'")
+	// PR #2 is squashed.
+	r.run("2023-01-04T00:00:00Z", "carol", "commit", "--quiet", "--allow-empty", "-m", "Fix typo (#2)",
+		"--date", "2023-01-03T12:00:00Z")
+
+	repo, err := NewGitRepository(r.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	got, err := repo.ListPullRequests(ctx, "", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	squashedAt := &model.Timestamp{Time: time.Date(2023, 1, 4, 0, 0, 0, 0, time.UTC)}
+	mergedAt := &model.Timestamp{Time: time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)}
+	want := []*model.PullRequest{
+		{
+			ID:        pointer.Int64(2),
+			Number:    pointer.Int(2),
+			State:     pointer.String("closed"),
+			Title:     pointer.String("Fix typo"),
+			UpdatedAt: squashedAt,
+			ClosedAt:  squashedAt,
+			MergedAt:  squashedAt,
+			User:      &model.User{Name: pointer.String("carol")},
+		},
+		{
+			ID:        pointer.Int64(1),
+			Number:    pointer.Int(1),
+			State:     pointer.String("closed"),
+			Title:     pointer.String("Add feature"),
+			UpdatedAt: mergedAt,
+			ClosedAt:  mergedAt,
+			MergedAt:  mergedAt,
+			User:      &model.User{Name: pointer.String("bob")},
+		},
+	}
+	opt := cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) })
+	if diff := cmp.Diff(want, got, opt); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// The first commit is the earliest commit reachable only from the merged side.
+	commit, err := repo.GetFirstCommit(ctx, "", "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCommit := &model.Commit{
+		Author:    &model.User{Name: pointer.String("bob")},
+		Committer: &model.User{Name: pointer.String("bob")},
+		Date:      &model.Timestamp{Time: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	if diff := cmp.Diff(wantCommit, commit, opt); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// The first commit of squashed PR is the squash commit at its author date.
+	commit, err = repo.GetFirstCommit(ctx, "", "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2023, 1, 3, 12, 0, 0, 0, time.UTC); !commit.Date.Time.Equal(want) {
+		t.Errorf("mismatch want=%v, got=%v", want, commit.Date.Time)
+	}
+
+	if _, err := repo.GetFirstCommit(ctx, "", "", 3); !errors.Is(err, repository.ErrNoCommit) {
+		t.Errorf("mismatch want=%v, got=%v", repository.ErrNoCommit, err)
+	}
+
+	got, err = repo.ListPullRequests(ctx, "", "", &repository.ListPullRequestsOptions{UpdatedSince: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || *got[0].Number != 2 {
+		t.Errorf("unexpected pull requests: %+v", got)
+	}
+}
+
+func TestGitRepository_GetPullRequestSize(t *testing.T) {
+	t.Parallel()
+
+	r := newTestRepo(t)
+	r.run("2023-01-02T00:00:00Z", "alice", "checkout", "--quiet", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(r.dir, "a.txt"), []byte("a\nb\nc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, "b.txt"), []byte("b\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r.run("2023-01-02T00:00:00Z", "bob", "add", ".")
+	r.commit("2023-01-02T00:00:00Z", "bob", "add files")
+	r.run("2023-01-02T00:00:00Z", "alice", "checkout", "--quiet", "main")
+	r.run("2023-01-02T03:00:00Z", "alice", "merge", "--quiet", "--no-ff", "feature",
+		"-m", "Merge pull request #1 from nao1215/feature")
+
+	repo, err := NewGitRepository(r.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := repo.ListPullRequests(ctx, "", "", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.GetPullRequestSize(ctx, "", "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &model.PullRequestSize{
+		Additions:    pointer.Int(4),
+		Deletions:    pointer.Int(0),
+		ChangedFiles: pointer.Int(2),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGitRepository_ListRepositories(t *testing.T) {
+	t.Parallel()
+
+	r := newTestRepo(t)
+	repo, err := NewGitRepository(r.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.ListRepositories(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || *got[0].Name != filepath.Base(r.dir) {
+		t.Errorf("unexpected repositories: %+v", got)
+	}
+}
@@ -0,0 +1,15 @@
+package gitea
+
+import (
+	"errors"
+
+	"github.com/nao1215/leadtime/infrastructure/httpclient"
+)
+
+// APIError is error for Gitea API.
+type APIError = httpclient.APIError
+
+var (
+	// ErrInvalidURL means "invalid Gitea URL"
+	ErrInvalidURL = errors.New("invalid Gitea URL")
+)
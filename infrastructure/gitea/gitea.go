@@ -0,0 +1,371 @@
+// Package gitea is http client for Gitea REST API.
+// Forgejo is a fork of Gitea and has the same API, so it is also supported.
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"github.com/nao1215/leadtime/infrastructure/httpclient"
+)
+
+// pagingLimit is number of items fetched by one request.
+// Gitea limits it to 50 by default (MAX_RESPONSE_ITEMS).
+const pagingLimit = 50
+
+// Client is http client for Gitea REST API.
+// BaseURL is base URL of Gitea REST API (e.g. https://gitea.example.com/api/v1/).
+type Client struct {
+	*httpclient.Client
+}
+
+// NewClient return http client for Gitea REST API. baseURL is URL of Gitea
+// (e.g. https://gitea.example.com). If baseURL does not end with /api/v1/, it is appended.
+func NewClient(token model.Token, baseURL string) (*Client, error) {
+	client, err := httpclient.NewClient("Gitea", baseURL, "api/v1/", func(req *http.Request) {
+		req.Header.Set("Authorization", "token "+token.String())
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidURL, err.Error())
+	}
+	return &Client{Client: client}, nil
+}
+
+// get send GET request to path and decode response body to v.
+// Return whether there is next page or not.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v interface{}, message string) (bool, error) {
+	u, err := url.Parse(c.BaseURL.String() + path)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", message, err)
+	}
+	u.RawQuery = query.Encode()
+
+	header, err := c.Get(ctx, u.String(), v, message)
+	if err != nil {
+		return false, err
+	}
+	return hasNextPage(header), nil
+}
+
+// hasNextPage check whether Link header has the next page or not.
+func hasNextPage(header http.Header) bool {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		if strings.Contains(link, `rel="next"`) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoPath return API path of the repository.
+func repoPath(owner, repo string) string {
+	return "repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
+
+// GiteaRepository is http client for Gitea REST API
+type GiteaRepository struct {
+	client *Client
+}
+
+// NewGiteaRepository initialize repository.SourceRepository for Gitea.
+func NewGiteaRepository(client *Client) repository.SourceRepository {
+	return &GiteaRepository{client: client}
+}
+
+// ListRepositories return List the repositories of the organization.
+// If org is empty, return the repositories of the authenticated user.
+func (g *GiteaRepository) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	path := "user/repos"
+	if org != "" {
+		path = "orgs/" + url.PathEscape(org) + "/repos"
+	}
+	query := url.Values{"limit": {strconv.Itoa(pagingLimit)}}
+
+	repoList := make([]*model.Repository, 0)
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		repos := []*repo{}
+		next, err := g.client.get(ctx, path, query, &repos, "failed to get repository list")
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range repos {
+			repoList = append(repoList, v.toDomainModelRepository())
+		}
+		if !next {
+			break
+		}
+	}
+	return repoList, nil
+}
+
+// ListPullRequests return List the pull requests in order of creation date (newest first).
+// If opts.UpdatedSince is set, pull requests are listed in order of update time
+// and paging stops at the first pull request updated before opts.UpdatedSince.
+func (g *GiteaRepository) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	if opts == nil {
+		opts = &repository.ListPullRequestsOptions{}
+	}
+
+	query := url.Values{
+		"state": {"all"},
+		"limit": {strconv.Itoa(pagingLimit)},
+	}
+	if !opts.UpdatedSince.IsZero() {
+		query.Set("sort", "recentupdate")
+	}
+
+	pullReqs := make([]*model.PullRequest, 0)
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		prs := []*pullRequest{}
+		next, err := g.client.get(ctx, repoPath(owner, repo)+"/pulls", query, &prs, "failed to get pull request list")
+		if err != nil {
+			return nil, err
+		}
+
+		reachedOldPR := false
+		for _, v := range prs {
+			if !opts.UpdatedSince.IsZero() && v.UpdatedAt != nil && v.UpdatedAt.Before(opts.UpdatedSince) {
+				reachedOldPR = true
+				break
+			}
+			pullReqs = append(pullReqs, v.toDomainModelPR())
+		}
+		if reachedOldPR || !next {
+			break
+		}
+	}
+
+	if len(pullReqs) == 0 && opts.UpdatedSince.IsZero() {
+		return nil, repository.ErrNoPullRequest
+	}
+	return pullReqs, nil
+}
+
+// ListCommitsInPR return List the commits in the pull request.
+// order is oldest to newest.
+func (g *GiteaRepository) ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error) {
+	query := url.Values{"limit": {strconv.Itoa(pagingLimit)}}
+	path := fmt.Sprintf("%s/pulls/%d/commits", repoPath(owner, repo), number)
+
+	commits := make([]*commit, 0)
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		list := []*commit{}
+		next, err := g.client.get(ctx, path, query, &list, "failed to get commit list")
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, list...)
+		if !next {
+			break
+		}
+	}
+
+	if len(commits) == 0 {
+		return nil, repository.ErrNoCommit
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Commit.Committer.Date.Before(commits[j].Commit.Committer.Date)
+	})
+	commitsInPR := make([]*model.Commit, 0, len(commits))
+	for _, v := range commits {
+		commitsInPR = append(commitsInPR, v.toDomainModelCommit())
+	}
+	return commitsInPR, nil
+}
+
+// GetFirstCommit return the first commit in the pull request.
+func (g *GiteaRepository) GetFirstCommit(ctx context.Context, owner, repository string, number int) (*model.Commit, error) {
+	list, err := g.ListCommitsInPR(ctx, owner, repository, number)
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// ListReviews return List the reviews in the pull request. Review states are
+// converted to GitHub ones (e.g. REQUEST_CHANGES to CHANGES_REQUESTED).
+// Pending reviews and review requests are not listed.
+func (g *GiteaRepository) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	query := url.Values{"limit": {strconv.Itoa(pagingLimit)}}
+	path := fmt.Sprintf("%s/pulls/%d/reviews", repoPath(owner, repo), number)
+
+	reviews := make([]*model.Review, 0)
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		list := []*review{}
+		next, err := g.client.get(ctx, path, query, &list, "failed to get review list")
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range list {
+			if r := v.toDomainModelReview(); r != nil {
+				reviews = append(reviews, r)
+			}
+		}
+		if !next {
+			break
+		}
+	}
+	return reviews, nil
+}
+
+// GetPullRequestSize return size of the pull request. Old Gitea does not include
+// number of added and deleted lines and changed files in the pull request list,
+// so the pull request is fetched. A value that Gitea does not provide is nil.
+func (g *GiteaRepository) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	pr := &pullRequest{}
+	path := fmt.Sprintf("%s/pulls/%d", repoPath(owner, repo), number)
+	if _, err := g.client.get(ctx, path, url.Values{}, pr, "failed to get pull request"); err != nil {
+		return nil, err
+	}
+
+	return &model.PullRequestSize{
+		Additions:    pr.Additions,
+		Deletions:    pr.Deletions,
+		ChangedFiles: pr.ChangedFiles,
+		Comments:     &pr.Comments,
+	}, nil
+}
+
+// user is user in Gitea REST API.
+type user struct {
+	Login string `json:"login"`
+}
+
+func (u *user) toDomainModelUser() *model.User {
+	if u == nil {
+		return nil
+	}
+	// Gitea does not have bot account type, so Bot is always false.
+	name := u.Login
+	return &model.User{Name: &name}
+}
+
+// repo is repository in Gitea REST API.
+type repo struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+}
+
+func (r *repo) toDomainModelRepository() *model.Repository {
+	return &model.Repository{
+		ID:          &r.ID,
+		Name:        &r.Name,
+		FullName:    &r.FullName,
+		Description: &r.Description,
+	}
+}
+
+// pullRequest is pull request in Gitea REST API.
+type pullRequest struct {
+	ID           int64      `json:"id"`
+	Number       int        `json:"number"`
+	State        string     `json:"state"`
+	Title        string     `json:"title"`
+	CreatedAt    *time.Time `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+	ClosedAt     *time.Time `json:"closed_at"`
+	MergedAt     *time.Time `json:"merged_at"`
+	User         *user      `json:"user"`
+	Comments     int        `json:"comments"`
+	Additions    *int       `json:"additions"`
+	Deletions    *int       `json:"deletions"`
+	ChangedFiles *int       `json:"changed_files"`
+	Labels       []label    `json:"labels"`
+}
+
+// label is issue and pull request label in Gitea REST API.
+type label struct {
+	Name string `json:"name"`
+}
+
+// toDomainModelPR convert pull request to *model.PullRequest.
+// Gitea state is "open" or "closed" like GitHub.
+func (pr *pullRequest) toDomainModelPR() *model.PullRequest {
+	var labels []string
+	for _, v := range pr.Labels {
+		labels = append(labels, v.Name)
+	}
+
+	return &model.PullRequest{
+		ID:           &pr.ID,
+		Number:       &pr.Number,
+		State:        &pr.State,
+		Title:        &pr.Title,
+		CreatedAt:    model.NewTimestamp(pr.CreatedAt),
+		UpdatedAt:    model.NewTimestamp(pr.UpdatedAt),
+		ClosedAt:     model.NewTimestamp(pr.ClosedAt),
+		MergedAt:     model.NewTimestamp(pr.MergedAt),
+		User:         pr.User.toDomainModelUser(),
+		Comments:     &pr.Comments,
+		Additions:    pr.Additions,
+		Deletions:    pr.Deletions,
+		ChangedFiles: pr.ChangedFiles,
+		Labels:       labels,
+	}
+}
+
+// commitUser is author or committer of git commit in Gitea REST API.
+type commitUser struct {
+	Name string    `json:"name"`
+	Date time.Time `json:"date"`
+}
+
+// commit is git commit in Gitea REST API.
+type commit struct {
+	Commit struct {
+		Author    commitUser `json:"author"`
+		Committer commitUser `json:"committer"`
+	} `json:"commit"`
+}
+
+func (c *commit) toDomainModelCommit() *model.Commit {
+	return &model.Commit{
+		Author:    &model.User{Name: &c.Commit.Author.Name},
+		Committer: &model.User{Name: &c.Commit.Committer.Name},
+		Date:      &model.Timestamp{Time: c.Commit.Committer.Date},
+	}
+}
+
+// review is pull request review in Gitea REST API.
+type review struct {
+	State       string     `json:"state"`
+	SubmittedAt *time.Time `json:"submitted_at"`
+	User        *user      `json:"user"`
+}
+
+// toDomainModelReview convert review to *model.Review.
+// Return nil for pending review and review request.
+func (r *review) toDomainModelReview() *model.Review {
+	var state string
+	switch r.State {
+	case "APPROVED":
+		state = "APPROVED"
+	case "COMMENT":
+		state = "COMMENTED"
+	case "REQUEST_CHANGES":
+		state = "CHANGES_REQUESTED"
+	default:
+		return nil
+	}
+
+	return &model.Review{
+		User:        r.User.toDomainModelUser(),
+		State:       &state,
+		SubmittedAt: model.NewTimestamp(r.SubmittedAt),
+	}
+}
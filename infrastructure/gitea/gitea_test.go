@@ -0,0 +1,321 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"github.com/shogo82148/pointer"
+)
+
+// newTestRepository return GiteaRepository that sends requests to handler.
+func newTestRepository(t *testing.T, handler http.Handler) repository.SourceRepository {
+	t.Helper()
+
+	testServer := httptest.NewServer(handler)
+	t.Cleanup(testServer.Close)
+
+	client, err := NewClient(model.Token("test_token"), testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGiteaRepository(client)
+}
+
+func write(t *testing.T, w http.ResponseWriter, body string) {
+	t.Helper()
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+	}{
+		{name: "gitea.com", baseURL: "https://gitea.com", want: "https://gitea.com/api/v1/"},
+		{name: "API path", baseURL: "https://gitea.example.com/api/v1", want: "https://gitea.example.com/api/v1/"},
+		{name: "Sub path", baseURL: "https://example.com/gitea/", want: "https://example.com/gitea/api/v1/"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := NewClient(model.Token("token"), tt.baseURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := client.BaseURL.String(); got != tt.want {
+				t.Errorf("mismatch want=%s, got=%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGiteaRepository_ListPullRequests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Get pull requests over pages", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/repos/owner/repo/pulls" {
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+			if r.Header.Get("Authorization") != "token test_token" {
+				t.Errorf("unexpected token: %s", r.Header.Get("Authorization"))
+			}
+			if r.URL.Query().Get("state") != "all" {
+				t.Errorf("unexpected state: %s", r.URL.Query().Get("state"))
+			}
+
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("Link", `<http://example.com/api/v1/repos/owner/repo/pulls?page=2>; rel="next",<http://example.com/api/v1/repos/owner/repo/pulls?page=2>; rel="last"`)
+				write(t, w, `[{"id":12,"number":2,"state":"closed","title":"pr2","created_at":"2023-01-02T00:00:00Z",
+"updated_at":"2023-01-02T03:00:00Z","closed_at":"2023-01-02T02:00:00Z","merged_at":"2023-01-02T02:00:00Z",
+"user":{"login":"alice"},"comments":1,"additions":10,"deletions":2,"changed_files":3,"labels":[{"name":"bug"}]}]`)
+				return
+			}
+			write(t, w, `[{"id":11,"number":1,"state":"open","title":"pr1","created_at":"2023-01-01T00:00:00Z",
+"updated_at":"2023-01-01T00:00:00Z","user":{"login":"bob"},"comments":0}]`)
+		}))
+
+		got, err := repo.ListPullRequests(context.Background(), "owner", "repo", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		merged := &model.Timestamp{Time: time.Date(2023, 1, 2, 2, 0, 0, 0, time.UTC)}
+		want := []*model.PullRequest{
+			{
+				ID:           pointer.Int64(12),
+				Number:       pointer.Int(2),
+				State:        pointer.String("closed"),
+				Title:        pointer.String("pr2"),
+				CreatedAt:    &model.Timestamp{Time: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+				UpdatedAt:    &model.Timestamp{Time: time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)},
+				ClosedAt:     merged,
+				MergedAt:     merged,
+				User:         &model.User{Name: pointer.String("alice")},
+				Comments:     pointer.Int(1),
+				Additions:    pointer.Int(10),
+				Deletions:    pointer.Int(2),
+				ChangedFiles: pointer.Int(3),
+				Labels:       []string{"bug"},
+			},
+			{
+				ID:        pointer.Int64(11),
+				Number:    pointer.Int(1),
+				State:     pointer.String("open"),
+				Title:     pointer.String("pr1"),
+				CreatedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+				UpdatedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+				User:      &model.User{Name: pointer.String("bob")},
+				Comments:  pointer.Int(0),
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Stop paging at PR updated before UpdatedSince", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("sort"); got != "recentupdate" {
+				t.Errorf("unexpected sort: %s", got)
+			}
+			if r.URL.Query().Get("page") != "1" {
+				t.Errorf("unexpected page: %s", r.URL.Query().Get("page"))
+			}
+			w.Header().Set("Link", `<http://example.com/api/v1/repos/owner/repo/pulls?page=2>; rel="next"`)
+			write(t, w, `[{"id":12,"number":2,"state":"open","updated_at":"2023-01-02T00:00:00Z"},
+{"id":11,"number":1,"state":"closed","updated_at":"2022-12-01T00:00:00Z"}]`)
+		}))
+
+		got, err := repo.ListPullRequests(context.Background(), "owner", "repo",
+			&repository.ListPullRequestsOptions{UpdatedSince: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || *got[0].Number != 2 {
+			t.Errorf("unexpected pull requests: %+v", got)
+		}
+	})
+
+	t.Run("No pull request", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			write(t, w, `[]`)
+		}))
+
+		if _, err := repo.ListPullRequests(context.Background(), "owner", "repo", nil); !errors.Is(err, repository.ErrNoPullRequest) {
+			t.Errorf("mismatch want=%v, got=%v", repository.ErrNoPullRequest, err)
+		}
+	})
+
+	t.Run("Return status code 404 from Gitea", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			if _, err := w.Write([]byte(`{"message":"The target couldn't be found."}`)); err != nil {
+				t.Fatal(err)
+			}
+		}))
+
+		_, err := repo.ListPullRequests(context.Background(), "owner", "repo", nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("mismatch expect=%T, got=%T", &APIError{}, err)
+		}
+		if apiErr.StatusCode != http.StatusNotFound {
+			t.Errorf("mismatch expect=%d, got=%d", http.StatusNotFound, apiErr.StatusCode)
+		}
+	})
+}
+
+func TestGiteaRepository_GetPullRequestSize(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/owner/repo/pulls/3" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		write(t, w, `{"id":13,"number":3,"state":"closed","comments":2,"additions":10,"deletions":2,"changed_files":3}`)
+	}))
+
+	got, err := repo.GetPullRequestSize(context.Background(), "owner", "repo", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &model.PullRequestSize{
+		Additions:    pointer.Int(10),
+		Deletions:    pointer.Int(2),
+		ChangedFiles: pointer.Int(3),
+		Comments:     pointer.Int(2),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGiteaRepository_GetFirstCommit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Return the oldest commit", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/repos/owner/repo/pulls/3/commits" {
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+			write(t, w, `[
+{"sha":"b","commit":{"author":{"name":"alice","date":"2023-01-03T00:00:00Z"},"committer":{"name":"alice","date":"2023-01-03T00:00:00Z"}}},
+{"sha":"a","commit":{"author":{"name":"bob","date":"2023-01-01T00:00:00Z"},"committer":{"name":"bob","date":"2023-01-01T00:00:00Z"}}}]`)
+		}))
+
+		got, err := repo.GetFirstCommit(context.Background(), "owner", "repo", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &model.Commit{
+			Author:    &model.User{Name: pointer.String("bob")},
+			Committer: &model.User{Name: pointer.String("bob")},
+			Date:      &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("No commit", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			write(t, w, `[]`)
+		}))
+
+		if _, err := repo.GetFirstCommit(context.Background(), "owner", "repo", 3); !errors.Is(err, repository.ErrNoCommit) {
+			t.Errorf("mismatch want=%v, got=%v", repository.ErrNoCommit, err)
+		}
+	})
+}
+
+func TestGiteaRepository_ListReviews(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		write(t, w, `[
+{"state":"REQUEST_REVIEW","submitted_at":"2023-01-01T00:00:00Z","user":{"login":"alice"}},
+{"state":"COMMENT","submitted_at":"2023-01-01T01:00:00Z","user":{"login":"bob"}},
+{"state":"REQUEST_CHANGES","submitted_at":"2023-01-01T02:00:00Z","user":{"login":"bob"}},
+{"state":"PENDING","user":{"login":"carol"}},
+{"state":"APPROVED","submitted_at":"2023-01-01T03:00:00Z","user":{"login":"bob"}}]`)
+	}))
+
+	got, err := repo.ListReviews(context.Background(), "owner", "repo", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*model.Review{
+		{
+			User:        &model.User{Name: pointer.String("bob")},
+			State:       pointer.String("COMMENTED"),
+			SubmittedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC)},
+		},
+		{
+			User:        &model.User{Name: pointer.String("bob")},
+			State:       pointer.String("CHANGES_REQUESTED"),
+			SubmittedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC)},
+		},
+		{
+			User:        &model.User{Name: pointer.String("bob")},
+			State:       pointer.String("APPROVED"),
+			SubmittedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 3, 0, 0, 0, time.UTC)},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGiteaRepository_ListRepositories(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/orgs/myorg/repos" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		write(t, w, `[{"id":1,"name":"repo","full_name":"myorg/repo","description":"desc"}]`)
+	}))
+
+	got, err := repo.ListRepositories(context.Background(), "myorg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*model.Repository{{
+		ID:          pointer.Int64(1),
+		Name:        pointer.String("repo"),
+		FullName:    pointer.String("myorg/repo"),
+		Description: pointer.String("desc"),
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
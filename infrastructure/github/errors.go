@@ -3,6 +3,9 @@ package github
 import (
 	"errors"
 	"fmt"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/nao1215/leadtime/domain/repository"
 )
 
 // APIError is error for GitHub API.
@@ -18,9 +21,26 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("GitHub API error: status code %d, message: %s", e.StatusCode, e.Message)
 }
 
+// newAPIError return error for failed GitHub API call.
+// If there is no HTTP response (e.g. network error, context canceled), err is wrapped as it is.
+func newAPIError(resp *github.Response, err error, message string) error {
+	if resp == nil || resp.Response == nil {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+
+	var rateLimitErr *github.RateLimitError
+	var abuseRateLimitErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) {
+		message += " (rate limit exceeded)"
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: message}
+}
+
 var (
 	// ErrNoPullRequest means "there is no pull request in this repository"
-	ErrNoPullRequest = errors.New("there is no pull request in this repository")
+	ErrNoPullRequest = repository.ErrNoPullRequest
 	// ErrNoCommit means "there is no commit in this repository"
-	ErrNoCommit = errors.New("there is no commit in this repository")
+	ErrNoCommit = repository.ErrNoCommit
+	// ErrInvalidAPIURL means "invalid GitHub API URL"
+	ErrInvalidAPIURL = errors.New("invalid GitHub API URL")
 )
@@ -1,8 +1,12 @@
 package github
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"testing"
+
+	"github.com/google/go-github/v50/github"
 )
 
 func TestAPIError_Error(t *testing.T) {
@@ -41,3 +45,28 @@ func TestAPIError_Error(t *testing.T) {
 		})
 	}
 }
+
+func Test_newAPIError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Wrap error when there is no response", func(t *testing.T) {
+		t.Parallel()
+
+		got := newAPIError(nil, context.Canceled, "failed to get review list")
+		if !errors.Is(got, context.Canceled) {
+			t.Errorf("mismatch want=%v, got=%v", context.Canceled, got)
+		}
+	})
+
+	t.Run("Mark rate limit error", func(t *testing.T) {
+		t.Parallel()
+
+		resp := &github.Response{Response: &http.Response{StatusCode: http.StatusForbidden}}
+		got := newAPIError(resp, &github.RateLimitError{Response: resp.Response}, "failed to get review list")
+
+		want := "GitHub API error: status code 403, message: failed to get review list (rate limit exceeded)"
+		if got.Error() != want {
+			t.Errorf("mismatch want=%s, got=%s", want, got.Error())
+		}
+	})
+}
@@ -5,6 +5,8 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/google/go-github/v50/github"
 	"github.com/nao1215/leadtime/domain/model"
@@ -18,13 +20,40 @@ type Client struct {
 }
 
 // NewClient return http client for GitHub API.
-func NewClient(token model.Token) *Client {
+// The client waits and retries requests that exceed the GitHub API rate limit
+// according to policy. If policy is nil, DefaultRetryPolicy is used.
+func NewClient(token model.Token, policy *RetryPolicy) *Client {
+	return &Client{Client: github.NewClient(newHTTPClient(token, policy))}
+}
+
+// NewEnterpriseClient return http client for GitHub Enterprise Server API.
+// apiURL is the API base URL (e.g. https://github.example.com/api/v3/). If apiURL
+// does not end with /api/v3/, it is appended. The upload URL is derived from apiURL.
+func NewEnterpriseClient(token model.Token, policy *RetryPolicy, apiURL string) (*Client, error) {
+	client, err := github.NewEnterpriseClient(apiURL, uploadURL(apiURL), newHTTPClient(token, policy))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAPIURL, err.Error())
+	}
+	return &Client{Client: client}, nil
+}
+
+// newHTTPClient return http client that authenticates with token and retries
+// requests exceeding the rate limit.
+func newHTTPClient(token model.Token, policy *RetryPolicy) *http.Client {
 	tokenSource := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token.String()},
 	)
 	client := oauth2.NewClient(context.Background(), tokenSource)
+	client.Transport = newRateLimitTransport(client.Transport, policy)
+	return client
+}
 
-	return &Client{Client: github.NewClient(client)}
+// uploadURL return upload URL of GitHub Enterprise Server whose API base URL is apiURL.
+// GitHub Enterprise Server serves API at /api/v3/ and uploads at /api/uploads/.
+func uploadURL(apiURL string) string {
+	u := strings.TrimSuffix(apiURL, "/")
+	u = strings.TrimSuffix(u, "/api/v3")
+	return u + "/api/uploads/"
 }
 
 // GitHubRepository is http client for GitHub API
@@ -37,57 +66,89 @@ func NewGitHubRepository(client *Client) repository.GitHubRepository {
 	return &GitHubRepository{client: client}
 }
 
-// ListRepositories return List the repositories for a user.
-func (c *GitHubRepository) ListRepositories(ctx context.Context) ([]*model.Repository, error) {
-	repos, resp, err := c.client.Repositories.List(ctx, "", nil)
-	if resp != nil {
-		defer func() error {
-			if err := resp.Body.Close(); err != nil {
-				return fmt.Errorf("failed to close response body: %w", err)
-			}
-
-			return nil
-		}()
-	}
-	if err != nil {
-		return nil, &APIError{StatusCode: resp.StatusCode, Message: "failed to get repository list"}
-	}
+// ListRepositories return List the repositories of the organization.
+// If org is empty, return the repositories of the authenticated user.
+func (c *GitHubRepository) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	const pagingLimit = 100
 
+	listOpts := github.ListOptions{PerPage: pagingLimit}
 	repoList := make([]*model.Repository, 0)
-	for _, v := range repos {
-		var user *model.User
-		if v.Owner != nil {
-			user = &model.User{
-				Name: v.Owner.Name,
-				Bot:  (v.Owner.GetType() == "Bot"),
-			}
+	for {
+		var (
+			repos []*github.Repository
+			resp  *github.Response
+			err   error
+		)
+		if org == "" {
+			repos, resp, err = c.client.Repositories.List(ctx, "", &github.RepositoryListOptions{ListOptions: listOpts})
+		} else {
+			repos, resp, err = c.client.Repositories.ListByOrg(ctx, org, &github.RepositoryListByOrgOptions{ListOptions: listOpts})
 		}
+		if resp != nil {
+			defer func() error {
+				if err := resp.Body.Close(); err != nil {
+					return fmt.Errorf("failed to close response body: %w", err)
+				}
 
-		repo := &model.Repository{
-			ID:          v.ID,
-			Owner:       user,
-			Name:        v.Name,
-			FullName:    v.FullName,
-			Description: v.Description,
+				return nil
+			}()
 		}
-		repoList = append(repoList, repo)
+		if err != nil {
+			return nil, newAPIError(resp, err, "failed to get repository list")
+		}
+
+		for _, v := range repos {
+			repoList = append(repoList, toDomainModelRepository(v))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
 	}
 
 	return repoList, nil
 }
 
+func toDomainModelRepository(repo *github.Repository) *model.Repository {
+	var user *model.User
+	if repo.Owner != nil {
+		user = &model.User{
+			Name: repo.Owner.Name,
+			Bot:  (repo.Owner.GetType() == "Bot"),
+		}
+	}
+
+	return &model.Repository{
+		ID:          repo.ID,
+		Owner:       user,
+		Name:        repo.Name,
+		FullName:    repo.FullName,
+		Description: repo.Description,
+	}
+}
+
 // ListPullRequests return List the pull requests.
-func (c *GitHubRepository) ListPullRequests(ctx context.Context, owner, repo string) ([]*model.PullRequest, error) {
+// If opts.UpdatedSince is set, pull requests are listed in order of update time
+// and paging stops at the first pull request updated before opts.UpdatedSince.
+func (c *GitHubRepository) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
 	const pagingLimit = 20
 
+	if opts == nil {
+		opts = &repository.ListPullRequestsOptions{}
+	}
+
 	pullReqs := make([]*model.PullRequest, 0)
-	opts := &github.PullRequestListOptions{
+	listOpts := &github.PullRequestListOptions{
 		State:       "all",
 		ListOptions: github.ListOptions{PerPage: pagingLimit},
 	}
+	if !opts.UpdatedSince.IsZero() {
+		listOpts.Sort = "updated"
+		listOpts.Direction = "desc"
+	}
 
 	for {
-		prs, resp, err := c.client.PullRequests.List(ctx, owner, repo, opts)
+		prs, resp, err := c.client.PullRequests.List(ctx, owner, repo, listOpts)
 		if resp != nil {
 			defer func() error {
 				if err := resp.Body.Close(); err != nil {
@@ -98,20 +159,25 @@ func (c *GitHubRepository) ListPullRequests(ctx context.Context, owner, repo str
 			}()
 		}
 		if err != nil {
-			return nil, &APIError{StatusCode: resp.StatusCode, Message: "failed to get pull request list"}
+			return nil, newAPIError(resp, err, "failed to get pull request list")
 		}
 
+		reachedOldPR := false
 		for _, v := range prs {
+			if !opts.UpdatedSince.IsZero() && v.UpdatedAt != nil && v.UpdatedAt.Before(opts.UpdatedSince) {
+				reachedOldPR = true
+				break
+			}
 			pullReqs = append(pullReqs, toDomainModelPR(v))
 		}
 
-		if resp.NextPage == 0 {
+		if reachedOldPR || resp.NextPage == 0 {
 			break
 		}
-		opts.ListOptions.Page = resp.NextPage
+		listOpts.ListOptions.Page = resp.NextPage
 	}
 
-	if len(pullReqs) == 0 {
+	if len(pullReqs) == 0 && opts.UpdatedSince.IsZero() {
 		return nil, ErrNoPullRequest
 	}
 
@@ -138,7 +204,7 @@ func (c *GitHubRepository) ListCommitsInPR(ctx context.Context, owner, repo stri
 			}()
 		}
 		if err != nil {
-			return nil, &APIError{StatusCode: resp.StatusCode, Message: "failed to get git commit list"}
+			return nil, newAPIError(resp, err, "failed to get git commit list")
 		}
 
 		for _, v := range commits {
@@ -167,12 +233,79 @@ func (c *GitHubRepository) GetFirstCommit(ctx context.Context, owner, repository
 	return list[0], nil
 }
 
+// ListReviews return List the reviews in the PR.
+// order is oldest to newest.
+func (c *GitHubRepository) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	const pagingLimit = 20
+
+	opts := &github.ListOptions{PerPage: pagingLimit}
+
+	reviewsInPR := make([]*model.Review, 0)
+	for {
+		reviews, resp, err := c.client.PullRequests.ListReviews(ctx, owner, repo, number, opts)
+		if resp != nil {
+			defer func() error {
+				if err := resp.Body.Close(); err != nil {
+					return fmt.Errorf("failed to close response body: %w", err)
+				}
+
+				return nil
+			}()
+		}
+		if err != nil {
+			return nil, newAPIError(resp, err, "failed to get review list")
+		}
+
+		for _, v := range reviews {
+			reviewsInPR = append(reviewsInPR, toDomainModelReview(v))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return reviewsInPR, nil
+}
+
+// GetPullRequestSize return size of the PR. The pull request list API does not
+// include number of added and deleted lines, changed files and comments, so the PR is fetched.
+func (c *GitHubRepository) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	pr, resp, err := c.client.PullRequests.Get(ctx, owner, repo, number)
+	if resp != nil {
+		defer func() error {
+			if err := resp.Body.Close(); err != nil {
+				return fmt.Errorf("failed to close response body: %w", err)
+			}
+
+			return nil
+		}()
+	}
+	if err != nil {
+		return nil, newAPIError(resp, err, "failed to get pull request")
+	}
+
+	return &model.PullRequestSize{
+		Additions:    pr.Additions,
+		Deletions:    pr.Deletions,
+		ChangedFiles: pr.ChangedFiles,
+		Comments:     pr.Comments,
+	}, nil
+}
+
 // toDomainModelPR convert *github.PullRequest to *model.PullRequest
 func toDomainModelPR(githubPR *github.PullRequest) *model.PullRequest {
 	var createdAt *model.Timestamp
-	if githubPR.ClosedAt != nil {
+	if githubPR.CreatedAt != nil {
 		createdAt = &model.Timestamp{
-			Time: githubPR.ClosedAt.Time,
+			Time: githubPR.CreatedAt.Time,
+		}
+	}
+
+	var updatedAt *model.Timestamp
+	if githubPR.UpdatedAt != nil {
+		updatedAt = &model.Timestamp{
+			Time: githubPR.UpdatedAt.Time,
 		}
 	}
 
@@ -190,6 +323,11 @@ func toDomainModelPR(githubPR *github.PullRequest) *model.PullRequest {
 		}
 	}
 
+	var labels []string
+	for _, v := range githubPR.Labels {
+		labels = append(labels, v.GetName())
+	}
+
 	var user *model.User
 	if githubPR.User != nil {
 		user = &model.User{
@@ -204,6 +342,7 @@ func toDomainModelPR(githubPR *github.PullRequest) *model.PullRequest {
 		State:        githubPR.State,
 		Title:        githubPR.Title,
 		CreatedAt:    createdAt,
+		UpdatedAt:    updatedAt,
 		ClosedAt:     closedAt,
 		MergedAt:     mergedAt,
 		User:         user,
@@ -211,6 +350,7 @@ func toDomainModelPR(githubPR *github.PullRequest) *model.PullRequest {
 		Additions:    githubPR.Additions,
 		Deletions:    githubPR.Deletions,
 		ChangedFiles: githubPR.ChangedFiles,
+		Labels:       labels,
 	}
 
 	return pr
@@ -249,3 +389,27 @@ func toDomainModelCommit(commit *github.RepositoryCommit) *model.Commit {
 
 	return domainModelCommit
 }
+
+// toDomainModelReview convert *github.PullRequestReview to *model.Review.
+func toDomainModelReview(review *github.PullRequestReview) *model.Review {
+	var user *model.User
+	if review.User != nil {
+		user = &model.User{
+			Name: github.String(review.GetUser().GetLogin()),
+			Bot:  (review.User.GetType() == "Bot"),
+		}
+	}
+
+	var submittedAt *model.Timestamp
+	if review.SubmittedAt != nil {
+		submittedAt = &model.Timestamp{
+			Time: review.SubmittedAt.Time,
+		}
+	}
+
+	return &model.Review{
+		User:        user,
+		State:       review.State,
+		SubmittedAt: submittedAt,
+	}
+}
@@ -7,13 +7,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-github/v50/github"
 	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
 )
 
 func TestListRepositories(t *testing.T) {
@@ -23,7 +26,7 @@ func TestListRepositories(t *testing.T) {
 		t.Parallel()
 
 		token := model.Token("good_token")
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -81,7 +84,62 @@ func TestListRepositories(t *testing.T) {
 			},
 		}
 		// test start
-		got, err := repo.ListRepositories(ctx)
+		got, err := repo.ListRepositories(ctx, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Get organization repository list over pages", func(t *testing.T) {
+		t.Parallel()
+
+		token := model.Token("good_token")
+		client := NewClient(token, nil)
+		repo := NewGitHubRepository(client)
+		ctx := context.Background()
+
+		// Test server
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/orgs/myorg/repos" {
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+
+			repos := []github.Repository{{ID: github.Int64(1), Name: github.String("repo1"), FullName: github.String("myorg/repo1")}}
+			if r.URL.Query().Get("page") == "2" {
+				repos = []github.Repository{{ID: github.Int64(2), Name: github.String("repo2"), FullName: github.String("myorg/repo2")}}
+			} else {
+				w.Header().Set("Link", `<`+"http://"+r.Host+r.URL.Path+`?page=2>; rel="next"`)
+			}
+			respBody, err := json.Marshal(repos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.WriteHeader(http.StatusOK)
+			if _, err := w.Write(respBody); err != nil {
+				t.Fatal(err)
+			}
+		}))
+		defer testServer.Close()
+
+		testURL, err := url.Parse(testServer.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.BaseURL = testURL
+		if !strings.HasSuffix(client.BaseURL.Path, "/") {
+			client.BaseURL.Path += "/"
+		}
+
+		want := []*model.Repository{
+			{ID: github.Int64(1), Name: github.String("repo1"), FullName: github.String("myorg/repo1")},
+			{ID: github.Int64(2), Name: github.String("repo2"), FullName: github.String("myorg/repo2")},
+		}
+		// test start
+		got, err := repo.ListRepositories(ctx, "myorg")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -95,7 +153,7 @@ func TestListRepositories(t *testing.T) {
 		t.Parallel()
 
 		token := model.Token("test_token")
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -118,7 +176,7 @@ func TestListRepositories(t *testing.T) {
 		}
 
 		// test start
-		_, err = repo.ListRepositories(ctx)
+		_, err = repo.ListRepositories(ctx, "")
 		if err == nil {
 			t.Fatal("expect error occurred, however got nil")
 		}
@@ -138,7 +196,7 @@ func TestListRepositories(t *testing.T) {
 		t.Parallel()
 
 		token := model.Token("bad_token")
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -173,7 +231,7 @@ func TestListRepositories(t *testing.T) {
 		}
 
 		// test start
-		_, err = repo.ListRepositories(ctx)
+		_, err = repo.ListRepositories(ctx, "")
 		if err == nil {
 			t.Fatal("expect error occurred, however got nil")
 		}
@@ -214,16 +272,19 @@ func TestClient_ListPullRequests(t *testing.T) {
 				{
 					ID:     github.Int64(1),
 					Number: github.Int(1),
-					State:  github.String("open"),
+					State:  github.String("closed"),
 					Title:  github.String("test_pr1"),
 					CreatedAt: &github.Timestamp{
 						Time: now,
 					},
+					UpdatedAt: &github.Timestamp{
+						Time: now.Add(3 * time.Hour),
+					},
 					ClosedAt: &github.Timestamp{
-						Time: now,
+						Time: now.Add(2 * time.Hour),
 					},
 					MergedAt: &github.Timestamp{
-						Time: now,
+						Time: now.Add(2 * time.Hour),
 					},
 					User: &github.User{
 						Login: github.String("test_user1"),
@@ -267,7 +328,7 @@ func TestClient_ListPullRequests(t *testing.T) {
 		defer testServer.Close()
 
 		token := model.Token("token")
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -284,11 +345,12 @@ func TestClient_ListPullRequests(t *testing.T) {
 			{
 				ID:           github.Int64(1),
 				Number:       github.Int(1),
-				State:        github.String("open"),
+				State:        github.String("closed"),
 				Title:        github.String("test_pr1"),
 				CreatedAt:    &model.Timestamp{Time: now},
-				ClosedAt:     &model.Timestamp{Time: now},
-				MergedAt:     &model.Timestamp{Time: now},
+				UpdatedAt:    &model.Timestamp{Time: now.Add(3 * time.Hour)},
+				ClosedAt:     &model.Timestamp{Time: now.Add(2 * time.Hour)},
+				MergedAt:     &model.Timestamp{Time: now.Add(2 * time.Hour)},
 				User:         &model.User{Name: github.String("test_user1")},
 				Comments:     github.Int(0),
 				Additions:    github.Int(10),
@@ -310,7 +372,7 @@ func TestClient_ListPullRequests(t *testing.T) {
 				ChangedFiles: github.Int(1),
 			},
 		}
-		gotPRs, err := repo.ListPullRequests(ctx, "owner", "repo")
+		gotPRs, err := repo.ListPullRequests(ctx, "owner", "repo", nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -345,7 +407,7 @@ func TestClient_ListPullRequests(t *testing.T) {
 		defer testServer.Close()
 
 		token := model.Token("token")
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -359,7 +421,7 @@ func TestClient_ListPullRequests(t *testing.T) {
 		}
 
 		want := ErrNoPullRequest
-		_, got := repo.ListPullRequests(ctx, "owner", "repo")
+		_, got := repo.ListPullRequests(ctx, "owner", "repo", nil)
 		if !errors.Is(got, want) {
 			t.Errorf("mismatch want=%v, got=%v", want, got)
 		}
@@ -369,7 +431,7 @@ func TestClient_ListPullRequests(t *testing.T) {
 		t.Parallel()
 
 		token := model.Token("test_token")
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -401,7 +463,7 @@ func TestClient_ListPullRequests(t *testing.T) {
 		}
 
 		// test start
-		_, err = repo.ListPullRequests(ctx, "owner", "repo")
+		_, err = repo.ListPullRequests(ctx, "owner", "repo", nil)
 		if err == nil {
 			t.Fatal("expect error occurred, however got nil")
 		}
@@ -421,7 +483,7 @@ func TestClient_ListPullRequests(t *testing.T) {
 		t.Parallel()
 
 		token := model.Token("bad_token")
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -456,7 +518,7 @@ func TestClient_ListPullRequests(t *testing.T) {
 		}
 
 		// test start
-		_, err = repo.ListPullRequests(ctx, "owner", "repo")
+		_, err = repo.ListPullRequests(ctx, "owner", "repo", nil)
 		if err == nil {
 			t.Fatal("expect error occurred, however got nil")
 		}
@@ -534,7 +596,7 @@ func TestClient_ListCommitsInPR(t *testing.T) {
 		}))
 		defer testServer.Close()
 
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -588,7 +650,7 @@ func TestClient_ListCommitsInPR(t *testing.T) {
 		}))
 		defer testServer.Close()
 
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -641,7 +703,7 @@ func TestClient_ListCommitsInPR(t *testing.T) {
 		}))
 		defer testServer.Close()
 
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -732,7 +794,7 @@ func TestGitHubRepository_GetFirstCommit(t *testing.T) {
 		}))
 		defer testServer.Close()
 
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -784,7 +846,7 @@ func TestGitHubRepository_GetFirstCommit(t *testing.T) {
 		}))
 		defer testServer.Close()
 
-		client := NewClient(token)
+		client := NewClient(token, nil)
 		repo := NewGitHubRepository(client)
 		ctx := context.Background()
 
@@ -849,16 +911,19 @@ func Test_toDomainModelPR(t *testing.T) {
 			githubPR: &github.PullRequest{
 				ID:     github.Int64(1),
 				Number: github.Int(1),
-				State:  github.String("open"),
+				State:  github.String("closed"),
 				Title:  github.String("test_pr1"),
 				CreatedAt: &github.Timestamp{
 					Time: now,
 				},
+				UpdatedAt: &github.Timestamp{
+					Time: now.Add(3 * time.Hour),
+				},
 				ClosedAt: &github.Timestamp{
-					Time: now,
+					Time: now.Add(2 * time.Hour),
 				},
 				MergedAt: &github.Timestamp{
-					Time: now,
+					Time: now.Add(2 * time.Hour),
 				},
 				User: &github.User{
 					Login: github.String("test_user1"),
@@ -871,11 +936,12 @@ func Test_toDomainModelPR(t *testing.T) {
 			want: &model.PullRequest{
 				ID:           github.Int64(1),
 				Number:       github.Int(1),
-				State:        github.String("open"),
+				State:        github.String("closed"),
 				Title:        github.String("test_pr1"),
 				CreatedAt:    &model.Timestamp{Time: now},
-				ClosedAt:     &model.Timestamp{Time: now},
-				MergedAt:     &model.Timestamp{Time: now},
+				UpdatedAt:    &model.Timestamp{Time: now.Add(3 * time.Hour)},
+				ClosedAt:     &model.Timestamp{Time: now.Add(2 * time.Hour)},
+				MergedAt:     &model.Timestamp{Time: now.Add(2 * time.Hour)},
 				User:         &model.User{Name: github.String("test_user1")},
 				Comments:     github.Int(0),
 				Additions:    github.Int(10),
@@ -883,6 +949,33 @@ func Test_toDomainModelPR(t *testing.T) {
 				ChangedFiles: github.Int(2),
 			},
 		},
+		{
+			name: "convert open PR that has only creation date",
+			githubPR: &github.PullRequest{
+				Number:    github.Int(2),
+				State:     github.String("open"),
+				CreatedAt: &github.Timestamp{Time: now},
+			},
+			want: &model.PullRequest{
+				Number:    github.Int(2),
+				State:     github.String("open"),
+				CreatedAt: &model.Timestamp{Time: now},
+			},
+		},
+		{
+			name: "convert PR with labels",
+			githubPR: &github.PullRequest{
+				Number: github.Int(1),
+				Labels: []*github.Label{
+					{Name: github.String("bug")},
+					{Name: github.String("dependencies")},
+				},
+			},
+			want: &model.PullRequest{
+				Number: github.Int(1),
+				Labels: []string{"bug", "dependencies"},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -946,3 +1039,543 @@ func Test_toDomainModelCommit(t *testing.T) {
 		})
 	}
 }
+
+func TestGitHubRepository_ListReviews(t *testing.T) {
+	t.Parallel()
+
+	const (
+		apiURL = "/repos/owner/repo/pulls/123/reviews"
+		token  = "token"
+	)
+	now := time.Date(2023, 2, 24, 12, 34, 56, 0, time.UTC)
+
+	t.Run("Get all review in the PR", func(t *testing.T) {
+		t.Parallel()
+
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			wantURL := apiURL
+			if wantURL != req.URL.Path {
+				t.Errorf("mismatch want=%v, got=%s", wantURL, req.URL.Path)
+			}
+
+			wantHTTPMethod := http.MethodGet
+			if wantHTTPMethod != req.Method {
+				t.Errorf("mismatch want=%v, got=%s", wantHTTPMethod, req.Method)
+			}
+
+			respBody, err := json.Marshal([]github.PullRequestReview{
+				{
+					User:        &github.User{Login: github.String("reviewer1")},
+					State:       github.String("COMMENTED"),
+					SubmittedAt: &github.Timestamp{Time: now},
+				},
+				{
+					User:        &github.User{Login: github.String("reviewer2")},
+					State:       github.String("APPROVED"),
+					SubmittedAt: &github.Timestamp{Time: now.Add(time.Hour)},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.WriteHeader(http.StatusOK)
+			if _, err := w.Write(respBody); err != nil {
+				t.Fatal(err)
+			}
+		}))
+		defer testServer.Close()
+
+		client := NewClient(token, nil)
+		repo := NewGitHubRepository(client)
+		ctx := context.Background()
+
+		testURL, err := url.Parse(testServer.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.BaseURL = testURL
+		if !strings.HasSuffix(client.BaseURL.Path, "/") {
+			client.BaseURL.Path += "/"
+		}
+
+		want := []*model.Review{
+			{
+				User:        &model.User{Name: github.String("reviewer1")},
+				State:       github.String("COMMENTED"),
+				SubmittedAt: &model.Timestamp{Time: now},
+			},
+			{
+				User:        &model.User{Name: github.String("reviewer2")},
+				State:       github.String("APPROVED"),
+				SubmittedAt: &model.Timestamp{Time: now.Add(time.Hour)},
+			},
+		}
+		got, err := repo.ListReviews(ctx, "owner", "repo", 123)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Return status code 500 from GitHub", func(t *testing.T) {
+		t.Parallel()
+
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			if _, err := w.Write([]byte("error message")); err != nil {
+				t.Fatal(err)
+			}
+		}))
+		defer testServer.Close()
+
+		client := NewClient(token, nil)
+		repo := NewGitHubRepository(client)
+		ctx := context.Background()
+
+		testURL, err := url.Parse(testServer.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.BaseURL = testURL
+		if !strings.HasSuffix(client.BaseURL.Path, "/") {
+			client.BaseURL.Path += "/"
+		}
+
+		_, err = repo.ListReviews(ctx, "owner", "repo", 123)
+		if err == nil {
+			t.Fatal("expect error occurred, however got nil")
+		}
+
+		var apiError *APIError
+		if !errors.As(err, &apiError) {
+			t.Fatalf("mismatch expect=%T, got=%T", &APIError{}, err)
+		}
+		if apiError.StatusCode != http.StatusInternalServerError {
+			t.Errorf("mismatch expect=%d, got=%d", apiError.StatusCode, http.StatusInternalServerError)
+		}
+	})
+}
+
+func TestGitHubRepository_GetPullRequestSize(t *testing.T) {
+	t.Parallel()
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		wantURL := "/repos/owner/repo/pulls/123"
+		if wantURL != req.URL.Path {
+			t.Errorf("mismatch want=%v, got=%s", wantURL, req.URL.Path)
+		}
+
+		respBody, err := json.Marshal(github.PullRequest{
+			Number:       github.Int(123),
+			Additions:    github.Int(10),
+			Deletions:    github.Int(2),
+			ChangedFiles: github.Int(3),
+			Comments:     github.Int(4),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(respBody); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer testServer.Close()
+
+	client := NewClient("token", nil)
+	repo := NewGitHubRepository(client)
+
+	testURL, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = testURL
+	if !strings.HasSuffix(client.BaseURL.Path, "/") {
+		client.BaseURL.Path += "/"
+	}
+
+	want := &model.PullRequestSize{
+		Additions:    github.Int(10),
+		Deletions:    github.Int(2),
+		ChangedFiles: github.Int(3),
+		Comments:     github.Int(4),
+	}
+	got, err := repo.GetPullRequestSize(context.Background(), "owner", "repo", 123)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_toDomainModelReview(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 2, 24, 12, 34, 56, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		review *github.PullRequestReview
+		want   *model.Review
+	}{
+		{
+			name:   "convert empty review",
+			review: &github.PullRequestReview{},
+			want:   &model.Review{},
+		},
+		{
+			name: "convert review",
+			review: &github.PullRequestReview{
+				User:        &github.User{Login: github.String("reviewer"), Type: github.String("Bot")},
+				State:       github.String("APPROVED"),
+				SubmittedAt: &github.Timestamp{Time: now},
+			},
+			want: &model.Review{
+				User:        &model.User{Name: github.String("reviewer"), Bot: true},
+				State:       github.String("APPROVED"),
+				SubmittedAt: &model.Timestamp{Time: now},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := toDomainModelReview(tt.review)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGitHubRepository_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	const apiURL = "/repos/owner/repo/pulls/123/reviews"
+
+	// newRateLimitTestServer return test server that returns limited response
+	// until the request count exceeds limitedCount.
+	newRateLimitTestServer := func(t *testing.T, limitedCount int32, limited func(w http.ResponseWriter)) (*httptest.Server, *int32) {
+		t.Helper()
+
+		var count int32
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			wantURL := apiURL
+			if wantURL != req.URL.Path {
+				t.Errorf("mismatch want=%v, got=%s", wantURL, req.URL.Path)
+			}
+
+			if atomic.AddInt32(&count, 1) <= limitedCount {
+				limited(w)
+				return
+			}
+
+			respBody, err := json.Marshal([]github.PullRequestReview{
+				{State: github.String("APPROVED")},
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			w.WriteHeader(http.StatusOK)
+			if _, err := w.Write(respBody); err != nil {
+				t.Error(err)
+			}
+		}))
+		return testServer, &count
+	}
+
+	newTestRepository := func(t *testing.T, testServer *httptest.Server, policy *RetryPolicy) *GitHubRepository {
+		t.Helper()
+
+		client := NewClient("token", policy)
+		testURL, err := url.Parse(testServer.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.BaseURL = testURL
+		if !strings.HasSuffix(client.BaseURL.Path, "/") {
+			client.BaseURL.Path += "/"
+		}
+		return &GitHubRepository{client: client}
+	}
+
+	primaryRateLimit := func(reset time.Time) func(w http.ResponseWriter) {
+		return func(w http.ResponseWriter) {
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			if _, err := w.Write([]byte(`{"message":"API rate limit exceeded"}`)); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+
+	t.Run("Wait until primary rate limit is reset and retry", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, count := newRateLimitTestServer(t, 2, primaryRateLimit(time.Now().Add(-time.Second)))
+		defer testServer.Close()
+
+		repo := newTestRepository(t, testServer, &RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxWait: time.Minute})
+		got, err := repo.ListReviews(context.Background(), "owner", "repo", 123)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 {
+			t.Errorf("mismatch want=1, got=%d", len(got))
+		}
+		if c := atomic.LoadInt32(count); c != 3 {
+			t.Errorf("mismatch request count want=3, got=%d", c)
+		}
+	})
+
+	t.Run("Wait Retry-After seconds at secondary rate limit and retry", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, count := newRateLimitTestServer(t, 1, func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			if _, err := w.Write([]byte(`{"message":"You have exceeded a secondary rate limit"}`)); err != nil {
+				t.Error(err)
+			}
+		})
+		defer testServer.Close()
+
+		repo := newTestRepository(t, testServer, &RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxWait: time.Minute})
+		if _, err := repo.ListReviews(context.Background(), "owner", "repo", 123); err != nil {
+			t.Fatal(err)
+		}
+		if c := atomic.LoadInt32(count); c != 2 {
+			t.Errorf("mismatch request count want=2, got=%d", c)
+		}
+	})
+
+	t.Run("Back off exponentially without rate limit headers", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, count := newRateLimitTestServer(t, 2, func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+		defer testServer.Close()
+
+		repo := newTestRepository(t, testServer, &RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxWait: time.Minute})
+		if _, err := repo.ListReviews(context.Background(), "owner", "repo", 123); err != nil {
+			t.Fatal(err)
+		}
+		if c := atomic.LoadInt32(count); c != 3 {
+			t.Errorf("mismatch request count want=3, got=%d", c)
+		}
+	})
+
+	t.Run("Give up when retry count exceeds MaxRetries", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, count := newRateLimitTestServer(t, 100, primaryRateLimit(time.Now().Add(-time.Second)))
+		defer testServer.Close()
+
+		repo := newTestRepository(t, testServer, &RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxWait: time.Minute})
+		_, err := repo.ListReviews(context.Background(), "owner", "repo", 123)
+
+		var apiError *APIError
+		if !errors.As(err, &apiError) {
+			t.Fatalf("mismatch expect=%T, got=%T", &APIError{}, err)
+		}
+		if apiError.StatusCode != http.StatusForbidden {
+			t.Errorf("mismatch expect=%d, got=%d", http.StatusForbidden, apiError.StatusCode)
+		}
+		if c := atomic.LoadInt32(count); c != 3 {
+			t.Errorf("mismatch request count want=3, got=%d", c)
+		}
+	})
+
+	t.Run("Give up when reset time is later than MaxWait", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, count := newRateLimitTestServer(t, 100, primaryRateLimit(time.Now().Add(2*time.Hour)))
+		defer testServer.Close()
+
+		repo := newTestRepository(t, testServer, &RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxWait: time.Minute})
+		_, err := repo.ListReviews(context.Background(), "owner", "repo", 123)
+
+		var apiError *APIError
+		if !errors.As(err, &apiError) {
+			t.Fatalf("mismatch expect=%T, got=%T", &APIError{}, err)
+		}
+		if c := atomic.LoadInt32(count); c != 1 {
+			t.Errorf("mismatch request count want=1, got=%d", c)
+		}
+	})
+
+	t.Run("Do not retry 403 that is not rate limit", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, count := newRateLimitTestServer(t, 100, func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusForbidden)
+			if _, err := w.Write([]byte(`{"message":"Resource not accessible by integration"}`)); err != nil {
+				t.Error(err)
+			}
+		})
+		defer testServer.Close()
+
+		repo := newTestRepository(t, testServer, &RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxWait: time.Minute})
+		if _, err := repo.ListReviews(context.Background(), "owner", "repo", 123); err == nil {
+			t.Fatal("expect error occurred, however got nil")
+		}
+		if c := atomic.LoadInt32(count); c != 1 {
+			t.Errorf("mismatch request count want=1, got=%d", c)
+		}
+	})
+
+	t.Run("Stop waiting when context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, _ := newRateLimitTestServer(t, 100, primaryRateLimit(time.Now().Add(30*time.Second)))
+		defer testServer.Close()
+
+		repo := newTestRepository(t, testServer, &RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxWait: time.Minute})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := repo.ListReviews(ctx, "owner", "repo", 123)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("mismatch want=%v, got=%v", context.DeadlineExceeded, err)
+		}
+	})
+}
+
+func TestGitHubRepository_ListPullRequestsUpdatedSince(t *testing.T) {
+	t.Parallel()
+
+	const apiURL = "/repos/owner/repo/pulls"
+	since := time.Date(2023, 2, 24, 0, 0, 0, 0, time.UTC)
+
+	var requestCount int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+
+		wantURL := apiURL
+		if wantURL != req.URL.Path {
+			t.Errorf("mismatch want=%v, got=%s", wantURL, req.URL.Path)
+		}
+		if got := req.URL.Query().Get("sort"); got != "updated" {
+			t.Errorf("mismatch want=updated, got=%s", got)
+		}
+		if got := req.URL.Query().Get("direction"); got != "desc" {
+			t.Errorf("mismatch want=desc, got=%s", got)
+		}
+
+		respBody, err := json.Marshal([]github.PullRequest{
+			{Number: github.Int(3), UpdatedAt: &github.Timestamp{Time: since.Add(2 * time.Hour)}},
+			{Number: github.Int(1), UpdatedAt: &github.Timestamp{Time: since.Add(time.Hour)}},
+			{Number: github.Int(2), UpdatedAt: &github.Timestamp{Time: since.Add(-time.Hour)}},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		// There is next page, but it must not be requested.
+		w.Header().Set("Link", `<`+"http://"+req.Host+apiURL+`?page=2>; rel="next"`)
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(respBody); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer testServer.Close()
+
+	client := NewClient("token", nil)
+	repo := NewGitHubRepository(client)
+	testURL, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = testURL
+	if !strings.HasSuffix(client.BaseURL.Path, "/") {
+		client.BaseURL.Path += "/"
+	}
+
+	got, err := repo.ListPullRequests(context.Background(), "owner", "repo", &repository.ListPullRequestsOptions{UpdatedSince: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*model.PullRequest{
+		{Number: github.Int(3), UpdatedAt: &model.Timestamp{Time: since.Add(2 * time.Hour)}},
+		{Number: github.Int(1), UpdatedAt: &model.Timestamp{Time: since.Add(time.Hour)}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if c := atomic.LoadInt32(&requestCount); c != 1 {
+		t.Errorf("mismatch request count want=1, got=%d", c)
+	}
+}
+
+func TestNewEnterpriseClient(t *testing.T) {
+	t.Parallel()
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/owner/repo/pulls" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer enterprise_token" {
+			t.Errorf("unexpected authorization header: %s", got)
+		}
+
+		respBody, err := json.Marshal([]github.PullRequest{{Number: github.Int(1)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(respBody); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	t.Cleanup(testServer.Close)
+
+	tests := []struct {
+		name   string
+		apiURL string
+	}{
+		{name: "Host only", apiURL: testServer.URL},
+		{name: "API path", apiURL: testServer.URL + "/api/v3"},
+		{name: "API path with trailing slash", apiURL: testServer.URL + "/api/v3/"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := NewEnterpriseClient(model.Token("enterprise_token"), nil, tt.apiURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := testServer.URL + "/api/v3/"; client.BaseURL.String() != want {
+				t.Errorf("mismatch want=%s, got=%s", want, client.BaseURL.String())
+			}
+			if want := testServer.URL + "/api/uploads/"; client.UploadURL.String() != want {
+				t.Errorf("mismatch want=%s, got=%s", want, client.UploadURL.String())
+			}
+
+			got, err := NewGitHubRepository(client).ListPullRequests(context.Background(), "owner", "repo", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 1 || *got[0].Number != 1 {
+				t.Errorf("unexpected pull requests: %+v", got)
+			}
+		})
+	}
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := NewEnterpriseClient(model.Token("enterprise_token"), nil, "https://github.example.com/%zz"); !errors.Is(err, ErrInvalidAPIURL) {
+			t.Errorf("mismatch want=%v, got=%v", ErrInvalidAPIURL, err)
+		}
+	})
+}
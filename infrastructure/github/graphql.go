@@ -0,0 +1,454 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+)
+
+// ghostLogin is login of the user that GitHub shows in place of deleted accounts.
+const ghostLogin = "ghost"
+
+// graphQLPageSize is number of pull requests fetched by one GraphQL query.
+const graphQLPageSize = 50
+
+// pullRequestsQuery fetch pull requests with their first commit and reviews.
+// Commits of pull request are ordered from oldest to newest. If a pull request
+// has more than 100 reviews, they are fetched by REST API.
+const pullRequestsQuery = `query($owner: String!, $name: String!, $first: Int!, $after: String, $orderBy: IssueOrderField!) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(first: $first, after: $after, orderBy: {field: $orderBy, direction: DESC}) {
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+      nodes {
+        databaseId
+        number
+        state
+        title
+        createdAt
+        updatedAt
+        closedAt
+        mergedAt
+        additions
+        deletions
+        changedFiles
+        author {
+          __typename
+          login
+        }
+        comments {
+          totalCount
+        }
+        labels(first: 100) {
+          nodes {
+            name
+          }
+        }
+        commits(first: 1) {
+          totalCount
+          nodes {
+            commit {
+              committedDate
+              author {
+                name
+              }
+              committer {
+                name
+              }
+            }
+          }
+        }
+        reviews(first: 100) {
+          totalCount
+          nodes {
+            state
+            submittedAt
+            author {
+              __typename
+              login
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// GraphQLRepository is repository.GitHubRepository that uses GitHub GraphQL API.
+// ListPullRequests fetches pull requests with their first commit and reviews in bulk,
+// and GetFirstCommit and ListReviews return the fetched data without API call.
+// Data that GraphQL API does not provide is fetched by REST API.
+type GraphQLRepository struct {
+	client   *Client
+	rest     repository.GitHubRepository
+	endpoint string
+
+	mu sync.Mutex
+	// firstCommits is prefetched first commits. nil means the PR has no commit.
+	firstCommits map[string]*model.Commit
+	reviews      map[string][]*model.Review
+}
+
+// NewGraphQLRepository initialize repository.GitHubRepository that uses GitHub GraphQL API.
+func NewGraphQLRepository(client *Client) repository.GitHubRepository {
+	return &GraphQLRepository{
+		client:       client,
+		rest:         NewGitHubRepository(client),
+		endpoint:     graphQLEndpoint(client),
+		firstCommits: map[string]*model.Commit{},
+		reviews:      map[string][]*model.Review{},
+	}
+}
+
+// graphQLEndpoint return GraphQL API endpoint of the client.
+// github.com serves GraphQL API at /graphql, and GitHub Enterprise Server serves it at /api/graphql.
+func graphQLEndpoint(client *Client) string {
+	base := client.BaseURL.String()
+	if strings.HasSuffix(base, "/api/v3/") {
+		return strings.TrimSuffix(base, "v3/") + "graphql"
+	}
+	return base + "graphql"
+}
+
+// prKey return key of pull request for prefetched data.
+func prKey(owner, repo string, number int) string {
+	return fmt.Sprintf("%s/%s#%d", owner, repo, number)
+}
+
+// ListRepositories return List the repositories by REST API.
+func (g *GraphQLRepository) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	return g.rest.ListRepositories(ctx, org)
+}
+
+// ListPullRequests return List the pull requests. The first commit and reviews of
+// each pull request are fetched at the same time.
+// If opts.UpdatedSince is set, pull requests are listed in order of update time
+// and paging stops at the first pull request updated before opts.UpdatedSince.
+func (g *GraphQLRepository) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	if opts == nil {
+		opts = &repository.ListPullRequestsOptions{}
+	}
+
+	orderBy := "CREATED_AT"
+	if !opts.UpdatedSince.IsZero() {
+		orderBy = "UPDATED_AT"
+	}
+
+	pullReqs := make([]*model.PullRequest, 0)
+	var after *string
+	for {
+		data := &pullRequestsData{}
+		if err := g.query(ctx, pullRequestsQuery, map[string]interface{}{
+			"owner":   owner,
+			"name":    repo,
+			"first":   graphQLPageSize,
+			"after":   after,
+			"orderBy": orderBy,
+		}, data); err != nil {
+			return nil, err
+		}
+		if data.Repository == nil {
+			return nil, &APIError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("repository %s/%s is not found", owner, repo)}
+		}
+
+		conn := data.Repository.PullRequests
+		reachedOldPR := false
+		for _, v := range conn.Nodes {
+			if !opts.UpdatedSince.IsZero() && v.UpdatedAt != nil && v.UpdatedAt.Before(opts.UpdatedSince) {
+				reachedOldPR = true
+				break
+			}
+			g.store(owner, repo, v)
+			pullReqs = append(pullReqs, v.toDomainModelPR())
+		}
+
+		if reachedOldPR || !conn.PageInfo.HasNextPage {
+			break
+		}
+		after = &conn.PageInfo.EndCursor
+	}
+
+	if len(pullReqs) == 0 && opts.UpdatedSince.IsZero() {
+		return nil, ErrNoPullRequest
+	}
+
+	return pullReqs, nil
+}
+
+// store keep the first commit and reviews of the pull request.
+func (g *GraphQLRepository) store(owner, repo string, pr *graphQLPullRequest) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := prKey(owner, repo, pr.Number)
+	switch {
+	case pr.Commits.TotalCount == 0:
+		g.firstCommits[key] = nil
+	case len(pr.Commits.Nodes) != 0 && pr.Commits.Nodes[0].Commit != nil:
+		g.firstCommits[key] = pr.Commits.Nodes[0].Commit.toDomainModelCommit()
+	}
+	if pr.Reviews.TotalCount <= len(pr.Reviews.Nodes) {
+		reviews := make([]*model.Review, 0, len(pr.Reviews.Nodes))
+		for _, v := range pr.Reviews.Nodes {
+			reviews = append(reviews, v.toDomainModelReview())
+		}
+		g.reviews[key] = reviews
+	}
+}
+
+// ListCommitsInPR return List the commits in the PR by REST API.
+func (g *GraphQLRepository) ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error) {
+	return g.rest.ListCommitsInPR(ctx, owner, repo, number)
+}
+
+// GetFirstCommit return the first commit in the PR.
+// If the commit is not fetched by ListPullRequests, it is fetched by REST API.
+func (g *GraphQLRepository) GetFirstCommit(ctx context.Context, owner, repository string, number int) (*model.Commit, error) {
+	g.mu.Lock()
+	commit, ok := g.firstCommits[prKey(owner, repository, number)]
+	g.mu.Unlock()
+	if !ok {
+		return g.rest.GetFirstCommit(ctx, owner, repository, number)
+	}
+	if commit == nil {
+		return nil, ErrNoCommit
+	}
+	return commit, nil
+}
+
+// ListReviews return List the reviews in the PR.
+// If reviews are not fetched by ListPullRequests, they are fetched by REST API.
+func (g *GraphQLRepository) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	g.mu.Lock()
+	reviews, ok := g.reviews[prKey(owner, repo, number)]
+	g.mu.Unlock()
+	if ok {
+		return reviews, nil
+	}
+	return g.rest.ListReviews(ctx, owner, repo, number)
+}
+
+// GetPullRequestSize return size of the PR by REST API.
+// PRs listed by ListPullRequests already have their size.
+func (g *GraphQLRepository) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	return g.rest.GetPullRequestSize(ctx, owner, repo, number)
+}
+
+// graphQLRequest is request body of GraphQL API.
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// graphQLResponse is response body of GraphQL API.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// query send GraphQL query and decode data to v.
+func (g *GraphQLRepository) query(ctx context.Context, query string, variables map[string]interface{}, v interface{}) error {
+	body, err := json.Marshal(&graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("failed to encode GraphQL query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create GraphQL request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Client.Client().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request list: %w", err)
+	}
+	defer resp.Body.Close() //nolint
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read GraphQL response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Message: "failed to get pull request list: " + string(respBody)}
+	}
+
+	result := &graphQLResponse{}
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	if len(result.Errors) != 0 {
+		messages := make([]string, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		statusCode := http.StatusBadRequest
+		if result.Errors[0].Type == "NOT_FOUND" {
+			statusCode = http.StatusNotFound
+		}
+		return &APIError{StatusCode: statusCode, Message: "failed to get pull request list: " + strings.Join(messages, ", ")}
+	}
+
+	if err := json.Unmarshal(result.Data, v); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	return nil
+}
+
+// pullRequestsData is data of pullRequestsQuery.
+//
+//nolint:tagliatelle // field names are defined by GitHub GraphQL API
+type pullRequestsData struct {
+	Repository *struct {
+		PullRequests struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []*graphQLPullRequest `json:"nodes"`
+		} `json:"pullRequests"`
+	} `json:"repository"`
+}
+
+// graphQLActor is user or bot in GraphQL API.
+//
+//nolint:tagliatelle // field names are defined by GitHub GraphQL API
+type graphQLActor struct {
+	Typename string `json:"__typename"`
+	Login    string `json:"login"`
+}
+
+// toDomainModelUser convert actor to user. GraphQL API returns null actor for
+// deleted accounts, so it is converted to "ghost" user as REST API does.
+func (a *graphQLActor) toDomainModelUser() *model.User {
+	if a == nil {
+		login := ghostLogin
+		return &model.User{Name: &login}
+	}
+	return &model.User{
+		Name: &a.Login,
+		Bot:  a.Typename == "Bot",
+	}
+}
+
+// graphQLPullRequest is pull request in GraphQL API.
+//
+//nolint:tagliatelle // field names are defined by GitHub GraphQL API
+type graphQLPullRequest struct {
+	DatabaseID   int64         `json:"databaseId"`
+	Number       int           `json:"number"`
+	State        string        `json:"state"`
+	Title        string        `json:"title"`
+	CreatedAt    *time.Time    `json:"createdAt"`
+	UpdatedAt    *time.Time    `json:"updatedAt"`
+	ClosedAt     *time.Time    `json:"closedAt"`
+	MergedAt     *time.Time    `json:"mergedAt"`
+	Additions    int           `json:"additions"`
+	Deletions    int           `json:"deletions"`
+	ChangedFiles int           `json:"changedFiles"`
+	Author       *graphQLActor `json:"author"`
+	Comments     struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"comments"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Commits struct {
+		TotalCount int `json:"totalCount"`
+		Nodes      []struct {
+			Commit *graphQLCommit `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+	Reviews struct {
+		TotalCount int              `json:"totalCount"`
+		Nodes      []*graphQLReview `json:"nodes"`
+	} `json:"reviews"`
+}
+
+// toDomainModelPR convert pull request in GraphQL API to *model.PullRequest.
+// GraphQL API state MERGED is converted to REST API state "closed".
+func (pr *graphQLPullRequest) toDomainModelPR() *model.PullRequest {
+	state := "closed"
+	if pr.State == "OPEN" {
+		state = "open"
+	}
+
+	var labels []string
+	for _, v := range pr.Labels.Nodes {
+		labels = append(labels, v.Name)
+	}
+
+	return &model.PullRequest{
+		ID:           &pr.DatabaseID,
+		Number:       &pr.Number,
+		State:        &state,
+		Title:        &pr.Title,
+		CreatedAt:    model.NewTimestamp(pr.CreatedAt),
+		UpdatedAt:    model.NewTimestamp(pr.UpdatedAt),
+		ClosedAt:     model.NewTimestamp(pr.ClosedAt),
+		MergedAt:     model.NewTimestamp(pr.MergedAt),
+		User:         pr.Author.toDomainModelUser(),
+		Comments:     &pr.Comments.TotalCount,
+		Additions:    &pr.Additions,
+		Deletions:    &pr.Deletions,
+		ChangedFiles: &pr.ChangedFiles,
+		Labels:       labels,
+	}
+}
+
+// graphQLCommit is git commit in GraphQL API.
+//
+//nolint:tagliatelle // field names are defined by GitHub GraphQL API
+type graphQLCommit struct {
+	CommittedDate *time.Time `json:"committedDate"`
+	Author        *struct {
+		Name string `json:"name"`
+	} `json:"author"`
+	Committer *struct {
+		Name string `json:"name"`
+	} `json:"committer"`
+}
+
+func (c *graphQLCommit) toDomainModelCommit() *model.Commit {
+	commit := &model.Commit{Date: model.NewTimestamp(c.CommittedDate)}
+	if c.Author != nil {
+		commit.Author = &model.User{Name: &c.Author.Name}
+	}
+	if c.Committer != nil {
+		commit.Committer = &model.User{Name: &c.Committer.Name}
+	}
+	return commit
+}
+
+// graphQLReview is pull request review in GraphQL API.
+//
+//nolint:tagliatelle // field names are defined by GitHub GraphQL API
+type graphQLReview struct {
+	State       string        `json:"state"`
+	SubmittedAt *time.Time    `json:"submittedAt"`
+	Author      *graphQLActor `json:"author"`
+}
+
+func (r *graphQLReview) toDomainModelReview() *model.Review {
+	return &model.Review{
+		User:        r.Author.toDomainModelUser(),
+		State:       &r.State,
+		SubmittedAt: model.NewTimestamp(r.SubmittedAt),
+	}
+}
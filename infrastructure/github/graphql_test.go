@@ -0,0 +1,348 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v50/github"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+)
+
+// newGraphQLTestRepository return GraphQLRepository that sends requests to handler.
+func newGraphQLTestRepository(t *testing.T, handler http.Handler) *GraphQLRepository {
+	t.Helper()
+
+	testServer := httptest.NewServer(handler)
+	t.Cleanup(testServer.Close)
+
+	client := NewClient(model.Token("test_token"), nil)
+	testURL, err := url.Parse(testServer.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = testURL
+
+	return NewGraphQLRepository(client).(*GraphQLRepository) //nolint
+}
+
+// graphQLPRNode return pull request node of GraphQL API response.
+func graphQLPRNode(number int, updatedAt string, commits int, reviews int) map[string]interface{} {
+	commitNodes := []interface{}{}
+	if commits != 0 {
+		commitNodes = append(commitNodes, map[string]interface{}{
+			"commit": map[string]interface{}{
+				"committedDate": "2023-01-01T00:00:00Z",
+				"author":        map[string]interface{}{"name": "alice"},
+				"committer":     map[string]interface{}{"name": "alice"},
+			},
+		})
+	}
+	return map[string]interface{}{
+		"databaseId":   number * 100,
+		"number":       number,
+		"state":        "MERGED",
+		"title":        "title",
+		"createdAt":    "2023-01-01T01:00:00Z",
+		"updatedAt":    updatedAt,
+		"closedAt":     "2023-01-01T03:00:00Z",
+		"mergedAt":     "2023-01-01T03:00:00Z",
+		"additions":    10,
+		"deletions":    2,
+		"changedFiles": 1,
+		"author":       map[string]interface{}{"__typename": "User", "login": "alice"},
+		"comments":     map[string]interface{}{"totalCount": 3},
+		"labels":       map[string]interface{}{"nodes": []interface{}{map[string]interface{}{"name": "bug"}}},
+		"commits":      map[string]interface{}{"totalCount": commits, "nodes": commitNodes},
+		"reviews": map[string]interface{}{
+			"totalCount": reviews,
+			"nodes": []interface{}{
+				map[string]interface{}{
+					"state":       "APPROVED",
+					"submittedAt": "2023-01-01T02:00:00Z",
+					"author":      map[string]interface{}{"__typename": "Bot", "login": "review-bot"},
+				},
+			},
+		},
+	}
+}
+
+func writeGraphQLPage(t *testing.T, w http.ResponseWriter, nodes []interface{}, hasNextPage bool, endCursor string) {
+	t.Helper()
+
+	respBody, err := json.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"repository": map[string]interface{}{
+				"pullRequests": map[string]interface{}{
+					"pageInfo": map[string]interface{}{"hasNextPage": hasNextPage, "endCursor": endCursor},
+					"nodes":    nodes,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(respBody); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGraphQLRepository_ListPullRequests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Fetch PRs with first commit and reviews over pages", func(t *testing.T) {
+		t.Parallel()
+
+		var graphQLCalls, restCalls int32
+		mux := http.NewServeMux()
+		mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&graphQLCalls, 1)
+			req := &graphQLRequest{}
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				t.Fatal(err)
+			}
+			if req.Variables["owner"] != "owner" || req.Variables["name"] != "repo" || req.Variables["orderBy"] != "CREATED_AT" {
+				t.Errorf("unexpected variables: %v", req.Variables)
+			}
+
+			if req.Variables["after"] == nil {
+				writeGraphQLPage(t, w, []interface{}{graphQLPRNode(3, "2023-01-03T00:00:00Z", 2, 1)}, true, "cursor1")
+				return
+			}
+			if req.Variables["after"] != "cursor1" {
+				t.Errorf("unexpected cursor: %v", req.Variables["after"])
+			}
+			writeGraphQLPage(t, w, []interface{}{
+				graphQLPRNode(2, "2023-01-02T00:00:00Z", 0, 1),
+				graphQLPRNode(1, "2023-01-01T00:00:00Z", 1, 101),
+			}, false, "cursor2")
+		})
+		mux.HandleFunc("/repos/owner/repo/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&restCalls, 1)
+			if _, err := w.Write([]byte(`[{"state":"COMMENTED","submitted_at":"2023-01-01T02:00:00Z","user":{"login":"bob","type":"User"}}]`)); err != nil {
+				t.Fatal(err)
+			}
+		})
+		repo := newGraphQLTestRepository(t, mux)
+		ctx := context.Background()
+
+		prs, err := repo.ListPullRequests(ctx, "owner", "repo", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gotNumbers := make([]int, 0, len(prs))
+		for _, v := range prs {
+			gotNumbers = append(gotNumbers, *v.Number)
+		}
+		if diff := cmp.Diff([]int{3, 2, 1}, gotNumbers); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		wantPR := &model.PullRequest{
+			ID:           github.Int64(300),
+			Number:       github.Int(3),
+			State:        github.String("closed"),
+			Title:        github.String("title"),
+			CreatedAt:    &model.Timestamp{Time: time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC)},
+			UpdatedAt:    &model.Timestamp{Time: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)},
+			ClosedAt:     &model.Timestamp{Time: time.Date(2023, 1, 1, 3, 0, 0, 0, time.UTC)},
+			MergedAt:     &model.Timestamp{Time: time.Date(2023, 1, 1, 3, 0, 0, 0, time.UTC)},
+			User:         &model.User{Name: github.String("alice")},
+			Comments:     github.Int(3),
+			Additions:    github.Int(10),
+			Deletions:    github.Int(2),
+			ChangedFiles: github.Int(1),
+			Labels:       []string{"bug"},
+		}
+		if diff := cmp.Diff(wantPR, prs[0]); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		commit, err := repo.GetFirstCommit(ctx, "owner", "repo", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC); !commit.Date.Time.Equal(want) {
+			t.Errorf("mismatch want=%v, got=%v", want, commit.Date.Time)
+		}
+		if _, err := repo.GetFirstCommit(ctx, "owner", "repo", 2); !errors.Is(err, ErrNoCommit) {
+			t.Errorf("mismatch want=%v, got=%v", ErrNoCommit, err)
+		}
+
+		reviews, err := repo.ListReviews(ctx, "owner", "repo", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantReviews := []*model.Review{{
+			User:        &model.User{Name: github.String("review-bot"), Bot: true},
+			State:       github.String("APPROVED"),
+			SubmittedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC)},
+		}}
+		if diff := cmp.Diff(wantReviews, reviews); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		// PR #1 has more reviews than fetched by GraphQL API, so they are fetched by REST API.
+		reviews, err = repo.ListReviews(ctx, "owner", "repo", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(reviews) != 1 || *reviews[0].State != "COMMENTED" {
+			t.Errorf("unexpected reviews: %+v", reviews)
+		}
+
+		if calls := atomic.LoadInt32(&graphQLCalls); calls != 2 {
+			t.Errorf("mismatch GraphQL calls want=2, got=%d", calls)
+		}
+		if calls := atomic.LoadInt32(&restCalls); calls != 1 {
+			t.Errorf("mismatch REST calls want=1, got=%d", calls)
+		}
+	})
+
+	t.Run("Stop paging at PR updated before UpdatedSince", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int32
+		repo := newGraphQLTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			req := &graphQLRequest{}
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				t.Fatal(err)
+			}
+			if req.Variables["orderBy"] != "UPDATED_AT" {
+				t.Errorf("unexpected order: %v", req.Variables["orderBy"])
+			}
+			writeGraphQLPage(t, w, []interface{}{
+				graphQLPRNode(2, "2023-01-02T00:00:00Z", 1, 1),
+				graphQLPRNode(1, "2022-12-01T00:00:00Z", 1, 1),
+			}, true, "cursor1")
+		}))
+
+		prs, err := repo.ListPullRequests(context.Background(), "owner", "repo",
+			&repository.ListPullRequestsOptions{UpdatedSince: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(prs) != 1 || *prs[0].Number != 2 {
+			t.Errorf("unexpected pull requests: %+v", prs)
+		}
+		if got := atomic.LoadInt32(&calls); got != 1 {
+			t.Errorf("mismatch calls want=1, got=%d", got)
+		}
+	})
+
+	t.Run("Map null author of deleted account to ghost user", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newGraphQLTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			node := graphQLPRNode(1, "2023-01-01T00:00:00Z", 1, 1)
+			node["author"] = nil
+			node["reviews"] = map[string]interface{}{
+				"totalCount": 1,
+				"nodes": []interface{}{
+					map[string]interface{}{"state": "APPROVED", "submittedAt": "2023-01-01T02:00:00Z", "author": nil},
+				},
+			}
+			writeGraphQLPage(t, w, []interface{}{node}, false, "")
+		}))
+		ctx := context.Background()
+
+		prs, err := repo.ListPullRequests(ctx, "owner", "repo", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ghost := &model.User{Name: github.String("ghost")}
+		if diff := cmp.Diff(ghost, prs[0].User); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		reviews, err := repo.ListReviews(ctx, "owner", "repo", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ghost, reviews[0].User); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Return GraphQL error as APIError", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newGraphQLTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			if _, err := w.Write([]byte(`{"data":{"repository":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a Repository"}]}`)); err != nil {
+				t.Fatal(err)
+			}
+		}))
+
+		_, err := repo.ListPullRequests(context.Background(), "owner", "repo", nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("mismatch expect=%T, got=%T", &APIError{}, err)
+		}
+		if apiErr.StatusCode != http.StatusNotFound || !strings.Contains(apiErr.Message, "Could not resolve") {
+			t.Errorf("unexpected error: %v", apiErr)
+		}
+	})
+
+	t.Run("No pull request", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newGraphQLTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeGraphQLPage(t, w, []interface{}{}, false, "")
+		}))
+
+		if _, err := repo.ListPullRequests(context.Background(), "owner", "repo", nil); !errors.Is(err, ErrNoPullRequest) {
+			t.Errorf("mismatch want=%v, got=%v", ErrNoPullRequest, err)
+		}
+	})
+}
+
+func Test_graphQLEndpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		client func() (*Client, error)
+		want   string
+	}{
+		{
+			name:   "github.com",
+			client: func() (*Client, error) { return NewClient(model.Token("token"), nil), nil },
+			want:   "https://api.github.com/graphql",
+		},
+		{
+			name: "GitHub Enterprise Server",
+			client: func() (*Client, error) {
+				return NewEnterpriseClient(model.Token("token"), nil, "https://github.example.com")
+			},
+			want: "https://github.example.com/api/graphql",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := tt.client()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := graphQLEndpoint(client); got != tt.want {
+				t.Errorf("mismatch want=%s, got=%s", tt.want, got)
+			}
+		})
+	}
+}
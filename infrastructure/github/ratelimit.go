@@ -0,0 +1,27 @@
+package github
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/nao1215/leadtime/infrastructure/httpclient"
+)
+
+// RetryPolicy is policy for retrying requests that exceed the GitHub API rate limit.
+type RetryPolicy = httpclient.RetryPolicy
+
+// DefaultRetryPolicy return default retry policy.
+func DefaultRetryPolicy() *RetryPolicy {
+	return httpclient.DefaultRetryPolicy()
+}
+
+// NewRetryPolicy return retry policy with default backoff.
+func NewRetryPolicy(maxRetries int, maxWait time.Duration) *RetryPolicy {
+	return httpclient.NewRetryPolicy(maxRetries, maxWait)
+}
+
+// newRateLimitTransport return http.RoundTripper that retries requests limited by
+// the GitHub primary or secondary rate limit.
+func newRateLimitTransport(base http.RoundTripper, policy *RetryPolicy) http.RoundTripper {
+	return httpclient.NewRateLimitTransport(base, policy, "GitHub")
+}
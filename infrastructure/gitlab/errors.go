@@ -0,0 +1,15 @@
+package gitlab
+
+import (
+	"errors"
+
+	"github.com/nao1215/leadtime/infrastructure/httpclient"
+)
+
+// APIError is error for GitLab API.
+type APIError = httpclient.APIError
+
+var (
+	// ErrInvalidURL means "invalid GitLab URL"
+	ErrInvalidURL = errors.New("invalid GitLab URL")
+)
@@ -0,0 +1,405 @@
+// Package gitlab is http client for GitLab REST API.
+// Merge requests of GitLab are handled as pull requests.
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"github.com/nao1215/leadtime/infrastructure/httpclient"
+)
+
+// pagingLimit is number of items fetched by one request.
+const pagingLimit = 100
+
+// Client is http client for GitLab REST API.
+// BaseURL is base URL of GitLab REST API (e.g. https://gitlab.com/api/v4/).
+type Client struct {
+	*httpclient.Client
+}
+
+// NewClient return http client for GitLab REST API. baseURL is URL of GitLab
+// (e.g. https://gitlab.example.com). If baseURL does not end with /api/v4/, it is appended.
+func NewClient(token model.Token, baseURL string) (*Client, error) {
+	client, err := httpclient.NewClient("GitLab", baseURL, "api/v4/", func(req *http.Request) {
+		req.Header.Set("PRIVATE-TOKEN", token.String())
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidURL, err.Error())
+	}
+	return &Client{Client: client}, nil
+}
+
+// get send GET request to path and decode response body to v.
+// Return next page number. If there is no next page, return empty string.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v interface{}, message string) (string, error) {
+	u, err := url.Parse(c.BaseURL.String() + path)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", message, err)
+	}
+	u.RawQuery = query.Encode()
+
+	header, err := c.Get(ctx, u.String(), v, message)
+	if err != nil {
+		return "", err
+	}
+	return header.Get("X-Next-Page"), nil
+}
+
+// projectPath return API path of the project. GitLab project is identified by URL-encoded
+// "namespace/project". namespace may include subgroups (e.g. group/subgroup).
+func projectPath(owner, repo string) string {
+	return "projects/" + url.PathEscape(owner+"/"+repo)
+}
+
+// GitLabRepository is http client for GitLab REST API
+type GitLabRepository struct {
+	client *Client
+}
+
+// NewGitLabRepository initialize repository.SourceRepository for GitLab.
+func NewGitLabRepository(client *Client) repository.SourceRepository {
+	return &GitLabRepository{client: client}
+}
+
+// ListRepositories return List the projects of the group including subgroups.
+// If org is empty, return the projects that the authenticated user is a member of.
+func (g *GitLabRepository) ListRepositories(ctx context.Context, org string) ([]*model.Repository, error) {
+	path := "projects"
+	query := url.Values{"simple": {"true"}, "per_page": {strconv.Itoa(pagingLimit)}}
+	if org == "" {
+		query.Set("membership", "true")
+	} else {
+		path = "groups/" + url.PathEscape(org) + "/projects"
+		query.Set("include_subgroups", "true")
+	}
+
+	repoList := make([]*model.Repository, 0)
+	for {
+		projects := []*project{}
+		next, err := g.client.get(ctx, path, query, &projects, "failed to get project list")
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range projects {
+			repoList = append(repoList, v.toDomainModelRepository())
+		}
+		if next == "" {
+			break
+		}
+		query.Set("page", next)
+	}
+	return repoList, nil
+}
+
+// ListPullRequests return List the merge requests in order of creation date (newest first).
+// If opts.UpdatedSince is set, merge requests updated before it are not listed.
+func (g *GitLabRepository) ListPullRequests(ctx context.Context, owner, repo string, opts *repository.ListPullRequestsOptions) ([]*model.PullRequest, error) {
+	if opts == nil {
+		opts = &repository.ListPullRequestsOptions{}
+	}
+
+	query := url.Values{
+		"state":    {"all"},
+		"order_by": {"created_at"},
+		"sort":     {"desc"},
+		"per_page": {strconv.Itoa(pagingLimit)},
+	}
+	if !opts.UpdatedSince.IsZero() {
+		query.Set("order_by", "updated_at")
+		query.Set("updated_after", opts.UpdatedSince.UTC().Format(time.RFC3339))
+	}
+
+	pullReqs := make([]*model.PullRequest, 0)
+	for {
+		mrs := []*mergeRequest{}
+		next, err := g.client.get(ctx, projectPath(owner, repo)+"/merge_requests", query, &mrs, "failed to get merge request list")
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range mrs {
+			pullReqs = append(pullReqs, v.toDomainModelPR())
+		}
+		if next == "" {
+			break
+		}
+		query.Set("page", next)
+	}
+
+	if len(pullReqs) == 0 && opts.UpdatedSince.IsZero() {
+		return nil, repository.ErrNoPullRequest
+	}
+	return pullReqs, nil
+}
+
+// ListCommitsInPR return List the commits in the merge request.
+// order is oldest to newest.
+func (g *GitLabRepository) ListCommitsInPR(ctx context.Context, owner, repo string, number int) ([]*model.Commit, error) {
+	query := url.Values{"per_page": {strconv.Itoa(pagingLimit)}}
+	path := fmt.Sprintf("%s/merge_requests/%d/commits", projectPath(owner, repo), number)
+
+	commits := make([]*commit, 0)
+	for {
+		page := []*commit{}
+		next, err := g.client.get(ctx, path, query, &page, "failed to get commit list")
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, page...)
+		if next == "" {
+			break
+		}
+		query.Set("page", next)
+	}
+
+	if len(commits) == 0 {
+		return nil, repository.ErrNoCommit
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].CommittedDate.Before(commits[j].CommittedDate)
+	})
+	commitsInPR := make([]*model.Commit, 0, len(commits))
+	for _, v := range commits {
+		commitsInPR = append(commitsInPR, v.toDomainModelCommit())
+	}
+	return commitsInPR, nil
+}
+
+// GetFirstCommit return the first commit in the merge request.
+func (g *GitLabRepository) GetFirstCommit(ctx context.Context, owner, repository string, number int) (*model.Commit, error) {
+	list, err := g.ListCommitsInPR(ctx, owner, repository, number)
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// ListReviews return List the reviews in the merge request. GitLab does not have
+// review object like GitHub, so comments are converted to COMMENTED reviews and
+// approvals are converted to APPROVED reviews. order is oldest to newest.
+func (g *GitLabRepository) ListReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, error) {
+	query := url.Values{
+		"sort":     {"asc"},
+		"order_by": {"created_at"},
+		"per_page": {strconv.Itoa(pagingLimit)},
+	}
+	path := fmt.Sprintf("%s/merge_requests/%d/notes", projectPath(owner, repo), number)
+
+	reviews := make([]*model.Review, 0)
+	for {
+		notes := []*note{}
+		next, err := g.client.get(ctx, path, query, &notes, "failed to get note list")
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range notes {
+			if review := v.toDomainModelReview(); review != nil {
+				reviews = append(reviews, review)
+			}
+		}
+		if next == "" {
+			break
+		}
+		query.Set("page", next)
+	}
+	return reviews, nil
+}
+
+// GetPullRequestSize return number of added and deleted lines and changed files in the
+// merge request. GitLab does not provide number of lines, so they are counted from the diffs.
+func (g *GitLabRepository) GetPullRequestSize(ctx context.Context, owner, repo string, number int) (*model.PullRequestSize, error) {
+	query := url.Values{"per_page": {strconv.Itoa(pagingLimit)}}
+	path := fmt.Sprintf("%s/merge_requests/%d/diffs", projectPath(owner, repo), number)
+
+	additions, deletions, files := 0, 0, 0
+	for {
+		diffs := []*diff{}
+		next, err := g.client.get(ctx, path, query, &diffs, "failed to get diff list")
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range diffs {
+			a, d := v.lines()
+			additions += a
+			deletions += d
+			files++
+		}
+		if next == "" {
+			break
+		}
+		query.Set("page", next)
+	}
+
+	return &model.PullRequestSize{
+		Additions:    &additions,
+		Deletions:    &deletions,
+		ChangedFiles: &files,
+	}, nil
+}
+
+// user is user in GitLab REST API.
+type user struct {
+	Username string `json:"username"`
+	Bot      bool   `json:"bot"`
+}
+
+func (u *user) toDomainModelUser() *model.User {
+	if u == nil {
+		return nil
+	}
+	name := u.Username
+	return &model.User{
+		Name: &name,
+		Bot:  u.Bot,
+	}
+}
+
+// project is project in GitLab REST API.
+type project struct {
+	ID                int64  `json:"id"`
+	Path              string `json:"path"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	Description       string `json:"description"`
+}
+
+func (p *project) toDomainModelRepository() *model.Repository {
+	return &model.Repository{
+		ID:          &p.ID,
+		Name:        &p.Path,
+		FullName:    &p.PathWithNamespace,
+		Description: &p.Description,
+	}
+}
+
+// mergeRequest is merge request in GitLab REST API.
+type mergeRequest struct {
+	ID           int64      `json:"id"`
+	IID          int        `json:"iid"`
+	State        string     `json:"state"`
+	Title        string     `json:"title"`
+	CreatedAt    *time.Time `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+	ClosedAt     *time.Time `json:"closed_at"`
+	MergedAt     *time.Time `json:"merged_at"`
+	Author       *user      `json:"author"`
+	UserNotes    int        `json:"user_notes_count"`
+	ChangesCount string     `json:"changes_count"`
+	Labels       []string   `json:"labels"`
+}
+
+// toDomainModelPR convert merge request to *model.PullRequest. GitLab state
+// "opened" is converted to "open", and the other states are converted to "closed".
+// GitLab does not set closed_at of merged merge request, so merged_at is used as it.
+func (mr *mergeRequest) toDomainModelPR() *model.PullRequest {
+	state := "closed"
+	if mr.State == "opened" {
+		state = "open"
+	}
+
+	closedAt := mr.ClosedAt
+	if closedAt == nil && mr.MergedAt != nil {
+		closedAt = mr.MergedAt
+	}
+
+	var changedFiles *int
+	if n, err := strconv.Atoi(strings.TrimSuffix(mr.ChangesCount, "+")); err == nil {
+		changedFiles = &n
+	}
+
+	return &model.PullRequest{
+		ID:           &mr.ID,
+		Number:       &mr.IID,
+		State:        &state,
+		Title:        &mr.Title,
+		CreatedAt:    model.NewTimestamp(mr.CreatedAt),
+		UpdatedAt:    model.NewTimestamp(mr.UpdatedAt),
+		ClosedAt:     model.NewTimestamp(closedAt),
+		MergedAt:     model.NewTimestamp(mr.MergedAt),
+		User:         mr.Author.toDomainModelUser(),
+		Comments:     &mr.UserNotes,
+		ChangedFiles: changedFiles,
+		Labels:       labelNames(mr.Labels),
+	}
+}
+
+// labelNames return label names. If there is no label, return nil.
+func labelNames(labels []string) []string {
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// diff is diff of one file in GitLab REST API.
+type diff struct {
+	Diff string `json:"diff"`
+}
+
+// lines return number of added and deleted lines in the unified diff.
+// The diff of GitLab does not have "---" and "+++" file headers.
+func (d *diff) lines() (additions, deletions int) {
+	for _, line := range strings.Split(d.Diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// commit is git commit in GitLab REST API.
+type commit struct {
+	AuthorName    string    `json:"author_name"`
+	CommitterName string    `json:"committer_name"`
+	CommittedDate time.Time `json:"committed_date"`
+}
+
+func (c *commit) toDomainModelCommit() *model.Commit {
+	return &model.Commit{
+		Author:    &model.User{Name: &c.AuthorName},
+		Committer: &model.User{Name: &c.CommitterName},
+		Date:      &model.Timestamp{Time: c.CommittedDate},
+	}
+}
+
+// note is comment of merge request in GitLab REST API.
+type note struct {
+	Body      string     `json:"body"`
+	System    bool       `json:"system"`
+	CreatedAt *time.Time `json:"created_at"`
+	Author    *user      `json:"author"`
+}
+
+// toDomainModelReview convert note to *model.Review. User comment is COMMENTED,
+// approval is APPROVED and change request is CHANGES_REQUESTED.
+// Return nil for the other system notes (e.g. "added 1 commit").
+func (n *note) toDomainModelReview() *model.Review {
+	state := "COMMENTED"
+	if n.System {
+		switch n.Body {
+		case "approved this merge request":
+			state = "APPROVED"
+		case "requested changes":
+			state = "CHANGES_REQUESTED"
+		default:
+			return nil
+		}
+	}
+
+	return &model.Review{
+		User:        n.Author.toDomainModelUser(),
+		State:       &state,
+		SubmittedAt: model.NewTimestamp(n.CreatedAt),
+	}
+}
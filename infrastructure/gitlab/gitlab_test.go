@@ -0,0 +1,315 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/leadtime/domain/model"
+	"github.com/nao1215/leadtime/domain/repository"
+	"github.com/shogo82148/pointer"
+)
+
+// newTestRepository return GitLabRepository that sends requests to handler.
+func newTestRepository(t *testing.T, handler http.Handler) repository.SourceRepository {
+	t.Helper()
+
+	testServer := httptest.NewServer(handler)
+	t.Cleanup(testServer.Close)
+
+	client, err := NewClient(model.Token("test_token"), testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGitLabRepository(client)
+}
+
+func write(t *testing.T, w http.ResponseWriter, body string) {
+	t.Helper()
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+	}{
+		{name: "gitlab.com", baseURL: "https://gitlab.com", want: "https://gitlab.com/api/v4/"},
+		{name: "API path", baseURL: "https://gitlab.example.com/api/v4", want: "https://gitlab.example.com/api/v4/"},
+		{name: "Relative URL root", baseURL: "https://example.com/gitlab/", want: "https://example.com/gitlab/api/v4/"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := NewClient(model.Token("token"), tt.baseURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := client.BaseURL.String(); got != tt.want {
+				t.Errorf("mismatch want=%s, got=%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGitLabRepository_ListPullRequests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Get merge requests over pages", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.EscapedPath() != "/api/v4/projects/group%2Fsub%2Fproject/merge_requests" {
+				t.Errorf("unexpected path: %s", r.URL.EscapedPath())
+			}
+			if r.Header.Get("PRIVATE-TOKEN") != "test_token" {
+				t.Errorf("unexpected token: %s", r.Header.Get("PRIVATE-TOKEN"))
+			}
+			if r.URL.Query().Get("state") != "all" {
+				t.Errorf("unexpected state: %s", r.URL.Query().Get("state"))
+			}
+
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("X-Next-Page", "2")
+				write(t, w, `[{"id":12,"iid":2,"state":"merged","title":"mr2","created_at":"2023-01-02T00:00:00Z",
+"updated_at":"2023-01-02T03:00:00Z","merged_at":"2023-01-02T02:00:00Z","author":{"username":"alice"},
+"user_notes_count":1,"changes_count":"3","labels":["bug","backend"]}]`)
+				return
+			}
+			write(t, w, `[{"id":11,"iid":1,"state":"opened","title":"mr1","created_at":"2023-01-01T00:00:00Z",
+"updated_at":"2023-01-01T00:00:00Z","author":{"username":"renovate-bot","bot":true},"changes_count":"1000+","labels":[]}]`)
+		}))
+
+		got, err := repo.ListPullRequests(context.Background(), "group/sub", "project", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		merged := &model.Timestamp{Time: time.Date(2023, 1, 2, 2, 0, 0, 0, time.UTC)}
+		want := []*model.PullRequest{
+			{
+				ID:           pointer.Int64(12),
+				Number:       pointer.Int(2),
+				State:        pointer.String("closed"),
+				Title:        pointer.String("mr2"),
+				CreatedAt:    &model.Timestamp{Time: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+				UpdatedAt:    &model.Timestamp{Time: time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)},
+				ClosedAt:     merged,
+				MergedAt:     merged,
+				User:         &model.User{Name: pointer.String("alice")},
+				Comments:     pointer.Int(1),
+				ChangedFiles: pointer.Int(3),
+				Labels:       []string{"bug", "backend"},
+			},
+			{
+				ID:           pointer.Int64(11),
+				Number:       pointer.Int(1),
+				State:        pointer.String("open"),
+				Title:        pointer.String("mr1"),
+				CreatedAt:    &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+				UpdatedAt:    &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+				User:         &model.User{Name: pointer.String("renovate-bot"), Bot: true},
+				Comments:     pointer.Int(0),
+				ChangedFiles: pointer.Int(1000),
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Push UpdatedSince down to updated_after", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("updated_after"); got != "2023-01-01T00:00:00Z" {
+				t.Errorf("unexpected updated_after: %s", got)
+			}
+			if got := r.URL.Query().Get("order_by"); got != "updated_at" {
+				t.Errorf("unexpected order_by: %s", got)
+			}
+			write(t, w, `[]`)
+		}))
+
+		got, err := repo.ListPullRequests(context.Background(), "group", "project",
+			&repository.ListPullRequestsOptions{UpdatedSince: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 0 {
+			t.Errorf("unexpected merge requests: %+v", got)
+		}
+	})
+
+	t.Run("No merge request", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			write(t, w, `[]`)
+		}))
+
+		if _, err := repo.ListPullRequests(context.Background(), "group", "project", nil); !errors.Is(err, repository.ErrNoPullRequest) {
+			t.Errorf("mismatch want=%v, got=%v", repository.ErrNoPullRequest, err)
+		}
+	})
+
+	t.Run("Return status code 404 from GitLab", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			if _, err := w.Write([]byte(`{"message":"404 Project Not Found"}`)); err != nil {
+				t.Fatal(err)
+			}
+		}))
+
+		_, err := repo.ListPullRequests(context.Background(), "group", "project", nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("mismatch expect=%T, got=%T", &APIError{}, err)
+		}
+		if apiErr.StatusCode != http.StatusNotFound {
+			t.Errorf("mismatch expect=%d, got=%d", http.StatusNotFound, apiErr.StatusCode)
+		}
+	})
+}
+
+func TestGitLabRepository_GetFirstCommit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Return the oldest commit", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/merge_requests/3/commits" {
+				t.Errorf("unexpected path: %s", r.URL.EscapedPath())
+			}
+			write(t, w, `[
+{"author_name":"alice","committer_name":"alice","committed_date":"2023-01-03T00:00:00Z"},
+{"author_name":"bob","committer_name":"bob","committed_date":"2023-01-01T00:00:00Z"}]`)
+		}))
+
+		got, err := repo.GetFirstCommit(context.Background(), "group", "project", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &model.Commit{
+			Author:    &model.User{Name: pointer.String("bob")},
+			Committer: &model.User{Name: pointer.String("bob")},
+			Date:      &model.Timestamp{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("No commit", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			write(t, w, `[]`)
+		}))
+
+		if _, err := repo.GetFirstCommit(context.Background(), "group", "project", 3); !errors.Is(err, repository.ErrNoCommit) {
+			t.Errorf("mismatch want=%v, got=%v", repository.ErrNoCommit, err)
+		}
+	})
+}
+
+func TestGitLabRepository_ListReviews(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		write(t, w, `[
+{"body":"added 1 commit","system":true,"created_at":"2023-01-01T00:00:00Z","author":{"username":"alice"}},
+{"body":"LGTM?","system":false,"created_at":"2023-01-01T01:00:00Z","author":{"username":"bob"}},
+{"body":"approved this merge request","system":true,"created_at":"2023-01-01T02:00:00Z","author":{"username":"bob"}}]`)
+	}))
+
+	got, err := repo.ListReviews(context.Background(), "group", "project", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*model.Review{
+		{
+			User:        &model.User{Name: pointer.String("bob")},
+			State:       pointer.String("COMMENTED"),
+			SubmittedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC)},
+		},
+		{
+			User:        &model.User{Name: pointer.String("bob")},
+			State:       pointer.String("APPROVED"),
+			SubmittedAt: &model.Timestamp{Time: time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC)},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGitLabRepository_GetPullRequestSize(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/merge_requests/3/diffs" {
+			t.Errorf("unexpected path: %s", r.URL.EscapedPath())
+		}
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("X-Next-Page", "2")
+			write(t, w, `[{"diff":"@@ -1,2 +1,3 @@\n-a\n+b\n+c\n d\n"}]`)
+			return
+		}
+		write(t, w, `[{"diff":"@@ -0,0 +1 @@\n+new\n"},{"diff":""}]`)
+	}))
+
+	got, err := repo.GetPullRequestSize(context.Background(), "group", "project", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &model.PullRequestSize{
+		Additions:    pointer.Int(3),
+		Deletions:    pointer.Int(1),
+		ChangedFiles: pointer.Int(3),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGitLabRepository_ListRepositories(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/groups/group/projects" || r.URL.Query().Get("include_subgroups") != "true" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		write(t, w, `[{"id":1,"path":"project","path_with_namespace":"group/sub/project","description":"desc"}]`)
+	}))
+
+	got, err := repo.ListRepositories(context.Background(), "group")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*model.Repository{{
+		ID:          pointer.Int64(1),
+		Name:        pointer.String("project"),
+		FullName:    pointer.String("group/sub/project"),
+		Description: pointer.String("desc"),
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
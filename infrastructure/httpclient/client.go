@@ -0,0 +1,76 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client is http client for REST API. It authenticates each request and
+// retries requests exceeding the rate limit.
+type Client struct {
+	// BaseURL is base URL of REST API (e.g. https://gitlab.com/api/v4/).
+	BaseURL *url.URL
+	// service is name of the API in error messages (e.g. GitLab).
+	service string
+	// authorize set credentials to the request.
+	authorize  func(req *http.Request)
+	httpClient *http.Client
+}
+
+// NewClient return http client for REST API of service. If apiPath is not empty
+// and baseURL does not end with it, apiPath is appended (e.g. "api/v4/").
+// authorize set credentials to each request. If policy is nil, DefaultRetryPolicy is used.
+func NewClient(service, baseURL, apiPath string, authorize func(req *http.Request), policy *RetryPolicy) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	if apiPath != "" && !strings.HasSuffix(u.Path, "/"+apiPath) {
+		u.Path += apiPath
+	}
+
+	return &Client{
+		BaseURL:   u,
+		service:   service,
+		authorize: authorize,
+		httpClient: &http.Client{
+			Transport: NewRateLimitTransport(nil, policy, service),
+		},
+	}, nil
+}
+
+// Get send GET request to rawURL and decode JSON response body to v.
+// Return response header for paging. message is prefix of the error message.
+func (c *Client) Get(ctx context.Context, rawURL string, v interface{}, message string) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", message, err)
+	}
+	if c.authorize != nil {
+		c.authorize(req)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", message, err)
+	}
+	defer resp.Body.Close() //nolint
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body) //nolint
+		return nil, &APIError{Service: c.service, StatusCode: resp.StatusCode, Message: fmt.Sprintf("%s: %s", message, strings.TrimSpace(string(body)))}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, fmt.Errorf("%s: %w", message, err)
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,136 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		apiPath string
+		want    string
+	}{
+		{name: "Append API path", baseURL: "https://gitlab.example.com", apiPath: "api/v4/", want: "https://gitlab.example.com/api/v4/"},
+		{name: "Keep API path", baseURL: "https://gitlab.example.com/api/v4", apiPath: "api/v4/", want: "https://gitlab.example.com/api/v4/"},
+		{name: "No API path", baseURL: "https://api.bitbucket.org/2.0", want: "https://api.bitbucket.org/2.0/"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := NewClient("Test", tt.baseURL, tt.apiPath, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := client.BaseURL.String(); got != tt.want {
+				t.Errorf("mismatch want=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_Get(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxWait: time.Minute}
+	authorize := func(req *http.Request) {
+		req.Header.Set("PRIVATE-TOKEN", "test_token")
+	}
+
+	t.Run("Decode response with credentials", func(t *testing.T) {
+		t.Parallel()
+
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got := r.Header.Get("PRIVATE-TOKEN"); got != "test_token" {
+				t.Errorf("mismatch want=%v, got=%v", "test_token", got)
+			}
+			w.Header().Set("X-Next-Page", "2")
+			w.Write([]byte(`{"name":"leadtime"}`)) //nolint
+		}))
+		defer testServer.Close()
+
+		client, err := NewClient("Test", testServer.URL, "", authorize, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v := struct {
+			Name string `json:"name"`
+		}{}
+		header, err := client.Get(context.Background(), testServer.URL, &v, "failed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Name != "leadtime" || header.Get("X-Next-Page") != "2" {
+			t.Errorf("mismatch want=%v, got=%v", "leadtime and next page 2", v.Name+" and next page "+header.Get("X-Next-Page"))
+		}
+	})
+
+	t.Run("Retry request exceeding rate limit", func(t *testing.T) {
+		t.Parallel()
+
+		count := 0
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			count++
+			if count == 1 {
+				w.Header().Set("RateLimit-Remaining", "0")
+				w.Header().Set("RateLimit-Reset", strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10))
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			if count == 2 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`{}`)) //nolint
+		}))
+		defer testServer.Close()
+
+		client, err := NewClient("Test", testServer.URL, "", authorize, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v := struct{}{}
+		if _, err := client.Get(context.Background(), testServer.URL, &v, "failed"); err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Errorf("mismatch want=%v, got=%v", 3, count)
+		}
+	})
+
+	t.Run("Return APIError", func(t *testing.T) {
+		t.Parallel()
+
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"404 Not Found"}`)) //nolint
+		}))
+		defer testServer.Close()
+
+		client, err := NewClient("Test", testServer.URL, "", authorize, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v := struct{}{}
+		_, err = client.Get(context.Background(), testServer.URL, &v, "failed to get project")
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("mismatch expect=%T, got=%T", &APIError{}, err)
+		}
+		want := `Test API error: status code 404, message: failed to get project: {"message":"404 Not Found"}`
+		if apiErr.Error() != want {
+			t.Errorf("mismatch want=%v, got=%v", want, apiErr.Error())
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package httpclient
+
+import "fmt"
+
+// APIError is error for REST API.
+type APIError struct {
+	// Service is name of the API (e.g. GitLab).
+	Service string
+	// StatusCode is HTTP status code from the server.
+	StatusCode int
+	// Message is error message
+	Message string
+}
+
+// Error return string that represents a REST API error
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error: status code %d, message: %s", e.Service, e.StatusCode, e.Message)
+}
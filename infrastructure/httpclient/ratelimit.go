@@ -0,0 +1,200 @@
+// Package httpclient is http client shared by REST API clients of the sources.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const headerRetryAfter = "Retry-After"
+
+// rateLimitHeaders is pairs of remaining and reset headers of the primary rate limit.
+// GitHub uses X-RateLimit-*, GitLab uses RateLimit-*.
+var rateLimitHeaders = [][2]string{
+	{"X-RateLimit-Remaining", "X-RateLimit-Reset"},
+	{"RateLimit-Remaining", "RateLimit-Reset"},
+}
+
+// RetryPolicy is policy for retrying requests that exceed the API rate limit.
+type RetryPolicy struct {
+	// MaxRetries is maximum number of retries for one request. 0 means no retry.
+	MaxRetries int
+	// InitialBackoff is wait time before the first retry when the server does not tell
+	// when the rate limit is reset. The wait time doubles at each retry.
+	InitialBackoff time.Duration
+	// MaxWait is maximum wait time for one retry. If the server asks to wait longer,
+	// the request is not retried and the rate limit error is returned.
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy return default retry policy.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     5,
+		InitialBackoff: time.Minute,
+		MaxWait:        time.Hour,
+	}
+}
+
+// NewRetryPolicy return retry policy with default backoff.
+func NewRetryPolicy(maxRetries int, maxWait time.Duration) *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MaxRetries = maxRetries
+	policy.MaxWait = maxWait
+	return policy
+}
+
+// rateLimitTransport is http.RoundTripper that waits and retries requests
+// limited by the primary or secondary rate limit.
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	policy *RetryPolicy
+	// service is name of the API in log messages (e.g. GitHub).
+	service string
+	now     func() time.Time
+}
+
+// NewRateLimitTransport return http.RoundTripper that retries rate limited requests.
+// service is name of the API in log messages (e.g. GitHub). If policy is nil,
+// DefaultRetryPolicy is used.
+func NewRateLimitTransport(base http.RoundTripper, policy *RetryPolicy, service string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+	return &rateLimitTransport{
+		base:    base,
+		policy:  policy,
+		service: service,
+		now:     time.Now,
+	}
+}
+
+// RoundTrip execute HTTP request. If the response says that the rate limit is
+// exceeded, RoundTrip sleeps until the rate limit is reset (or backs off
+// exponentially) and sends the request again.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		wait, limited := t.rateLimitWait(resp, attempt)
+		if !limited {
+			return resp, nil
+		}
+		if attempt >= t.policy.MaxRetries {
+			log.Warn(t.service+" API rate limit exceeded, give up retrying", "url", req.URL.Path, "retries", attempt)
+			return resp, nil
+		}
+		if wait > t.policy.MaxWait {
+			log.Warn(t.service+" API rate limit exceeded, wait time is too long", "url", req.URL.Path, "wait", wait, "max_wait", t.policy.MaxWait)
+			return resp, nil
+		}
+
+		if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+			log.Debug("failed to drain response body", "error", err)
+		}
+		if err := resp.Body.Close(); err != nil {
+			log.Debug("failed to close response body", "error", err)
+		}
+
+		log.Warn(t.service+" API rate limit exceeded, wait before retrying", "url", req.URL.Path, "wait", wait, "retry", attempt+1)
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rateLimitWait return wait time before retrying the request and whether the
+// response means rate limit exceeded or not.
+//
+// The wait time is decided in the following order:
+//  1. Retry-After header (secondary rate limit)
+//  2. (X-)RateLimit-Reset header when (X-)RateLimit-Remaining is 0 (primary rate limit)
+//  3. exponential backoff (secondary rate limit without Retry-After)
+func (t *rateLimitTransport) rateLimitWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if v := resp.Header.Get(headerRetryAfter); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	for _, h := range rateLimitHeaders {
+		if resp.Header.Get(h[0]) != "0" {
+			continue
+		}
+		if reset, err := strconv.ParseInt(resp.Header.Get(h[1]), 10, 64); err == nil {
+			wait := time.Unix(reset, 0).Sub(t.now())
+			if wait < 0 {
+				wait = 0
+			}
+			return wait, true
+		}
+	}
+
+	if resp.StatusCode == http.StatusForbidden && !mentionsRateLimit(resp) {
+		return 0, false
+	}
+
+	backoff := t.policy.InitialBackoff << attempt
+	if backoff <= 0 || backoff > t.policy.MaxWait {
+		backoff = t.policy.MaxWait
+	}
+	return backoff, true
+}
+
+// mentionsRateLimit check whether response body is rate limit error message or not.
+// GitHub returns 403 for both permission errors and secondary rate limits.
+// The response body is restored so that the caller can read it again.
+func mentionsRateLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false
+	}
+	if err := resp.Body.Close(); err != nil {
+		log.Debug("failed to close response body", "error", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return strings.Contains(strings.ToLower(string(body)), "rate limit")
+}
+
+// sleep wait for d. If ctx is done before d elapses, return ctx error.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}